@@ -1,7 +1,7 @@
 package app
 
 import (
-	//coll "agent/internal/collectors"
+	coll "agent/internal/collectors"
 	"agent/internal/config"
 	"agent/internal/models"
 	service "agent/internal/services"
@@ -55,12 +55,16 @@ func (a *App) Run(ctx context.Context, wg *sync.WaitGroup) {
 		a.server.Start(ctx)
 	}()
 
+	// Наблюдатели за событиями между циклами сбора
+	a.metricsService.Registry.StartWatchers(ctx, wg, a.metricsService.RecordEvents)
+
 	// Горутина 2: Сбор метрик
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 
-		ticker := time.NewTicker(a.cfg.PollInterval)
+		// Каждый коллектор запускается по своему интервалу; тик только проверяет, кому пора
+		ticker := time.NewTicker(coll.RegistryTick)
 		defer ticker.Stop()
 
 		for {
@@ -68,17 +72,14 @@ func (a *App) Run(ctx context.Context, wg *sync.WaitGroup) {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				// Сбор метрик от всех коллекторов
+				// Сбор метрик от коллекторов, которым пора, и последних результатов остальных
 				metrics := models.NewAgentMetrics(a.cfg.HostID)
-
-				for _, c := range a.metricsService.Collectors {
-					if err := c.Collect(&metrics); err != nil {
-						log.Printf("Collection error: %v", err)
-					}
+				if !a.metricsService.Registry.RunDue(ctx, metrics.Timestamp, &metrics) {
+					continue
 				}
 
 				// Отправка метрик в сервис для обработки
-				a.metricsService.ProcessMetrics(metrics)
+				a.metricsService.ProcessMetrics(&metrics)
 
 				// Отправка метрик в канал для HTTP-сервера
 				select {
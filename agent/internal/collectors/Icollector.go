@@ -2,21 +2,36 @@ package collectors
 
 import (
 	"agent/internal/models"
-)
-
-type CollectorType int
-
-const (
-	Docker  CollectorType = iota // iota = 0
-	Process                      // iota = 1
-	Network                      // iota = 2
-	System                       // iota = 3
+	"context"
 )
 
 // Collector определяет интерфейс для всех сборщиков метрик
 type Collector interface {
 	// Collect собирает метрики и записывает их в переданную структуру
 	Collect(metrics *models.AgentMetrics) error
-	// ChangeConfig изменяет конфигурацию коллектора
-	ChangeConfig(collType CollectorType, newconfig []string)
+}
+
+// Configurable - необязательный интерфейс коллекторов с типизированными настройками
+// (GET/PUT /config/collectors/:name)
+type Configurable interface {
+	// ConfigSchema возвращает JSON-схему настроек
+	ConfigSchema() *Schema
+	// Config возвращает текущие настройки
+	Config() interface{}
+	// SetConfig проверяет настройки в JSON по схеме и применяет их. Отсутствующие поля
+	// не меняются; при ошибке настройки остаются прежними
+	SetConfig(raw []byte) error
+}
+
+// ContextCollector - необязательный интерфейс коллекторов, которые прерывают сбор
+// по истечении срока контекста (запросы к Docker и другим внешним службам)
+type ContextCollector interface {
+	CollectContext(ctx context.Context, metrics *models.AgentMetrics) error
+}
+
+// Watcher - необязательный интерфейс коллекторов, которые отслеживают события
+// между циклами сбора (завершение процессов, события Docker и т.п.)
+type Watcher interface {
+	// Watch работает до отмены контекста и передает события в emit
+	Watch(ctx context.Context, emit func(events ...models.Event))
 }
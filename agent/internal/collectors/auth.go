@@ -0,0 +1,438 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUtmpPath - текущие сеансы пользователей
+	DefaultUtmpPath = "/var/run/utmp"
+	// DefaultWtmpPath - история входов
+	DefaultWtmpPath = "/var/log/wtmp"
+	// authStateFile - файл в каталоге состояния агента с позициями чтения журналов входа
+	authStateFile = "auth_state.json"
+	// maxFailedUsers - сколько имен пользователей указывается в событии о неудачных попытках
+	maxFailedUsers = 5
+	// maxFailedSources - сколько адресов с наибольшим числом неудачных попыток передается в метриках
+	maxFailedSources = 10
+	// journalTimeout ограничивает время одного запроса к journalctl
+	journalTimeout = 10 * time.Second
+)
+
+// authLogPaths - журналы аутентификации в Debian/Ubuntu и RHEL; используется первый существующий
+var authLogPaths = []string{"/var/log/auth.log", "/var/log/secure"}
+
+var (
+	// Accepted publickey for root from 10.0.0.1 port 52314 ssh2: RSA SHA256:...
+	sshAcceptedRe = regexp.MustCompile(`Accepted (\S+) for (\S+) from (\S+) port (\d+)`)
+	// Failed password for invalid user admin from 10.0.0.1 port 52314 ssh2
+	sshFailedRe = regexp.MustCompile(`Failed (\S+) for (?:invalid user )?(\S+) from (\S+) port (\d+)`)
+	// Invalid user admin from 10.0.0.1 port 52314
+	sshInvalidUserRe = regexp.MustCompile(`Invalid user (.*?) from (\S+)(?: port \d+)?$`)
+	// rsyslog сворачивает одинаковые строки: message repeated 5 times: [ Failed password for ...]
+	syslogRepeatedRe = regexp.MustCompile(`message repeated (\d+) times: \[ ?(.*)\]$`)
+)
+
+// AuthOptions задает источники сведений о входах
+type AuthOptions struct {
+	StateDir string
+	LogPath  string // Журнал аутентификации; пустой - auth.log или secure, а без них - журнал systemd
+	UtmpPath string
+	WtmpPath string
+}
+
+// AuthCollector сообщает текущие сеансы пользователей из utmp, входы из wtmp и результаты
+// входа по SSH из журнала аутентификации или журнала systemd. Неудачные попытки за интервал
+// сводятся в одно событие на адрес источника, чтобы перебор паролей не вытеснял остальные события.
+type AuthCollector struct {
+	logPath   string
+	utmpPath  string
+	wtmpPath  string
+	stateFile string
+	journal   bool
+
+	mu    sync.Mutex
+	tail  *logTail
+	state authState
+	since time.Time // Начало чтения журнала systemd, пока не получен курсор
+}
+
+// authState - сохраняемые позиции чтения
+type authState struct {
+	Log           *logOffset `json:"log,omitempty"`
+	Wtmp          *logOffset `json:"wtmp,omitempty"`
+	JournalCursor string     `json:"journal_cursor,omitempty"`
+}
+
+// sshTally - результаты входа по SSH за интервал
+type sshTally struct {
+	accepted int
+	invalid  int
+	events   []models.Event
+	sources  map[string]*sshSource
+}
+
+// sshSource - неудачные попытки с одного адреса
+type sshSource struct {
+	failed  int
+	invalid int
+	users   map[string]bool
+}
+
+// NewAuthCollector создает коллектор. Возвращает ошибку, если нет ни utmp, ни источника записей о входе по SSH
+func NewAuthCollector(opts AuthOptions) (*AuthCollector, error) {
+	c := &AuthCollector{
+		logPath:  opts.LogPath,
+		utmpPath: opts.UtmpPath,
+		wtmpPath: opts.WtmpPath,
+		since:    time.Now(),
+	}
+	if c.utmpPath == "" {
+		c.utmpPath = DefaultUtmpPath
+	}
+	if c.wtmpPath == "" {
+		c.wtmpPath = DefaultWtmpPath
+	}
+	if c.logPath == "" {
+		for _, p := range authLogPaths {
+			if _, err := os.Stat(p); err == nil {
+				c.logPath = p
+				break
+			}
+		}
+	}
+	if c.logPath == "" {
+		c.journal = journalAvailable()
+	}
+
+	_, utmpErr := os.Stat(c.utmpPath)
+	if c.logPath == "" && !c.journal && utmpErr != nil {
+		return nil, errors.New("neither utmp nor auth log is available")
+	}
+
+	if opts.StateDir != "" {
+		c.stateFile = filepath.Join(opts.StateDir, authStateFile)
+		c.loadState()
+	}
+	return c, nil
+}
+
+func (c *AuthCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	auth := &models.AuthMetrics{}
+	var errs []error
+
+	if entries, _, err := readUtmp(c.utmpPath, 0); err == nil {
+		for _, e := range entries {
+			if e.Login && e.User != "" {
+				auth.Sessions = append(auth.Sessions, models.UserSession{
+					User:      e.User,
+					TTY:       e.TTY,
+					Host:      e.Host,
+					PID:       e.PID,
+					LoginTime: e.Time,
+				})
+			}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, fmt.Errorf("utmp: %w", err))
+	}
+
+	logins, err := c.readWtmp()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("wtmp: %w", err))
+	}
+
+	tally := &sshTally{sources: make(map[string]*sshSource)}
+	switch {
+	case c.logPath != "":
+		if err := c.readAuthLog(tally); err != nil {
+			errs = append(errs, fmt.Errorf("auth log %s: %w", c.logPath, err))
+		}
+	case c.journal:
+		if err := c.readJournal(tally); err != nil {
+			errs = append(errs, fmt.Errorf("journal: %w", err))
+		}
+	}
+	c.saveState()
+
+	now := time.Now()
+	failedEvents := tally.failedEvents(now)
+	auth.SSHAccepted = tally.accepted
+	auth.SSHInvalidUsers = tally.invalid
+	for _, e := range failedEvents {
+		n, _ := strconv.Atoi(e.Attributes["count"])
+		auth.SSHFailed += n
+	}
+	auth.FailedSources = topFailedSources(failedEvents, maxFailedSources)
+
+	metrics.Auth = auth
+	metrics.Events = append(metrics.Events, logins...)
+	metrics.Events = append(metrics.Events, tally.events...)
+	metrics.Events = append(metrics.Events, failedEvents...)
+	return errors.Join(errs...)
+}
+
+// readWtmp возвращает события о входах, записанных в wtmp после предыдущего чтения.
+// При первом запуске чтение начинается с конца файла, после ротации - с начала нового файла
+func (c *AuthCollector) readWtmp() ([]models.Event, error) {
+	info, err := os.Stat(c.wtmpPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	id := fileID(info)
+	pos := c.state.Wtmp
+	switch {
+	case pos == nil || pos.Path != c.wtmpPath:
+		pos = &logOffset{Path: c.wtmpPath, ID: id, Offset: info.Size() - info.Size()%utmpRecordSize}
+	case pos.ID != id || info.Size() < pos.Offset:
+		pos = &logOffset{Path: c.wtmpPath, ID: id}
+	}
+	c.state.Wtmp = pos
+
+	entries, offset, err := readUtmp(c.wtmpPath, pos.Offset)
+	pos.Offset = offset
+
+	var events []models.Event
+	for _, e := range entries {
+		if !e.Login || e.User == "" {
+			continue
+		}
+		message := fmt.Sprintf("%s logged in on %s", e.User, e.TTY)
+		if e.Host != "" {
+			message += " from " + e.Host
+		}
+		events = append(events, models.Event{
+			Timestamp: e.Time,
+			Source:    "auth",
+			Type:      "login",
+			Object:    e.User,
+			Message:   message,
+			Attributes: map[string]string{
+				"tty":  e.TTY,
+				"host": e.Host,
+				"pid":  strconv.Itoa(e.PID),
+			},
+		})
+	}
+	return events, err
+}
+
+// readAuthLog дочитывает журнал аутентификации, учитывая ротацию
+func (c *AuthCollector) readAuthLog(tally *sshTally) error {
+	if c.tail == nil {
+		tail, err := openLogTail(c.logPath, c.state.Log)
+		if err != nil {
+			return err
+		}
+		c.tail = tail
+	} else {
+		c.tail.checkRotation()
+	}
+
+	var info models.LogFileInfo
+	err := c.tail.read(&info, func(line []byte) {
+		// В общем журнале пишут и другие службы (sudo, su, cron)
+		if s := string(line); strings.Contains(s, "sshd") {
+			tally.add(s)
+		}
+	})
+	c.state.Log = &logOffset{Path: c.tail.path, ID: c.tail.id, Offset: c.tail.offset}
+	return err
+}
+
+// readJournal читает сообщения sshd из журнала systemd после сохраненного курсора
+func (c *AuthCollector) readJournal(tally *sshTally) error {
+	args := []string{"--no-pager", "--quiet", "--output=cat", "--show-cursor", "_COMM=sshd", "_COMM=sshd-session"}
+	if c.state.JournalCursor != "" {
+		args = append(args, "--after-cursor="+c.state.JournalCursor)
+	} else {
+		args = append(args, "--since=@"+strconv.FormatInt(c.since.Unix(), 10))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), journalTimeout)
+	defer cancel()
+	start := time.Now()
+	out, err := exec.CommandContext(ctx, "journalctl", args...).Output()
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if cursor, ok := strings.CutPrefix(line, "-- cursor: "); ok {
+			c.state.JournalCursor = cursor
+			continue
+		}
+		if line != "" {
+			tally.add(line)
+		}
+	}
+	if c.state.JournalCursor == "" {
+		// Сообщений еще не было: в следующий раз читаем с момента этого запроса
+		c.since = start
+	}
+	return nil
+}
+
+// journalAvailable проверяет, что есть journalctl и файлы журнала systemd (в контейнерах их обычно нет)
+func journalAvailable() bool {
+	if _, err := exec.LookPath("journalctl"); err != nil {
+		return false
+	}
+	for _, dir := range []string{"/run/log/journal", "/var/log/journal"} {
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// add учитывает строку журнала sshd
+func (t *sshTally) add(line string) {
+	n := 1
+	if m := syslogRepeatedRe.FindStringSubmatch(line); m != nil {
+		n, _ = strconv.Atoi(m[1])
+		line = m[2]
+	}
+
+	if m := sshAcceptedRe.FindStringSubmatch(line); m != nil {
+		t.accepted += n
+		t.events = append(t.events, models.Event{
+			Source:  "auth",
+			Type:    "ssh_accepted",
+			Object:  m[2],
+			Message: fmt.Sprintf("SSH login of %s from %s (%s)", m[2], m[3], m[1]),
+			Attributes: map[string]string{
+				"ip":     m[3],
+				"port":   m[4],
+				"method": m[1],
+			},
+		})
+		return
+	}
+	if m := sshFailedRe.FindStringSubmatch(line); m != nil {
+		src := t.source(m[3])
+		src.failed += n
+		src.users[m[2]] = true
+		return
+	}
+	if m := sshInvalidUserRe.FindStringSubmatch(line); m != nil {
+		t.invalid += n
+		src := t.source(m[2])
+		src.invalid += n
+		src.users[m[1]] = true
+	}
+}
+
+func (t *sshTally) source(ip string) *sshSource {
+	src, ok := t.sources[ip]
+	if !ok {
+		src = &sshSource{users: make(map[string]bool)}
+		t.sources[ip] = src
+	}
+	return src
+}
+
+// failedEvents сводит неудачные попытки в события по адресам. При входе по паролю несуществующий
+// пользователь дает две строки (Invalid user и Failed password), а при входе только по ключу - одну
+// Invalid user, поэтому число попыток - большее из двух счетчиков
+func (t *sshTally) failedEvents(now time.Time) []models.Event {
+	ips := make([]string, 0, len(t.sources))
+	for ip := range t.sources {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	events := make([]models.Event, 0, len(ips))
+	for _, ip := range ips {
+		src := t.sources[ip]
+		count := max(src.failed, src.invalid)
+
+		users := make([]string, 0, len(src.users))
+		for u := range src.users {
+			users = append(users, u)
+		}
+		sort.Strings(users)
+		if len(users) > maxFailedUsers {
+			users = append(users[:maxFailedUsers], "...")
+		}
+
+		events = append(events, models.Event{
+			Timestamp: now,
+			Source:    "auth",
+			Type:      "ssh_failed",
+			Object:    ip,
+			Message:   fmt.Sprintf("%d failed SSH login attempts from %s", count, ip),
+			Attributes: map[string]string{
+				"count":         strconv.Itoa(count),
+				"invalid_users": strconv.Itoa(src.invalid),
+				"users":         strings.Join(users, ","),
+			},
+		})
+	}
+	return events
+}
+
+// topFailedSources возвращает адреса с наибольшим числом неудачных попыток
+func topFailedSources(events []models.Event, limit int) map[string]int {
+	if len(events) == 0 {
+		return nil
+	}
+	sorted := make([]models.Event, len(events))
+	copy(sorted, events)
+	count := func(e models.Event) int {
+		n, _ := strconv.Atoi(e.Attributes["count"])
+		return n
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return count(sorted[i]) > count(sorted[j]) })
+
+	sources := make(map[string]int, min(limit, len(sorted)))
+	for _, e := range sorted[:min(limit, len(sorted))] {
+		sources[e.Object] = count(e)
+	}
+	return sources
+}
+
+// loadState читает сохраненные позиции; отсутствие файла не считается ошибкой
+func (c *AuthCollector) loadState() {
+	data, err := os.ReadFile(c.stateFile)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &c.state); err != nil {
+		log.Printf("Ignoring corrupted %s: %v", c.stateFile, err)
+		c.state = authState{}
+	}
+}
+
+func (c *AuthCollector) saveState() {
+	if c.stateFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(c.state, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := WriteFileAtomic(c.stateFile, data, 0o600); err != nil {
+		log.Printf("Failed to save auth state: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSSHTally(t *testing.T) {
+	lines := []string{
+		"Mar  1 09:00:00 web1 sshd[4100]: Accepted publickey for deploy from 10.0.0.15 port 52314 ssh2: ED25519 SHA256:3q2+7w",
+		"Mar  1 09:00:05 web1 sshd[4110]: Accepted password for admin from 192.168.1.5 port 40022 ssh2",
+		// Пароль несуществующего пользователя: две строки на одну попытку
+		"Mar  1 09:01:00 web1 sshd[4120]: Invalid user oracle from 203.0.113.7 port 33012",
+		"Mar  1 09:01:02 web1 sshd[4120]: Failed password for invalid user oracle from 203.0.113.7 port 33012 ssh2",
+		"Mar  1 09:01:10 web1 sshd[4121]: Failed password for root from 203.0.113.7 port 33020 ssh2",
+		"Mar  1 09:01:20 web1 sshd[4121]: message repeated 3 times: [ Failed password for root from 203.0.113.7 port 33020 ssh2]",
+		// Вход только по ключу: одна строка Invalid user на попытку
+		"Mar  1 09:02:00 web1 sshd[4130]: Invalid user test from 198.51.100.20 port 51000",
+		"Mar  1 09:02:01 web1 sshd[4131]: Invalid user guest from 198.51.100.20",
+		"2024-03-01T09:03:00.123456+00:00 web1 sshd-session[4140]: Failed publickey for git from 2001:db8::5 port 60000 ssh2: RSA SHA256:abc",
+		"Mar  1 09:04:00 web1 sshd[4150]: Connection closed by 10.0.0.15 port 52314 [preauth]",
+		"Mar  1 09:04:01 web1 sshd[4150]: pam_unix(sshd:session): session opened for user deploy(uid=1000) by (uid=0)",
+	}
+	tally := &sshTally{sources: make(map[string]*sshSource)}
+	for _, l := range lines {
+		tally.add(l)
+	}
+
+	if tally.accepted != 2 || tally.invalid != 3 {
+		t.Errorf("accepted = %d, invalid = %d", tally.accepted, tally.invalid)
+	}
+	wantAccepted := []models.Event{
+		{Source: "auth", Type: "ssh_accepted", Object: "deploy", Message: "SSH login of deploy from 10.0.0.15 (publickey)",
+			Attributes: map[string]string{"ip": "10.0.0.15", "port": "52314", "method": "publickey"}},
+		{Source: "auth", Type: "ssh_accepted", Object: "admin", Message: "SSH login of admin from 192.168.1.5 (password)",
+			Attributes: map[string]string{"ip": "192.168.1.5", "port": "40022", "method": "password"}},
+	}
+	if !reflect.DeepEqual(tally.events, wantAccepted) {
+		t.Errorf("accepted events:\n got %+v\nwant %+v", tally.events, wantAccepted)
+	}
+
+	now := time.Date(2024, 3, 1, 9, 5, 0, 0, time.UTC)
+	failed := tally.failedEvents(now)
+	want := []models.Event{
+		{Timestamp: now, Source: "auth", Type: "ssh_failed", Object: "198.51.100.20", Message: "2 failed SSH login attempts from 198.51.100.20",
+			Attributes: map[string]string{"count": "2", "invalid_users": "2", "users": "guest,test"}},
+		{Timestamp: now, Source: "auth", Type: "ssh_failed", Object: "2001:db8::5", Message: "1 failed SSH login attempts from 2001:db8::5",
+			Attributes: map[string]string{"count": "1", "invalid_users": "0", "users": "git"}},
+		{Timestamp: now, Source: "auth", Type: "ssh_failed", Object: "203.0.113.7", Message: "5 failed SSH login attempts from 203.0.113.7",
+			Attributes: map[string]string{"count": "5", "invalid_users": "1", "users": "oracle,root"}},
+	}
+	if !reflect.DeepEqual(failed, want) {
+		t.Errorf("failed events:\n got %+v\nwant %+v", failed, want)
+	}
+
+	top := topFailedSources(failed, 2)
+	if !reflect.DeepEqual(top, map[string]int{"203.0.113.7": 5, "198.51.100.20": 2}) {
+		t.Errorf("top failed sources = %v", top)
+	}
+}
+
+func TestSSHTallyLimitsUsers(t *testing.T) {
+	tally := &sshTally{sources: make(map[string]*sshSource)}
+	for _, u := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		tally.add("sshd[1]: Failed password for invalid user " + u + " from 203.0.113.9 port 1 ssh2")
+	}
+	events := tally.failedEvents(time.Now())
+	if len(events) != 1 || events[0].Attributes["users"] != "a,b,c,d,e,..." {
+		t.Errorf("failed events = %+v", events)
+	}
+}
+
+func TestAuthCollector(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "auth.log")
+	utmpPath := filepath.Join(dir, "utmp")
+	wtmpPath := filepath.Join(dir, "wtmp")
+	session := utmpBytes(testUtmpRecord{typ: utmpUserProcess, pid: 4102, line: "pts/0", user: "deploy", host: "10.0.0.15", sec: 1709283600})
+	old := utmpBytes(testUtmpRecord{typ: utmpUserProcess, pid: 900, line: "tty1", user: "root", sec: 1709280000})
+	writeTestFiles(t, dir, map[string]string{
+		"auth.log": "Mar  1 08:00:00 web1 sshd[100]: Failed password for root from 203.0.113.7 port 1 ssh2\n",
+		"utmp":     string(session),
+		"wtmp":     string(old),
+	})
+
+	opts := AuthOptions{StateDir: dir, LogPath: logPath, UtmpPath: utmpPath, WtmpPath: wtmpPath}
+	c, err := NewAuthCollector(opts)
+	if err != nil {
+		t.Fatalf("NewAuthCollector: %v", err)
+	}
+
+	// Первый сбор: текущие сеансы есть, старые записи журнала и wtmp пропускаются
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if s := metrics.Auth.Sessions; len(s) != 1 || s[0].User != "deploy" || s[0].Host != "10.0.0.15" || s[0].PID != 4102 {
+		t.Errorf("sessions = %+v", s)
+	}
+	if len(metrics.Events) != 0 || metrics.Auth.SSHFailed != 0 {
+		t.Errorf("old records reported: %+v, failed %d", metrics.Events, metrics.Auth.SSHFailed)
+	}
+
+	appendFile(t, logPath, "Mar  1 09:00:00 web1 sshd[4100]: Accepted publickey for deploy from 10.0.0.15 port 52314 ssh2\n"+
+		"Mar  1 09:01:02 web1 sshd[4120]: Failed password for invalid user oracle from 203.0.113.7 port 33012 ssh2\n"+
+		"Mar  1 09:01:03 web1 sudo[4200]: deploy : TTY=pts/0 ; PWD=/home/deploy ; USER=root ; COMMAND=/usr/bin/id\n")
+	appendFile(t, wtmpPath, string(session))
+
+	metrics = models.AgentMetrics{}
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	var types []string
+	for _, e := range metrics.Events {
+		types = append(types, e.Type+":"+e.Object)
+	}
+	if !reflect.DeepEqual(types, []string{"login:deploy", "ssh_accepted:deploy", "ssh_failed:203.0.113.7"}) {
+		t.Errorf("events = %v", types)
+	}
+	if metrics.Auth.SSHAccepted != 1 || metrics.Auth.SSHFailed != 1 || metrics.Auth.FailedSources["203.0.113.7"] != 1 {
+		t.Errorf("auth metrics = %+v", metrics.Auth)
+	}
+
+	// После перезапуска чтение продолжается с сохраненных позиций
+	appendFile(t, logPath, "Mar  1 09:10:00 web1 sshd[4300]: Accepted password for admin from 192.168.1.5 port 40022 ssh2\n")
+	restarted, err := NewAuthCollector(opts)
+	if err != nil {
+		t.Fatalf("NewAuthCollector: %v", err)
+	}
+	metrics = models.AgentMetrics{}
+	if err := restarted.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(metrics.Events) != 1 || metrics.Events[0].Object != "admin" {
+		t.Errorf("events after restart = %+v", metrics.Events)
+	}
+}
+
+// appendFile дописывает data в конец файла
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
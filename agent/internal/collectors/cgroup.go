@@ -0,0 +1,340 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCgroupRoot - точка монтирования cgroup v2 по умолчанию
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// CgroupCollector читает показатели cgroup v2 напрямую из cgroupfs.
+// Так видны ресурсы systemd-сервисов и контейнеров без Docker API, в том числе троттлинг CPU.
+type CgroupCollector struct {
+	root string
+
+	mu      sync.Mutex
+	targets []models.CgroupTarget
+	prev    map[string]cgroupSample // счетчики с предыдущего сбора по пути cgroup
+}
+
+// cgroupSample хранит накопительные счетчики cgroup для расчета показателей за интервал
+type cgroupSample struct {
+	usageUsec     uint64
+	nrPeriods     uint64
+	nrThrottled   uint64
+	throttledUsec uint64
+	oomKill       uint64
+	ioRead        uint64
+	ioWrite       uint64
+	at            time.Time
+}
+
+// NewCgroupCollector создает коллектор; пустой root означает /sys/fs/cgroup
+func NewCgroupCollector(root string, targets []models.CgroupTarget) (*CgroupCollector, error) {
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+	if err := validateCgroupTargets(targets); err != nil {
+		return nil, err
+	}
+	return &CgroupCollector{
+		root:    root,
+		targets: targets,
+		prev:    make(map[string]cgroupSample),
+	}, nil
+}
+
+// validateCgroupTargets проверяет пути и шаблоны отслеживаемых cgroup
+func validateCgroupTargets(targets []models.CgroupTarget) error {
+	for _, target := range targets {
+		pattern := strings.Trim(target.Path, "/")
+		if pattern == "" {
+			return fmt.Errorf("cgroup path must not be empty")
+		}
+		for _, part := range strings.Split(pattern, "/") {
+			if part == ".." {
+				return fmt.Errorf("cgroup path %q must not leave the cgroup root", target.Path)
+			}
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid cgroup pattern %q: %w", target.Path, err)
+		}
+	}
+	return nil
+}
+
+// cgroupConfigSchema - схема настроек коллектора cgroups
+var cgroupConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"targets": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["path"],
+				"properties": {
+					"alias": {"type": "string", "description": "Отображаемое имя (только для пути без шаблона)"},
+					"path": {"type": "string", "minLength": 1, "description": "Путь относительно корня cgroupfs или шаблон (system.slice/*.service)"}
+				}
+			}
+		}
+	}
+}`)
+
+// CgroupConfig - настройки коллектора cgroups
+type CgroupConfig struct {
+	Targets []models.CgroupTarget `json:"targets"`
+}
+
+func (c *CgroupCollector) ConfigSchema() *Schema {
+	return cgroupConfigSchema
+}
+
+func (c *CgroupCollector) Config() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CgroupConfig{Targets: append([]models.CgroupTarget{}, c.targets...)}
+}
+
+func (c *CgroupCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(CgroupConfig)
+	if err := decodeConfig(cgroupConfigSchema, raw, &cfg); err != nil {
+		return err
+	}
+	if err := validateCgroupTargets(cfg.Targets); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.targets = cfg.Targets
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *CgroupCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	targets := c.targets
+	c.mu.Unlock()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(c.root, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("cgroup v2 is not mounted at %s: %w", c.root, err)
+	}
+
+	now := time.Now()
+	var infos []models.CgroupInfo
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, target := range targets {
+		// Ошибка в одном шаблоне не мешает собирать остальные cgroup
+		paths, err := c.resolve(target.Path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, rel := range paths {
+			if seen[rel] {
+				continue
+			}
+			seen[rel] = true
+
+			name := rel
+			if target.Alias != "" && len(paths) == 1 && !hasGlob(target.Path) {
+				name = target.Alias
+			}
+			infos = append(infos, c.readCgroup(name, rel, now))
+		}
+	}
+
+	// Забываем счетчики исчезнувших cgroup
+	c.mu.Lock()
+	for rel := range c.prev {
+		if !seen[rel] {
+			delete(c.prev, rel)
+		}
+	}
+	c.mu.Unlock()
+
+	metrics.Cgroups = infos
+	return errors.Join(errs...)
+}
+
+// resolve раскрывает шаблон пути в список существующих cgroup относительно корня
+func (c *CgroupCollector) resolve(pattern string) ([]string, error) {
+	pattern = strings.Trim(pattern, "/")
+	if !hasGlob(pattern) {
+		if info, err := os.Stat(filepath.Join(c.root, pattern)); err != nil || !info.IsDir() {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(c.root, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cgroup pattern %q: %w", pattern, err)
+	}
+	var paths []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err != nil || !info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(c.root, m)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, rel)
+	}
+	return paths, nil
+}
+
+// readCgroup читает файлы интерфейса cgroup. Файлы выключенных контроллеров отсутствуют,
+// соответствующие поля остаются нулевыми.
+func (c *CgroupCollector) readCgroup(name, rel string, now time.Time) models.CgroupInfo {
+	dir := filepath.Join(c.root, rel)
+	info := models.CgroupInfo{Name: name, Path: rel}
+
+	cpuStat := readKeyValueFile(filepath.Join(dir, "cpu.stat"))
+	info.CPUUsageSeconds = float64(cpuStat["usage_usec"]) / 1e6
+	info.NrPeriods = cpuStat["nr_periods"]
+	info.NrThrottled = cpuStat["nr_throttled"]
+	info.ThrottledUsec = cpuStat["throttled_usec"]
+
+	info.MemoryCurrent, _ = readCgroupValue(filepath.Join(dir, "memory.current"))
+	info.MemoryMax, _ = readCgroupValue(filepath.Join(dir, "memory.max"))
+	if info.MemoryMax > 0 {
+		info.MemoryUsagePercent = float64(info.MemoryCurrent) / float64(info.MemoryMax) * 100
+	}
+	memEvents := readKeyValueFile(filepath.Join(dir, "memory.events"))
+	info.OOM = memEvents["oom"]
+	info.OOMKill = memEvents["oom_kill"]
+
+	info.IOReadBytes, info.IOWriteBytes = readIOStat(filepath.Join(dir, "io.stat"))
+
+	info.PIDsCurrent, _ = readCgroupValue(filepath.Join(dir, "pids.current"))
+	info.PIDsMax, _ = readCgroupValue(filepath.Join(dir, "pids.max"))
+	info.PSI = readPressureDir(dir, ".pressure")
+
+	sample := cgroupSample{
+		usageUsec:     cpuStat["usage_usec"],
+		nrPeriods:     info.NrPeriods,
+		nrThrottled:   info.NrThrottled,
+		throttledUsec: info.ThrottledUsec,
+		oomKill:       info.OOMKill,
+		ioRead:        info.IOReadBytes,
+		ioWrite:       info.IOWriteBytes,
+		at:            now,
+	}
+
+	c.mu.Lock()
+	prev, ok := c.prev[rel]
+	c.prev[rel] = sample
+	c.mu.Unlock()
+
+	// На первом сборе показатели за интервал остаются нулевыми
+	if !ok {
+		return info
+	}
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return info
+	}
+
+	if sample.usageUsec >= prev.usageUsec {
+		info.CPUPercent = float64(sample.usageUsec-prev.usageUsec) / 1e6 / elapsed * 100
+	}
+	if sample.nrPeriods > prev.nrPeriods && sample.nrThrottled >= prev.nrThrottled {
+		info.ThrottledPercent = float64(sample.nrThrottled-prev.nrThrottled) / float64(sample.nrPeriods-prev.nrPeriods) * 100
+	}
+	if sample.throttledUsec >= prev.throttledUsec {
+		info.ThrottledSecondsPerSec = float64(sample.throttledUsec-prev.throttledUsec) / 1e6 / elapsed
+	}
+	if sample.oomKill >= prev.oomKill {
+		info.OOMKillDelta = sample.oomKill - prev.oomKill
+	}
+	info.IOReadBytesPerSec = counterRate(prev.ioRead, sample.ioRead, elapsed)
+	info.IOWriteBytesPerSec = counterRate(prev.ioWrite, sample.ioWrite, elapsed)
+	return info
+}
+
+// readKeyValueFile разбирает файлы вида "key value" (cpu.stat, memory.events)
+func readKeyValueFile(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values
+}
+
+// readCgroupValue читает файл с одним числом; "max" означает отсутствие ограничения и дает 0
+func readCgroupValue(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readIOStat суммирует rbytes и wbytes по всем устройствам из io.stat:
+// "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0"
+func readIOStat(path string) (read, write uint64) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for _, field := range fields[min(1, len(fields)):] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				write += n
+			}
+		}
+	}
+	return read, write
+}
+
+// hasGlob сообщает, содержит ли путь символы шаблона
+func hasGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
@@ -0,0 +1,304 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cgroupFiles возвращает файлы интерфейса одной cgroup v2 с заданными счетчиками
+func cgroupFiles(rel string, usageUsec, nrPeriods, nrThrottled, throttledUsec, oomKill, rbytes, wbytes int, memoryMax string) map[string]string {
+	dir := rel + "/"
+	return map[string]string{
+		dir + "cpu.stat": "usage_usec " + strconv.Itoa(usageUsec) + "\nuser_usec 0\nsystem_usec 0\n" +
+			"nr_periods " + strconv.Itoa(nrPeriods) + "\nnr_throttled " + strconv.Itoa(nrThrottled) + "\nthrottled_usec " + strconv.Itoa(throttledUsec) + "\n",
+		dir + "memory.current": "268435456\n",
+		dir + "memory.max":     memoryMax + "\n",
+		dir + "memory.events":  "low 0\nhigh 0\nmax 4\noom 2\noom_kill " + strconv.Itoa(oomKill) + "\noom_group_kill 0\n",
+		dir + "io.stat": "8:0 rbytes=" + strconv.Itoa(rbytes) + " wbytes=" + strconv.Itoa(wbytes) + " rios=1 wios=1 dbytes=0 dios=0\n" +
+			"253:0 rbytes=0 wbytes=0 rios=0 wios=0 dbytes=0 dios=0\n",
+		dir + "pids.current": "12\n",
+		dir + "pids.max":     "max\n",
+		dir + "cpu.pressure": "some avg10=1.50 avg60=0.75 avg300=0.10 total=123456\nfull avg10=0.50 avg60=0.25 avg300=0.05 total=23456\n",
+		dir + "io.pressure":  "some avg10=3.00 avg60=2.00 avg300=1.00 total=999\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	}
+}
+
+func newTestCgroupCollector(t *testing.T, root string, targets []models.CgroupTarget) *CgroupCollector {
+	t.Helper()
+	c, err := NewCgroupCollector(root, targets)
+	if err != nil {
+		t.Fatalf("NewCgroupCollector: %v", err)
+	}
+	return c
+}
+
+func TestReadCgroup(t *testing.T) {
+	root := t.TempDir()
+	const rel = "system.slice/nginx.service"
+	writeTestFiles(t, root, cgroupFiles(rel, 2000000, 100, 10, 50000, 1, 4096, 8192, "536870912"))
+	c := newTestCgroupCollector(t, root, nil)
+
+	start := time.Now()
+	info := c.readCgroup("nginx", rel, start)
+	if info.Name != "nginx" || info.Path != rel {
+		t.Errorf("name/path = %q/%q", info.Name, info.Path)
+	}
+	if info.CPUUsageSeconds != 2 || info.NrPeriods != 100 || info.NrThrottled != 10 || info.ThrottledUsec != 50000 {
+		t.Errorf("cpu.stat = %+v", info)
+	}
+	if info.MemoryCurrent != 268435456 || info.MemoryMax != 536870912 || info.MemoryUsagePercent != 50 {
+		t.Errorf("memory = %d/%d %v%%", info.MemoryCurrent, info.MemoryMax, info.MemoryUsagePercent)
+	}
+	if info.OOM != 2 || info.OOMKill != 1 {
+		t.Errorf("memory.events = oom %d oom_kill %d", info.OOM, info.OOMKill)
+	}
+	if info.IOReadBytes != 4096 || info.IOWriteBytes != 8192 {
+		t.Errorf("io.stat = %d/%d", info.IOReadBytes, info.IOWriteBytes)
+	}
+	if info.PIDsCurrent != 12 || info.PIDsMax != 0 {
+		t.Errorf("pids = %d/%d", info.PIDsCurrent, info.PIDsMax)
+	}
+	if info.PSI == nil || info.PSI.CPU.Some.Avg10 != 1.5 || info.PSI.CPU.Full.Total != 23456 || info.PSI.IO.Some.Avg60 != 2 {
+		t.Errorf("psi = %+v", info.PSI)
+	}
+	// На первом сборе показатели за интервал нулевые
+	if info.CPUPercent != 0 || info.ThrottledPercent != 0 || info.OOMKillDelta != 0 || info.IOReadBytesPerSec != 0 {
+		t.Errorf("interval values on first read: %+v", info)
+	}
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		check func(t *testing.T, info models.CgroupInfo)
+	}{
+		{
+			name:  "deltas over interval",
+			files: cgroupFiles(rel, 7000000, 200, 35, 1050000, 3, 14096, 8192, "max"),
+			check: func(t *testing.T, info models.CgroupInfo) {
+				// 5 секунд CPU за 10 секунд - половина ядра
+				if info.CPUPercent != 50 {
+					t.Errorf("cpu percent = %v, want 50", info.CPUPercent)
+				}
+				if info.ThrottledPercent != 25 {
+					t.Errorf("throttled percent = %v, want 25", info.ThrottledPercent)
+				}
+				if info.ThrottledSecondsPerSec != 0.1 {
+					t.Errorf("throttled seconds/sec = %v, want 0.1", info.ThrottledSecondsPerSec)
+				}
+				if info.OOMKillDelta != 2 {
+					t.Errorf("oom kill delta = %d, want 2", info.OOMKillDelta)
+				}
+				if info.IOReadBytesPerSec != 1000 || info.IOWriteBytesPerSec != 0 {
+					t.Errorf("io rates = %v/%v, want 1000/0", info.IOReadBytesPerSec, info.IOWriteBytesPerSec)
+				}
+				// memory.max = max - ограничения нет
+				if info.MemoryMax != 0 || info.MemoryUsagePercent != 0 {
+					t.Errorf("unlimited memory = %d %v%%", info.MemoryMax, info.MemoryUsagePercent)
+				}
+			},
+		},
+		{
+			// cgroup пересоздали с тем же путем: счетчики начались заново
+			name:  "counter reset",
+			files: cgroupFiles(rel, 1000, 1, 0, 0, 0, 10, 20, "max"),
+			check: func(t *testing.T, info models.CgroupInfo) {
+				if info.CPUPercent != 0 || info.ThrottledPercent != 0 || info.ThrottledSecondsPerSec != 0 ||
+					info.OOMKillDelta != 0 || info.IOReadBytesPerSec != 0 || info.IOWriteBytesPerSec != 0 {
+					t.Errorf("interval values after reset: %+v", info)
+				}
+			},
+		},
+		{
+			name:  "values after reset count from new baseline",
+			files: cgroupFiles(rel, 2001000, 11, 1, 0, 1, 10, 10020, "max"),
+			check: func(t *testing.T, info models.CgroupInfo) {
+				if info.CPUPercent != 20 || info.ThrottledPercent != 10 || info.OOMKillDelta != 1 || info.IOWriteBytesPerSec != 1000 {
+					t.Errorf("interval values = cpu %v throttled %v oom %d write %v",
+						info.CPUPercent, info.ThrottledPercent, info.OOMKillDelta, info.IOWriteBytesPerSec)
+				}
+			},
+		},
+	}
+
+	now := start
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestFiles(t, root, tt.files)
+			now = now.Add(10 * time.Second)
+			tt.check(t, c.readCgroup("nginx", rel, now))
+		})
+	}
+}
+
+func TestReadCgroupMissingControllers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"user.slice/cpu.stat": "usage_usec 1000000\n"})
+	c := newTestCgroupCollector(t, root, nil)
+
+	info := c.readCgroup("user.slice", "user.slice", time.Now())
+	if info.CPUUsageSeconds != 1 || info.MemoryCurrent != 0 || info.PIDsCurrent != 0 || info.PSI != nil {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestCgroupResolve(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"cgroup.controllers":                             "cpu io memory pids\n",
+		"system.slice/nginx.service/cpu.stat":            "usage_usec 0\n",
+		"system.slice/postgresql.service/cpu.stat":       "usage_usec 0\n",
+		"system.slice/docker.socket/cpu.stat":            "usage_usec 0\n",
+		"system.slice/cgroup.procs":                      "",
+		"system.slice/not-a-dir.service":                 "",
+		"kubepods.slice/pod-a/cri-containerd-1/cpu.stat": "usage_usec 0\n",
+		"kubepods.slice/pod-b/cri-containerd-2/cpu.stat": "usage_usec 0\n",
+	})
+	c := newTestCgroupCollector(t, root, nil)
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "system.slice/nginx.service", want: []string{"system.slice/nginx.service"}},
+		{pattern: "/system.slice/nginx.service/", want: []string{"system.slice/nginx.service"}},
+		{pattern: "system.slice/missing.service", want: nil},
+		{pattern: "system.slice/not-a-dir.service", want: nil},
+		{pattern: "system.slice/*.service", want: []string{"system.slice/nginx.service", "system.slice/postgresql.service"}},
+		{pattern: "kubepods.slice/*/cri-containerd-*", want: []string{"kubepods.slice/pod-a/cri-containerd-1", "kubepods.slice/pod-b/cri-containerd-2"}},
+		{pattern: "system.slice/[np]*", want: []string{"system.slice/nginx.service", "system.slice/postgresql.service"}},
+		{pattern: "nothing/*", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := c.resolve(tt.pattern)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("resolve(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCgroupCollect(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{"cgroup.controllers": "cpu io memory pids\n"}
+	for _, rel := range []string{"system.slice/nginx.service", "system.slice/cron.service", "user.slice"} {
+		for name, content := range cgroupFiles(rel, 1000, 0, 0, 0, 0, 0, 0, "max") {
+			files[name] = content
+		}
+	}
+	writeTestFiles(t, root, files)
+
+	c := newTestCgroupCollector(t, root, []models.CgroupTarget{
+		{Alias: "users", Path: "user.slice"},
+		{Alias: "ignored", Path: "system.slice/*.service"},
+		{Path: "system.slice/nginx.service"}, // уже найдена шаблоном
+		{Path: "missing.slice"},
+	})
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	var names []string
+	for _, info := range metrics.Cgroups {
+		names = append(names, info.Name)
+	}
+	// Псевдоним применяется только к пути без шаблона
+	want := "users,system.slice/cron.service,system.slice/nginx.service"
+	if strings.Join(names, ",") != want {
+		t.Errorf("cgroups = %v, want %s", names, want)
+	}
+}
+
+func TestCgroupCollectSkipsBadPattern(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{"cgroup.controllers": "cpu io memory pids\n"}
+	for name, content := range cgroupFiles("user.slice", 1000, 0, 0, 0, 0, 0, 0, "max") {
+		files[name] = content
+	}
+	writeTestFiles(t, root, files)
+
+	c := newTestCgroupCollector(t, root, nil)
+	// Шаблоны проверяются при настройке; здесь ошибочный попадает в список в обход проверки
+	c.targets = []models.CgroupTarget{{Path: "system.slice/[bad"}, {Path: "user.slice"}}
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err == nil {
+		t.Errorf("Collect did not report the bad pattern")
+	}
+	if len(metrics.Cgroups) != 1 || metrics.Cgroups[0].Path != "user.slice" {
+		t.Errorf("cgroups = %+v, want user.slice", metrics.Cgroups)
+	}
+}
+
+func TestCgroupCollectWithoutCgroupV2(t *testing.T) {
+	c := newTestCgroupCollector(t, t.TempDir(), []models.CgroupTarget{{Path: "user.slice"}})
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err == nil {
+		t.Errorf("Collect succeeded without cgroup.controllers")
+	}
+}
+
+func TestValidateCgroupTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []models.CgroupTarget
+		wantErr string
+	}{
+		{name: "empty list"},
+		{name: "plain path", targets: []models.CgroupTarget{{Path: "system.slice/nginx.service"}}},
+		{name: "glob", targets: []models.CgroupTarget{{Path: "system.slice/*.service"}, {Path: "kubepods.slice/pod-[ab]"}}},
+		{name: "empty path", targets: []models.CgroupTarget{{Path: "/"}}, wantErr: "must not be empty"},
+		{name: "parent directory", targets: []models.CgroupTarget{{Path: "system.slice/../../etc"}}, wantErr: "must not leave"},
+		{name: "bad pattern", targets: []models.CgroupTarget{{Path: "system.slice/nginx.service"}, {Path: "system.slice/[*.service"}}, wantErr: "invalid cgroup pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCgroupTargets(tt.targets)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := NewCgroupCollector(t.TempDir(), tt.targets); err == nil {
+				t.Errorf("NewCgroupCollector accepted invalid targets")
+			}
+		})
+	}
+}
+
+func TestCgroupCollectorSetConfig(t *testing.T) {
+	c := newTestCgroupCollector(t, t.TempDir(), []models.CgroupTarget{{Alias: "web", Path: "system.slice/nginx.service"}})
+
+	if err := c.SetConfig([]byte(`{"targets": [{"path": "system.slice/[bad"}]}`)); err == nil {
+		t.Fatalf("SetConfig accepted invalid pattern")
+	}
+	if err := c.SetConfig([]byte(`{"targets": [{"alias": "x"}]}`)); err == nil {
+		t.Fatalf("SetConfig accepted target without path")
+	}
+	if got := c.Config().(CgroupConfig).Targets; len(got) != 1 || got[0].Alias != "web" {
+		t.Fatalf("invalid config changed targets: %+v", got)
+	}
+
+	if err := c.SetConfig([]byte(`{"targets": [{"path": "system.slice/*.service"}, {"alias": "users", "path": "user.slice"}]}`)); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	got := c.Config().(CgroupConfig).Targets
+	if len(got) != 2 || got[0].Path != "system.slice/*.service" || got[1].Alias != "users" {
+		t.Errorf("targets = %+v", got)
+	}
+}
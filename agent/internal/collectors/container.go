@@ -0,0 +1,359 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// ContainerOptions задает среду выполнения контейнеров и параметры ее опроса
+type ContainerOptions struct {
+	Runtime        string        // docker, podman, containerd, cri-o или auto
+	Endpoint       string        // Адрес сокета; пусто - сокет по умолчанию
+	MaxConcurrency int           // Число одновременных запросов статистики
+	StatsTimeout   time.Duration // Таймаут одного запроса к среде выполнения
+	Streaming      bool          // Держать постоянный поток статистики на каждый контейнер
+}
+
+// ContainerCollector собирает метрики контейнеров через ContainerRuntime.
+// Статистика контейнеров запрашивается параллельно (не более MaxConcurrency запросов сразу),
+// каждый запрос ограничен StatsTimeout.
+type ContainerCollector struct {
+	runtime  ContainerRuntime
+	matchers []containerMatcher // правила отбора отслеживаемых контейнеров
+	opts     ContainerOptions
+
+	mu      sync.Mutex
+	samples map[string]containerSample // счетчики с предыдущего сбора по ID контейнера
+	streams map[string]*statsStream    // потоки статистики в режиме Streaming
+}
+
+// containerSample хранит накопительные счетчики контейнера для расчета скоростей
+type containerSample struct {
+	netRx, netTx          uint64
+	blockRead, blockWrite uint64
+	at                    time.Time
+}
+
+// NewContainerCollector подключается к среде выполнения из opts (или находит ее по сокетам)
+func NewContainerCollector(containers []string, opts ContainerOptions) (*ContainerCollector, error) {
+	runtime, err := DetectContainerRuntime(opts.Runtime, opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewContainerCollectorWithRuntime(runtime, containers, opts), nil
+}
+
+// NewContainerCollectorWithRuntime создает коллектор поверх готовой среды выполнения
+func NewContainerCollectorWithRuntime(runtime ContainerRuntime, containers []string, opts ContainerOptions) *ContainerCollector {
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = 1
+	}
+	if opts.StatsTimeout <= 0 {
+		opts.StatsTimeout = 5 * time.Second
+	}
+
+	return &ContainerCollector{
+		runtime:  runtime,
+		matchers: containerMatchersFromNames(containers),
+		opts:     opts,
+		samples:  make(map[string]containerSample),
+		streams:  make(map[string]*statsStream),
+	}
+}
+
+// Runtime возвращает имя используемой среды выполнения
+func (c *ContainerCollector) Runtime() string {
+	return c.runtime.Name()
+}
+
+// containerConfigSchema - схема настроек коллектора container
+var containerConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"matchers": {
+			"type": "array",
+			"description": "Правила отбора отслеживаемых контейнеров",
+			"items": {
+				"type": "object",
+				"properties": {
+					"alias": {"type": "string"},
+					"name": {"type": "string"},
+					"name_regex": {"type": "string", "format": "regex"},
+					"image": {"type": "string"},
+					"label": {"type": "string"}
+				}
+			}
+		}
+	}
+}`)
+
+// ContainerConfig - настройки коллектора container
+type ContainerConfig struct {
+	Matchers []models.ContainerMatcher `json:"matchers"`
+}
+
+func (c *ContainerCollector) ConfigSchema() *Schema {
+	return containerConfigSchema
+}
+
+func (c *ContainerCollector) Config() interface{} {
+	matchers := c.monitored()
+	cfg := ContainerConfig{Matchers: make([]models.ContainerMatcher, 0, len(matchers))}
+	for _, m := range matchers {
+		cfg.Matchers = append(cfg.Matchers, m.spec)
+	}
+	return cfg
+}
+
+func (c *ContainerCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(ContainerConfig)
+	if err := decodeConfig(containerConfigSchema, raw, &cfg); err != nil {
+		return err
+	}
+	return c.SetMatchers(cfg.Matchers)
+}
+
+// SetNames заменяет правила отбора списком точных имен контейнеров
+func (c *ContainerCollector) SetNames(names []string) {
+	c.mu.Lock()
+	c.matchers = containerMatchersFromNames(names)
+	c.mu.Unlock()
+}
+
+// SetMatchers заменяет правила отбора контейнеров; при ошибке в правилах конфигурация не меняется
+func (c *ContainerCollector) SetMatchers(specs []models.ContainerMatcher) error {
+	matchers, err := compileContainerMatchers(specs)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.matchers = matchers
+	c.mu.Unlock()
+	return nil
+}
+
+// monitored возвращает текущие правила отбора контейнеров
+func (c *ContainerCollector) monitored() []containerMatcher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.matchers
+}
+
+func (c *ContainerCollector) Collect(metrics *models.AgentMetrics) error {
+	return c.CollectContext(context.Background(), metrics)
+}
+
+// CollectContext собирает метрики контейнеров; запросы к среде выполнения прерываются с отменой ctx
+func (c *ContainerCollector) CollectContext(ctx context.Context, metrics *models.AgentMetrics) error {
+	monitored := c.monitored()
+
+	listCtx, cancel := context.WithTimeout(ctx, c.opts.StatsTimeout)
+	defer cancel()
+
+	// При заданном списке нужны и остановленные контейнеры, чтобы видеть код выхода и OOMKilled
+	containers, err := c.runtime.List(listCtx, len(monitored) > 0)
+	if err != nil {
+		return err
+	}
+
+	var selected []RuntimeContainer
+	var aliases []string
+	for _, container := range containers {
+		// Если заданы правила отбора и контейнер ни одному не подходит - пропускаем
+		alias, ok := matchContainer(monitored, container.Names, container.Image, container.Labels)
+		if !ok {
+			continue
+		}
+		selected = append(selected, container)
+		aliases = append(aliases, alias)
+	}
+
+	// Каждый контейнер пишет в свою ячейку, поэтому порядок совпадает со списком среды выполнения
+	now := time.Now()
+	results := make([]*models.ContainerInfo, len(selected))
+	sem := make(chan struct{}, c.opts.MaxConcurrency)
+	var wg sync.WaitGroup
+	for i := range selected {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if info, err := c.collectContainer(ctx, selected[i], now); err == nil {
+				info.Alias = aliases[i]
+				results[i] = &info
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var containerInfos []models.ContainerInfo
+	seen := make(map[string]bool)
+	running := make(map[string]bool)
+	for _, info := range results {
+		if info == nil {
+			continue
+		}
+		seen[info.ID] = true
+		if info.Status == "running" {
+			running[info.ID] = true
+		}
+		containerInfos = append(containerInfos, *info)
+	}
+
+	// Забываем счетчики удаленных контейнеров и закрываем их потоки
+	c.mu.Lock()
+	for id := range c.samples {
+		if !seen[id] {
+			delete(c.samples, id)
+		}
+	}
+	for id, stream := range c.streams {
+		if !running[id] {
+			stream.stop()
+			delete(c.streams, id)
+		}
+	}
+	c.mu.Unlock()
+
+	metrics.Containers = containerInfos
+	return nil
+}
+
+// collectContainer собирает статистику и состояние одного контейнера
+func (c *ContainerCollector) collectContainer(ctx context.Context, container RuntimeContainer, now time.Time) (models.ContainerInfo, error) {
+	// Получаем имя контейнера
+	name := ""
+	if len(container.Names) > 0 {
+		name = container.Names[0]
+	}
+
+	// Получаем статус контейнера
+	status := "unknown"
+	if container.State != "" {
+		status = container.State
+	}
+
+	info := models.ContainerInfo{
+		ID:     container.ID,
+		Name:   name,
+		Image:  container.Image,
+		Status: status,
+		Labels: container.Labels,
+	}
+
+	// Состояние из inspect: перезапуски, код выхода, OOM и healthcheck
+	inspectCtx, cancel := context.WithTimeout(ctx, c.opts.StatsTimeout)
+	state, err := c.runtime.Inspect(inspectCtx, container.ID)
+	cancel()
+	if err != nil {
+		return info, err
+	}
+	applyContainerState(&info, state, now)
+
+	// У остановленного контейнера статистика нулевая
+	if status != "running" {
+		return info, nil
+	}
+
+	var stats *RuntimeStats
+	if streamer, ok := c.runtime.(statsStreamer); ok && c.opts.Streaming {
+		stats = c.streamFor(streamer, container.ID).latest()
+	}
+	// Пока поток не прислал первый снимок, делаем разовый запрос
+	if stats == nil {
+		stats, err = c.fetchStats(ctx, container.ID)
+		if err != nil {
+			return info, err
+		}
+	}
+
+	c.applyStats(&info, *stats, now)
+	return info, nil
+}
+
+// fetchStats запрашивает разовый снимок статистики с таймаутом
+func (c *ContainerCollector) fetchStats(ctx context.Context, id string) (*RuntimeStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.StatsTimeout)
+	defer cancel()
+
+	stats, err := c.runtime.Stats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// streamFor возвращает поток статистики контейнера, запуская его при необходимости.
+// Завершившийся поток (перезапуск контейнера, ошибка API) запускается заново.
+func (c *ContainerCollector) streamFor(streamer statsStreamer, id string) *statsStream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stream, ok := c.streams[id]; ok && !stream.finished() {
+		return stream
+	}
+	stream := startStatsStream(streamer, id)
+	c.streams[id] = stream
+	return stream
+}
+
+// applyContainerState заполняет поля, доступные только через inspect
+func applyContainerState(info *models.ContainerInfo, state RuntimeState, now time.Time) {
+	info.RestartCount = state.RestartCount
+	info.ExitCode = state.ExitCode
+	info.OOMKilled = state.OOMKilled
+	info.Health = state.Health
+	if !state.StartedAt.IsZero() {
+		info.StartedAt = state.StartedAt
+		if state.Running {
+			info.UptimeSeconds = now.Sub(state.StartedAt).Seconds()
+		}
+	}
+}
+
+// applyStats заполняет показатели потребления ресурсов и считает скорости за интервал
+func (c *ContainerCollector) applyStats(info *models.ContainerInfo, stats RuntimeStats, now time.Time) {
+	info.CPUPercent = stats.CPUPercent
+	info.MemoryUsageBytes = stats.MemoryUsageBytes
+	info.MemoryLimitBytes = stats.MemoryLimitBytes
+	info.MemoryCacheBytes = stats.MemoryCacheBytes
+	info.MemoryRSSBytes = stats.MemoryRSSBytes
+	if info.MemoryLimitBytes > 0 {
+		info.MemPercent = float64(info.MemoryUsageBytes) / float64(info.MemoryLimitBytes) * 100.0
+	}
+	info.NetRxBytes = stats.NetRxBytes
+	info.NetTxBytes = stats.NetTxBytes
+	info.BlockReadBytes = stats.BlockReadBytes
+	info.BlockWriteBytes = stats.BlockWriteBytes
+	info.PIDs = stats.PIDs
+
+	sample := containerSample{
+		netRx:      info.NetRxBytes,
+		netTx:      info.NetTxBytes,
+		blockRead:  info.BlockReadBytes,
+		blockWrite: info.BlockWriteBytes,
+		at:         now,
+	}
+
+	c.mu.Lock()
+	prev, ok := c.samples[info.ID]
+	c.samples[info.ID] = sample
+	c.mu.Unlock()
+
+	// На первом сборе для контейнера скорости остаются нулевыми
+	if !ok {
+		return
+	}
+	elapsed := now.Sub(prev.at).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	info.NetRxBytesPerSec = counterRate(prev.netRx, sample.netRx, elapsed)
+	info.NetTxBytesPerSec = counterRate(prev.netTx, sample.netTx, elapsed)
+	info.BlockReadBytesPerSec = counterRate(prev.blockRead, sample.blockRead, elapsed)
+	info.BlockWriteBytesPerSec = counterRate(prev.blockWrite, sample.blockWrite, elapsed)
+}
@@ -0,0 +1,91 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// containerEventsRetry - пауза перед переподключением к потоку событий среды выполнения
+const containerEventsRetry = 5 * time.Second
+
+// Watch подписывается на события контейнеров, чтобы падения между циклами сбора не терялись.
+// После разрыва соединения подписка возобновляется с момента последнего полученного события.
+// Среды выполнения без потока событий (CRI) не отслеживаются.
+func (c *ContainerCollector) Watch(ctx context.Context, emit func(events ...models.Event)) {
+	source, ok := c.runtime.(eventSource)
+	if !ok {
+		return
+	}
+
+	since := time.Now()
+	for {
+		err := source.Events(ctx, since, func(e RuntimeEvent) {
+			// При переподключении среда повторяет события той же секунды
+			if !e.Time.After(since) {
+				return
+			}
+			since = e.Time
+			if event, ok := c.containerEvent(e); ok {
+				emit(event)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("%s events stream closed: %v", c.runtime.Name(), err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(containerEventsRetry):
+		}
+	}
+}
+
+// containerEvent преобразует событие среды выполнения; события неотслеживаемых контейнеров пропускаются
+func (c *ContainerCollector) containerEvent(e RuntimeEvent) (models.Event, bool) {
+	// Атрибуты события содержат метки контейнера вместе с name и image
+	alias, ok := matchContainer(c.monitored(), []string{e.Name}, e.Image, e.Attributes)
+	if !ok {
+		return models.Event{}, false
+	}
+
+	// health_status приходит как "health_status: healthy"
+	action, detail, _ := strings.Cut(e.Action, ": ")
+
+	attrs := map[string]string{
+		"id":      e.ID,
+		"image":   e.Image,
+		"runtime": c.runtime.Name(),
+	}
+	if alias != "" {
+		attrs["alias"] = alias
+	}
+	message := fmt.Sprintf("container %s %s", e.Name, action)
+	switch action {
+	case "die":
+		attrs["exit_code"] = e.Attributes["exitCode"]
+		message = fmt.Sprintf("container %s died with exit code %s", e.Name, attrs["exit_code"])
+	case "kill":
+		attrs["signal"] = e.Attributes["signal"]
+		message = fmt.Sprintf("container %s killed with signal %s", e.Name, attrs["signal"])
+	case "oom":
+		message = fmt.Sprintf("container %s ran out of memory", e.Name)
+	case "health_status":
+		attrs["health"] = detail
+		message = fmt.Sprintf("container %s is %s", e.Name, detail)
+	}
+
+	return models.Event{
+		Timestamp:  e.Time,
+		Source:     "container",
+		Type:       action,
+		Object:     e.Name,
+		Message:    message,
+		Attributes: attrs,
+	}, true
+}
@@ -0,0 +1,181 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// containerMatcher - скомпилированное правило отбора контейнеров
+type containerMatcher struct {
+	spec      models.ContainerMatcher
+	nameRegex *regexp.Regexp
+	labels    []labelSelector
+}
+
+// labelSelector - условие на метку: точное значение или только наличие ключа
+type labelSelector struct {
+	key, value string
+	hasValue   bool
+}
+
+// compileContainerMatchers проверяет и компилирует правила отбора
+func compileContainerMatchers(specs []models.ContainerMatcher) ([]containerMatcher, error) {
+	matchers := make([]containerMatcher, 0, len(specs))
+	for _, spec := range specs {
+		m := containerMatcher{spec: spec}
+
+		if m.spec.Alias == "" {
+			m.spec.Alias = spec.Name
+		}
+		if m.spec.Alias == "" {
+			return nil, fmt.Errorf("container matcher requires alias or name")
+		}
+
+		if spec.NameRegex != "" {
+			re, err := regexp.Compile(spec.NameRegex)
+			if err != nil {
+				return nil, fmt.Errorf("matcher %s: invalid name_regex: %w", m.spec.Alias, err)
+			}
+			m.nameRegex = re
+		}
+		if spec.Image != "" {
+			if _, err := path.Match(spec.Image, ""); err != nil {
+				return nil, fmt.Errorf("matcher %s: invalid image pattern: %w", m.spec.Alias, err)
+			}
+		}
+		if spec.Label != "" {
+			labels, err := parseLabelSelectors(spec.Label)
+			if err != nil {
+				return nil, fmt.Errorf("matcher %s: %w", m.spec.Alias, err)
+			}
+			m.labels = labels
+		}
+
+		// Правило без условий отбирает контейнер с именем, равным псевдониму
+		if spec.Name == "" && spec.NameRegex == "" && spec.Image == "" && spec.Label == "" {
+			m.spec.Name = m.spec.Alias
+		}
+
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// containerMatchersFromNames строит правила точного совпадения имени для списка контейнеров
+func containerMatchersFromNames(names []string) []containerMatcher {
+	specs := make([]models.ContainerMatcher, 0, len(names))
+	for _, name := range names {
+		specs = append(specs, models.ContainerMatcher{Alias: name, Name: name})
+	}
+	// Правила только с именами компилируются без ошибок
+	matchers, _ := compileContainerMatchers(specs)
+	return matchers
+}
+
+// ContainerMatchersWithNames дополняет правила отбора правилами точного совпадения для имен из names.
+// Имена, совпадающие с псевдонимом одного из правил, пропускаются: ЦМ передает оба списка для одних и тех же контейнеров
+func ContainerMatchersWithNames(names []string, specs []models.ContainerMatcher) []models.ContainerMatcher {
+	aliases := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec.Alias != "" {
+			aliases[spec.Alias] = true
+		} else {
+			aliases[spec.Name] = true
+		}
+	}
+
+	merged := make([]models.ContainerMatcher, 0, len(names)+len(specs))
+	for _, name := range names {
+		if !aliases[name] {
+			merged = append(merged, models.ContainerMatcher{Alias: name, Name: name})
+			aliases[name] = true
+		}
+	}
+	return append(merged, specs...)
+}
+
+// parseLabelSelectors разбирает строку вида "com.docker.compose.project=billing,tier"
+func parseLabelSelectors(selector string) ([]labelSelector, error) {
+	var labels []labelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid label selector %q", part)
+		}
+		labels = append(labels, labelSelector{key: key, value: strings.TrimSpace(value), hasValue: hasValue})
+	}
+	return labels, nil
+}
+
+// matches проверяет, удовлетворяет ли контейнер всем условиям правила
+func (m *containerMatcher) matches(names []string, image string, labels map[string]string) bool {
+	if m.spec.Name != "" && !containsName(names, m.spec.Name) {
+		return false
+	}
+	if m.nameRegex != nil && !anyNameMatches(names, m.nameRegex) {
+		return false
+	}
+	if m.spec.Image != "" && !imageMatches(m.spec.Image, image) {
+		return false
+	}
+	for _, l := range m.labels {
+		value, ok := labels[l.key]
+		if !ok || (l.hasValue && value != l.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func anyNameMatches(names []string, re *regexp.Regexp) bool {
+	for _, n := range names {
+		if re.MatchString(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageMatches сравнивает образ с шаблоном; шаблон без тега подходит к образу с любым тегом
+func imageMatches(pattern, image string) bool {
+	if ok, _ := path.Match(pattern, image); ok {
+		return true
+	}
+	repo := image
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repo = image[:i]
+	}
+	ok, _ := path.Match(pattern, repo)
+	return ok
+}
+
+// matchContainer возвращает псевдоним первого подходящего правила.
+// Без правил отслеживаются все контейнеры с пустым псевдонимом.
+func matchContainer(matchers []containerMatcher, names []string, image string, labels map[string]string) (string, bool) {
+	if len(matchers) == 0 {
+		return "", true
+	}
+	for i := range matchers {
+		if matchers[i].matches(names, image, labels) {
+			return matchers[i].spec.Alias, true
+		}
+	}
+	return "", false
+}
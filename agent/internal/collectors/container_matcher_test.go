@@ -0,0 +1,123 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testContainer - атрибуты контейнера, по которым работают правила отбора
+type testContainer struct {
+	names  []string
+	image  string
+	labels map[string]string
+}
+
+func TestContainerMatcherMatches(t *testing.T) {
+	billing := testContainer{
+		names: []string{"billing-api-1"},
+		image: "registry.example.com/billing/api:2.4.1",
+		labels: map[string]string{
+			"com.docker.compose.project": "billing",
+			"com.docker.compose.service": "api",
+			"tier":                       "",
+		},
+	}
+	nginx := testContainer{names: []string{"web", "proxy"}, image: "nginx:1.25-alpine", labels: map[string]string{"tier": "frontend"}}
+	mongo := testContainer{names: []string{"build-mongodb-1"}, image: "mongo", labels: nil}
+
+	tests := []struct {
+		name string
+		spec models.ContainerMatcher
+		want []bool // billing, nginx, mongo
+	}{
+		{name: "alias only", spec: models.ContainerMatcher{Alias: "build-mongodb-1"}, want: []bool{false, false, true}},
+		// Сравнивается любое из имен контейнера
+		{name: "second name", spec: models.ContainerMatcher{Alias: "web", Name: "proxy"}, want: []bool{false, true, false}},
+		{name: "name regex", spec: models.ContainerMatcher{Alias: "billing", NameRegex: `^billing-api-\d+$`}, want: []bool{true, false, false}},
+		{name: "label value", spec: models.ContainerMatcher{Alias: "billing", Label: "com.docker.compose.project=billing"}, want: []bool{true, false, false}},
+		{name: "label key", spec: models.ContainerMatcher{Alias: "tiered", Label: "tier"}, want: []bool{true, true, false}},
+		{name: "label empty value", spec: models.ContainerMatcher{Alias: "untiered", Label: "tier="}, want: []bool{true, false, false}},
+		{name: "all labels", spec: models.ContainerMatcher{Alias: "api", Label: "com.docker.compose.project=billing, com.docker.compose.service=api"}, want: []bool{true, false, false}},
+		{name: "one label differs", spec: models.ContainerMatcher{Alias: "worker", Label: "com.docker.compose.project=billing,com.docker.compose.service=worker"}, want: []bool{false, false, false}},
+		{name: "image with tag", spec: models.ContainerMatcher{Alias: "nginx", Image: "nginx:1.25-alpine"}, want: []bool{false, true, false}},
+		{name: "image without tag", spec: models.ContainerMatcher{Alias: "nginx", Image: "nginx"}, want: []bool{false, true, false}},
+		{name: "image tag pattern", spec: models.ContainerMatcher{Alias: "nginx", Image: "nginx:1.2*"}, want: []bool{false, true, false}},
+		{name: "image repository pattern", spec: models.ContainerMatcher{Alias: "billing", Image: "registry.example.com/billing/*"}, want: []bool{true, false, false}},
+		{name: "image does not cross path", spec: models.ContainerMatcher{Alias: "registry", Image: "registry.example.com/*"}, want: []bool{false, false, false}},
+		{name: "image and label", spec: models.ContainerMatcher{Alias: "frontend", Image: "nginx:*", Label: "tier=frontend"}, want: []bool{false, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchers, err := compileContainerMatchers([]models.ContainerMatcher{tt.spec})
+			if err != nil {
+				t.Fatalf("compileContainerMatchers: %v", err)
+			}
+			for i, c := range []testContainer{billing, nginx, mongo} {
+				if got := matchers[0].matches(c.names, c.image, c.labels); got != tt.want[i] {
+					t.Errorf("matches(%v) = %v, want %v", c.names, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileContainerMatchers(t *testing.T) {
+	for _, tt := range []struct {
+		spec models.ContainerMatcher
+		err  string
+	}{
+		{spec: models.ContainerMatcher{Image: "nginx"}, err: "container matcher requires alias or name"},
+		{spec: models.ContainerMatcher{Alias: "a", NameRegex: "("}, err: "matcher a: invalid name_regex"},
+		{spec: models.ContainerMatcher{Alias: "b", Image: "nginx:[1"}, err: "matcher b: invalid image pattern"},
+		{spec: models.ContainerMatcher{Alias: "c", Label: "tier,=frontend"}, err: `matcher c: invalid label selector "=frontend"`},
+	} {
+		if _, err := compileContainerMatchers([]models.ContainerMatcher{tt.spec}); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("compileContainerMatchers(%+v) error = %v, want %q", tt.spec, err, tt.err)
+		}
+	}
+}
+
+func TestMatchContainer(t *testing.T) {
+	matchers, err := compileContainerMatchers([]models.ContainerMatcher{
+		{Alias: "billing-api", Label: "com.docker.compose.project=billing,com.docker.compose.service=api"},
+		{Alias: "billing", Label: "com.docker.compose.project=billing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Контейнер получает псевдоним первого подходящего правила
+	labels := map[string]string{"com.docker.compose.project": "billing", "com.docker.compose.service": "api"}
+	if alias, ok := matchContainer(matchers, []string{"billing-api-1"}, "billing/api", labels); !ok || alias != "billing-api" {
+		t.Errorf("alias = %q, %v, want billing-api", alias, ok)
+	}
+	labels["com.docker.compose.service"] = "worker"
+	if alias, ok := matchContainer(matchers, []string{"billing-worker-1"}, "billing/worker", labels); !ok || alias != "billing" {
+		t.Errorf("alias = %q, %v, want billing", alias, ok)
+	}
+	if _, ok := matchContainer(matchers, []string{"redis"}, "redis:7", nil); ok {
+		t.Errorf("container without matching rule selected")
+	}
+	// Без правил отслеживаются все контейнеры
+	if alias, ok := matchContainer(nil, []string{"redis"}, "redis:7", nil); !ok || alias != "" {
+		t.Errorf("alias without matchers = %q, %v", alias, ok)
+	}
+}
+
+func TestContainerMatchersWithNames(t *testing.T) {
+	specs := []models.ContainerMatcher{
+		{Alias: "billing", Label: "com.docker.compose.project=billing"},
+		{Name: "build-mongodb-1"},
+	}
+	// ЦМ передает в containers псевдонимы всех правил, а имена без правил добавляются как точные совпадения
+	got := ContainerMatchersWithNames([]string{"redis", "billing", "build-mongodb-1", "redis"}, specs)
+	want := []models.ContainerMatcher{
+		{Alias: "redis", Name: "redis"},
+		{Alias: "billing", Label: "com.docker.compose.project=billing"},
+		{Name: "build-mongodb-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchers:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,256 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRuntime - среда выполнения в памяти для тестов ContainerCollector
+type fakeRuntime struct {
+	mu         sync.Mutex
+	containers []RuntimeContainer
+	states     map[string]RuntimeState
+	stats      map[string]RuntimeStats
+	inspectErr map[string]error
+	statsDelay map[string]time.Duration
+
+	listAll    []bool   // значения all во всех вызовах List
+	statsCalls []string // ID контейнеров, для которых запрашивалась статистика
+}
+
+func (f *fakeRuntime) Name() string { return "fake" }
+
+func (f *fakeRuntime) List(ctx context.Context, all bool) ([]RuntimeContainer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listAll = append(f.listAll, all)
+	var result []RuntimeContainer
+	for _, c := range f.containers {
+		if all || c.State == "running" {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRuntime) Inspect(ctx context.Context, id string) (RuntimeState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.inspectErr[id]; err != nil {
+		return RuntimeState{}, err
+	}
+	return f.states[id], nil
+}
+
+func (f *fakeRuntime) Stats(ctx context.Context, id string) (RuntimeStats, error) {
+	f.mu.Lock()
+	f.statsCalls = append(f.statsCalls, id)
+	delay := f.statsDelay[id]
+	stats := f.stats[id]
+	f.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return RuntimeStats{}, ctx.Err()
+		}
+	}
+	return stats, nil
+}
+
+func (f *fakeRuntime) setStats(id string, stats RuntimeStats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats[id] = stats
+}
+
+func newFakeRuntime() *fakeRuntime {
+	return &fakeRuntime{
+		containers: []RuntimeContainer{
+			{ID: "a1", Names: []string{"web"}, Image: "nginx:1.25", State: "running", Labels: map[string]string{"tier": "front"}},
+			{ID: "b2", Names: []string{"billing-api"}, Image: "registry/billing/api:2", State: "running", Labels: map[string]string{"com.docker.compose.project": "billing"}},
+			{ID: "c3", Names: []string{"billing-worker"}, Image: "registry/billing/worker:2", State: "exited"},
+		},
+		states: map[string]RuntimeState{
+			"a1": {Running: true, RestartCount: 2, Health: "healthy", StartedAt: time.Now().Add(-time.Minute)},
+			"b2": {Running: true},
+			"c3": {ExitCode: 137, OOMKilled: true},
+		},
+		stats: map[string]RuntimeStats{
+			"a1": {CPUPercent: 12.5, MemoryUsageBytes: 256, MemoryLimitBytes: 1024, NetRxBytes: 1000, NetTxBytes: 2000, BlockReadBytes: 300, BlockWriteBytes: 400, PIDs: 5},
+			"b2": {CPUPercent: 1, MemoryUsageBytes: 100},
+		},
+		inspectErr: make(map[string]error),
+		statsDelay: make(map[string]time.Duration),
+	}
+}
+
+func containerIDs(containers []models.ContainerInfo) []string {
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID+"="+c.Alias)
+	}
+	return ids
+}
+
+func TestContainerCollectorSelectsByMatchers(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers []models.ContainerMatcher
+		want     []string
+		wantAll  bool
+	}{
+		{
+			name:    "no matchers lists running containers",
+			want:    []string{"a1=", "b2="},
+			wantAll: false,
+		},
+		{
+			name:     "exact name",
+			matchers: []models.ContainerMatcher{{Alias: "web", Name: "web"}},
+			want:     []string{"a1=web"},
+			wantAll:  true,
+		},
+		{
+			name:     "name regex includes stopped",
+			matchers: []models.ContainerMatcher{{Alias: "billing", NameRegex: "^billing-"}},
+			want:     []string{"b2=billing", "c3=billing"},
+			wantAll:  true,
+		},
+		{
+			name:     "image pattern",
+			matchers: []models.ContainerMatcher{{Alias: "worker", Image: "registry/billing/worker:*"}},
+			want:     []string{"c3=worker"},
+			wantAll:  true,
+		},
+		{
+			name:     "label selector",
+			matchers: []models.ContainerMatcher{{Alias: "compose", Label: "com.docker.compose.project=billing"}},
+			want:     []string{"b2=compose"},
+			wantAll:  true,
+		},
+		{
+			name: "first matching rule wins",
+			matchers: []models.ContainerMatcher{
+				{Alias: "front", Label: "tier=front"},
+				{Alias: "any", NameRegex: "."},
+			},
+			want:    []string{"a1=front", "b2=any", "c3=any"},
+			wantAll: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := newFakeRuntime()
+			c := NewContainerCollectorWithRuntime(runtime, nil, ContainerOptions{MaxConcurrency: 2})
+			if err := c.SetMatchers(tt.matchers); err != nil {
+				t.Fatalf("SetMatchers: %v", err)
+			}
+
+			var metrics models.AgentMetrics
+			if err := c.Collect(&metrics); err != nil {
+				t.Fatalf("Collect: %v", err)
+			}
+
+			got := containerIDs(metrics.Containers)
+			if len(got) != len(tt.want) {
+				t.Fatalf("containers = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("containers = %v, want %v", got, tt.want)
+				}
+			}
+			if len(runtime.listAll) != 1 || runtime.listAll[0] != tt.wantAll {
+				t.Errorf("List(all) = %v, want %v", runtime.listAll, tt.wantAll)
+			}
+		})
+	}
+}
+
+func TestContainerCollectorStateAndStats(t *testing.T) {
+	runtime := newFakeRuntime()
+	c := NewContainerCollectorWithRuntime(runtime, []string{"web", "billing-worker"}, ContainerOptions{})
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(metrics.Containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(metrics.Containers))
+	}
+
+	web := metrics.Containers[0]
+	if web.CPUPercent != 12.5 || web.MemPercent != 25 || web.PIDs != 5 {
+		t.Errorf("web stats = cpu %v mem %v%% pids %d", web.CPUPercent, web.MemPercent, web.PIDs)
+	}
+	if web.RestartCount != 2 || web.Health != "healthy" || web.UptimeSeconds < 59 {
+		t.Errorf("web state = restarts %d health %q uptime %v", web.RestartCount, web.Health, web.UptimeSeconds)
+	}
+	if web.NetRxBytesPerSec != 0 || web.BlockWriteBytesPerSec != 0 {
+		t.Errorf("rates on first collect must be zero, got rx %v write %v", web.NetRxBytesPerSec, web.BlockWriteBytesPerSec)
+	}
+
+	// Статистика остановленного контейнера не запрашивается, состояние берется из inspect
+	worker := metrics.Containers[1]
+	if worker.Status != "exited" || worker.ExitCode != 137 || !worker.OOMKilled {
+		t.Errorf("worker state = %q exit %d oom %v", worker.Status, worker.ExitCode, worker.OOMKilled)
+	}
+	if worker.CPUPercent != 0 || worker.MemoryUsageBytes != 0 {
+		t.Errorf("stopped container has stats: cpu %v mem %d", worker.CPUPercent, worker.MemoryUsageBytes)
+	}
+	for _, id := range runtime.statsCalls {
+		if id == "c3" {
+			t.Errorf("stats requested for stopped container")
+		}
+	}
+
+	// Второй сбор считает скорости по приросту счетчиков
+	time.Sleep(20 * time.Millisecond)
+	runtime.setStats("a1", RuntimeStats{NetRxBytes: 3000, NetTxBytes: 2000, BlockReadBytes: 300, BlockWriteBytes: 1400})
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	web = metrics.Containers[0]
+	if web.NetRxBytesPerSec <= 0 || web.BlockWriteBytesPerSec <= 0 {
+		t.Errorf("rates not computed: rx %v write %v", web.NetRxBytesPerSec, web.BlockWriteBytesPerSec)
+	}
+	if web.NetTxBytesPerSec != 0 || web.BlockReadBytesPerSec != 0 {
+		t.Errorf("unchanged counters have rates: tx %v read %v", web.NetTxBytesPerSec, web.BlockReadBytesPerSec)
+	}
+	// Без лимита памяти процент не считается
+	if web.MemoryLimitBytes != 0 || web.MemPercent != 0 {
+		t.Errorf("mem percent without limit = %v", web.MemPercent)
+	}
+}
+
+func TestContainerCollectorSkipsFailedContainers(t *testing.T) {
+	runtime := newFakeRuntime()
+	runtime.inspectErr["a1"] = errors.New("no such container")
+	runtime.statsDelay["b2"] = time.Second
+	c := NewContainerCollectorWithRuntime(runtime, nil, ContainerOptions{MaxConcurrency: 2, StatsTimeout: 50 * time.Millisecond})
+	if err := c.SetMatchers([]models.ContainerMatcher{{Alias: "all", NameRegex: "."}}); err != nil {
+		t.Fatalf("SetMatchers: %v", err)
+	}
+
+	start := time.Now()
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Collect took %v, stats timeout not applied", elapsed)
+	}
+
+	// a1 - ошибка inspect, b2 - таймаут статистики; остается только остановленный c3
+	got := containerIDs(metrics.Containers)
+	if len(got) != 1 || got[0] != "c3=all" {
+		t.Errorf("containers = %v, want [c3=all]", got)
+	}
+}
@@ -0,0 +1,310 @@
+package collectors
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// criRuntime работает с containerd и CRI-O через crictl в формате JSON, чтобы не тянуть в агент клиент gRPC;
+// без crictl в PATH (пакет cri-tools) эти среды недоступны. crictl stats сообщает только CPU и память,
+// поэтому лимит памяти, блочный ввод-вывод и число процессов читаются из cgroup v2 контейнера,
+// а сетевые счетчики - из /proc/<pid>/net/dev его основного процесса
+type criRuntime struct {
+	name       string
+	endpoint   string
+	crictl     string
+	procRoot   string // /proc
+	cgroupRoot string // Точка монтирования cgroup v2
+
+	mu      sync.Mutex
+	cpuPrev map[string]criCPUSample // предыдущие значения CPU для расчета процента
+	pids    map[string]int          // PID основного процесса из inspect
+}
+
+// criCPUSample - накопленное время CPU контейнера на момент снимка
+type criCPUSample struct {
+	usageNanos uint64
+	timestamp  int64
+}
+
+func newCRIRuntime(name, endpoint string) (*criRuntime, error) {
+	path, err := exec.LookPath("crictl")
+	if err != nil {
+		return nil, fmt.Errorf("crictl is required for %s: %w", name, err)
+	}
+	return &criRuntime{
+		name:       name,
+		endpoint:   endpoint,
+		crictl:     path,
+		procRoot:   "/proc",
+		cgroupRoot: DefaultCgroupRoot,
+		cpuPrev:    make(map[string]criCPUSample),
+		pids:       make(map[string]int),
+	}, nil
+}
+
+func (r *criRuntime) Name() string {
+	return r.name
+}
+
+// run выполняет crictl и декодирует JSON-ответ
+func (r *criRuntime) run(ctx context.Context, out interface{}, args ...string) error {
+	args = append([]string{"--runtime-endpoint", r.endpoint}, args...)
+	cmd := exec.CommandContext(ctx, r.crictl, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	data, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("crictl %s: %w: %s", strings.Join(args[2:], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return json.Unmarshal(data, out)
+}
+
+// criInt - целое из JSON crictl; protobuf int64/uint64 кодируются строками
+type criInt int64
+
+func (v *criInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*v = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = criInt(n)
+	return nil
+}
+
+// criValue - обертка UInt64Value из CRI
+type criValue struct {
+	Value criInt `json:"value"`
+}
+
+type criMetadata struct {
+	Name    string `json:"name"`
+	Attempt criInt `json:"attempt"`
+}
+
+func (r *criRuntime) List(ctx context.Context, all bool) ([]RuntimeContainer, error) {
+	args := []string{"ps", "-o", "json"}
+	if all {
+		args = append(args, "-a")
+	}
+
+	var resp struct {
+		Containers []struct {
+			ID       string                 `json:"id"`
+			Metadata criMetadata            `json:"metadata"`
+			Image    struct{ Image string } `json:"image"`
+			State    string                 `json:"state"`
+			Labels   map[string]string      `json:"labels"`
+		} `json:"containers"`
+	}
+	if err := r.run(ctx, &resp, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]RuntimeContainer, 0, len(resp.Containers))
+	present := make(map[string]bool, len(resp.Containers))
+	for _, c := range resp.Containers {
+		present[c.ID] = true
+		names := []string{c.Metadata.Name}
+		// В Kubernetes имя контейнера уникально только внутри пода
+		if pod := c.Labels["io.kubernetes.pod.name"]; pod != "" {
+			names = append(names, pod+"/"+c.Metadata.Name)
+		}
+		result = append(result, RuntimeContainer{
+			ID:     c.ID,
+			Names:  names,
+			Image:  c.Image.Image,
+			State:  criStateName(c.State),
+			Labels: c.Labels,
+		})
+	}
+
+	// Забываем значения CPU и PID удаленных контейнеров
+	r.mu.Lock()
+	for id := range r.cpuPrev {
+		if !present[id] {
+			delete(r.cpuPrev, id)
+		}
+	}
+	for id := range r.pids {
+		if !present[id] {
+			delete(r.pids, id)
+		}
+	}
+	r.mu.Unlock()
+
+	return result, nil
+}
+
+func (r *criRuntime) Inspect(ctx context.Context, id string) (RuntimeState, error) {
+	var resp struct {
+		Status struct {
+			Metadata  criMetadata `json:"metadata"`
+			State     string      `json:"state"`
+			StartedAt string      `json:"startedAt"`
+			ExitCode  int         `json:"exitCode"`
+			Reason    string      `json:"reason"`
+		} `json:"status"`
+		// info заполняют containerd и CRI-O; у остановленного контейнера pid равен 0
+		Info struct {
+			PID int `json:"pid"`
+		} `json:"info"`
+	}
+	if err := r.run(ctx, &resp, "inspect", "-o", "json", id); err != nil {
+		return RuntimeState{}, err
+	}
+
+	status := resp.Status
+	state := RuntimeState{
+		Running:      status.State == "CONTAINER_RUNNING",
+		RestartCount: int(status.Metadata.Attempt),
+		ExitCode:     status.ExitCode,
+		OOMKilled:    status.Reason == "OOMKilled",
+	}
+	if startedAt, err := time.Parse(time.RFC3339Nano, status.StartedAt); err == nil {
+		state.StartedAt = startedAt
+	}
+
+	r.mu.Lock()
+	if resp.Info.PID > 0 {
+		r.pids[id] = resp.Info.PID
+	} else {
+		delete(r.pids, id)
+	}
+	r.mu.Unlock()
+	return state, nil
+}
+
+func (r *criRuntime) Stats(ctx context.Context, id string) (RuntimeStats, error) {
+	var resp struct {
+		Stats []struct {
+			CPU struct {
+				Timestamp            criInt   `json:"timestamp"`
+				UsageCoreNanoSeconds criValue `json:"usageCoreNanoSeconds"`
+			} `json:"cpu"`
+			Memory struct {
+				WorkingSetBytes criValue `json:"workingSetBytes"`
+				UsageBytes      criValue `json:"usageBytes"`
+				RSSBytes        criValue `json:"rssBytes"`
+			} `json:"memory"`
+		} `json:"stats"`
+	}
+	if err := r.run(ctx, &resp, "stats", "-o", "json", "--id", id); err != nil {
+		return RuntimeStats{}, err
+	}
+	if len(resp.Stats) == 0 {
+		return RuntimeStats{}, fmt.Errorf("no stats for container %s", id)
+	}
+	s := resp.Stats[0]
+
+	stats := RuntimeStats{
+		MemoryUsageBytes: uint64(s.Memory.WorkingSetBytes.Value),
+		MemoryRSSBytes:   uint64(s.Memory.RSSBytes.Value),
+	}
+	if s.Memory.UsageBytes.Value > s.Memory.RSSBytes.Value {
+		stats.MemoryCacheBytes = uint64(s.Memory.UsageBytes.Value - s.Memory.RSSBytes.Value)
+	}
+
+	r.mu.Lock()
+	pid := r.pids[id]
+	r.mu.Unlock()
+	if pid > 0 {
+		r.readProcessStats(pid, &stats)
+	}
+
+	// Процент CPU считаем по приросту времени CPU между снимками
+	sample := criCPUSample{usageNanos: uint64(s.CPU.UsageCoreNanoSeconds.Value), timestamp: int64(s.CPU.Timestamp)}
+	r.mu.Lock()
+	prev, ok := r.cpuPrev[id]
+	r.cpuPrev[id] = sample
+	r.mu.Unlock()
+	if ok && sample.timestamp > prev.timestamp && sample.usageNanos >= prev.usageNanos {
+		stats.CPUPercent = float64(sample.usageNanos-prev.usageNanos) / float64(sample.timestamp-prev.timestamp) * 100
+	}
+	return stats, nil
+}
+
+// readProcessStats дополняет статистику CRI данными cgroup v2 и сетевыми счетчиками основного процесса
+// контейнера. Неизвестный лимит памяти (cgroup v1, memory.max = max) остается нулевым
+func (r *criRuntime) readProcessStats(pid int, stats *RuntimeStats) {
+	if rel := readProcessCgroupAt(r.procRoot, pid); rel != "" {
+		dir := filepath.Join(r.cgroupRoot, rel)
+		stats.MemoryLimitBytes, _ = readCgroupValue(filepath.Join(dir, "memory.max"))
+		stats.PIDs, _ = readCgroupValue(filepath.Join(dir, "pids.current"))
+		stats.BlockReadBytes, stats.BlockWriteBytes = readIOStat(filepath.Join(dir, "io.stat"))
+	}
+	stats.NetRxBytes, stats.NetTxBytes = readNetDev(filepath.Join(r.procRoot, strconv.Itoa(pid), "net", "dev"))
+}
+
+// readProcessCgroupAt возвращает путь cgroup v2 процесса ("0::/path") или пустую строку для cgroup v1
+func readProcessCgroupAt(procRoot string, pid int) string {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rel, ok := strings.CutPrefix(line, "0::"); ok {
+			return rel
+		}
+	}
+	return ""
+}
+
+// readNetDev суммирует принятые и переданные байты всех интерфейсов, кроме lo, из /proc/<pid>/net/dev:
+// "  eth0: 1024 10 0 0 0 0 0 0 2048 20 0 0 0 0 0 0"
+func readNetDev(path string) (rx, tx uint64) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		iface, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			rx += n
+		}
+		if n, err := strconv.ParseUint(fields[8], 10, 64); err == nil {
+			tx += n
+		}
+	}
+	return rx, tx
+}
+
+// criStateName приводит состояние CRI к именам Docker
+func criStateName(state string) string {
+	switch state {
+	case "CONTAINER_RUNNING":
+		return "running"
+	case "CONTAINER_EXITED":
+		return "exited"
+	case "CONTAINER_CREATED":
+		return "created"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,84 @@
+package collectors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFiles создает файлы с содержимым в каталоге root
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 9999      10    0    0    0     0          0         0     9999      10    0    0    0     0       0          0
+  eth0: 1024      10    0    0    0     0          0         0     2048      20    0    0    0     0       0          0
+  eth1:  100       1    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+`
+
+func TestCRIReadProcessStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  RuntimeStats
+	}{
+		{
+			name: "cgroup v2 with limit",
+			files: map[string]string{
+				"proc/42/cgroup":  "0::/kubepods/pod1/cri-containerd-abc\n",
+				"proc/42/net/dev": testNetDev,
+				"cgroup/kubepods/pod1/cri-containerd-abc/memory.max":   "536870912\n",
+				"cgroup/kubepods/pod1/cri-containerd-abc/pids.current": "12\n",
+				"cgroup/kubepods/pod1/cri-containerd-abc/io.stat": "8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0\n" +
+					"8:16 rbytes=1024 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n",
+			},
+			want: RuntimeStats{MemoryLimitBytes: 536870912, PIDs: 12, BlockReadBytes: 5120, BlockWriteBytes: 8192, NetRxBytes: 1124, NetTxBytes: 2248},
+		},
+		{
+			name: "unlimited memory stays zero",
+			files: map[string]string{
+				"proc/42/cgroup": "0::/system.slice/crio-abc.scope\n",
+				"cgroup/system.slice/crio-abc.scope/memory.max":   "max\n",
+				"cgroup/system.slice/crio-abc.scope/pids.current": "3\n",
+			},
+			want: RuntimeStats{PIDs: 3},
+		},
+		{
+			name: "cgroup v1 reports only network",
+			files: map[string]string{
+				"proc/42/cgroup":  "12:memory:/docker/abc\n11:pids:/docker/abc\n",
+				"proc/42/net/dev": testNetDev,
+			},
+			want: RuntimeStats{NetRxBytes: 1124, NetTxBytes: 2248},
+		},
+		{
+			name: "exited process",
+			want: RuntimeStats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, tt.files)
+			r := &criRuntime{procRoot: filepath.Join(root, "proc"), cgroupRoot: filepath.Join(root, "cgroup")}
+
+			var stats RuntimeStats
+			r.readProcessStats(42, &stats)
+			if stats != tt.want {
+				t.Errorf("readProcessStats = %+v, want %+v", stats, tt.want)
+			}
+		})
+	}
+}
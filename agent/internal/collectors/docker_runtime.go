@@ -0,0 +1,220 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// dockerEventActions - действия контейнеров, которые фиксируются как события
+var dockerEventActions = []string{"create", "start", "die", "oom", "kill", "health_status"}
+
+// dockerRuntime работает с Docker Engine API; Podman обслуживается им же через совместимый сокет
+type dockerRuntime struct {
+	name   string
+	client *client.Client
+}
+
+func newDockerRuntime(name, endpoint string) (*dockerRuntime, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if endpoint != "" {
+		opts = append(opts, client.WithHost(endpoint))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &dockerRuntime{name: name, client: cli}, nil
+}
+
+func (r *dockerRuntime) Name() string {
+	return r.name
+}
+
+func (r *dockerRuntime) List(ctx context.Context, all bool) ([]RuntimeContainer, error) {
+	containers, err := r.client.ContainerList(ctx, types.ContainerListOptions{All: all})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RuntimeContainer, 0, len(containers))
+	for _, c := range containers {
+		names := make([]string, 0, len(c.Names))
+		for _, name := range c.Names {
+			names = append(names, strings.TrimPrefix(name, "/"))
+		}
+		result = append(result, RuntimeContainer{
+			ID:     c.ID,
+			Names:  names,
+			Image:  c.Image,
+			State:  c.State,
+			Labels: c.Labels,
+		})
+	}
+	return result, nil
+}
+
+func (r *dockerRuntime) Inspect(ctx context.Context, id string) (RuntimeState, error) {
+	inspect, err := r.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return RuntimeState{}, err
+	}
+
+	var state RuntimeState
+	if inspect.ContainerJSONBase == nil {
+		return state, nil
+	}
+	state.RestartCount = inspect.RestartCount
+
+	if s := inspect.State; s != nil {
+		state.Running = s.Running
+		state.ExitCode = s.ExitCode
+		state.OOMKilled = s.OOMKilled
+		if s.Health != nil {
+			state.Health = s.Health.Status
+		}
+		if startedAt, err := time.Parse(time.RFC3339Nano, s.StartedAt); err == nil {
+			state.StartedAt = startedAt
+		}
+	}
+	return state, nil
+}
+
+func (r *dockerRuntime) Stats(ctx context.Context, id string) (RuntimeStats, error) {
+	stats, err := r.client.ContainerStats(ctx, id, false)
+	if err != nil {
+		return RuntimeStats{}, err
+	}
+	defer stats.Body.Close()
+
+	var statsJSON types.StatsJSON
+	if err := json.NewDecoder(stats.Body).Decode(&statsJSON); err != nil {
+		return RuntimeStats{}, err
+	}
+	return convertDockerStats(&statsJSON), nil
+}
+
+func (r *dockerRuntime) StreamStats(ctx context.Context, id string, update func(RuntimeStats)) error {
+	stats, err := r.client.ContainerStats(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	defer stats.Body.Close()
+
+	// Docker присылает по JSON-объекту в секунду, пока контейнер работает
+	decoder := json.NewDecoder(stats.Body)
+	for {
+		var sample types.StatsJSON
+		if err := decoder.Decode(&sample); err != nil {
+			return err
+		}
+		update(convertDockerStats(&sample))
+	}
+}
+
+func (r *dockerRuntime) Events(ctx context.Context, since time.Time, emit func(RuntimeEvent)) error {
+	args := filters.NewArgs(filters.Arg("type", "container"))
+	for _, action := range dockerEventActions {
+		args.Add("event", action)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errs := r.client.Events(ctx, types.EventsOptions{
+		Since:   strconv.FormatInt(since.Unix(), 10),
+		Filters: args,
+	})
+
+	for {
+		select {
+		case msg := <-messages:
+			emit(RuntimeEvent{
+				Time:       time.Unix(0, msg.TimeNano),
+				Action:     msg.Action,
+				ID:         msg.Actor.ID,
+				Name:       msg.Actor.Attributes["name"],
+				Image:      msg.Actor.Attributes["image"],
+				Attributes: msg.Actor.Attributes,
+			})
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// convertDockerStats переводит статистику Docker API в общий формат
+func convertDockerStats(stats *types.StatsJSON) RuntimeStats {
+	result := RuntimeStats{
+		CPUPercent:       calculateCPUPercent(stats),
+		MemoryUsageBytes: memoryUsageWithoutCache(stats.MemoryStats),
+		MemoryLimitBytes: stats.MemoryStats.Limit,
+		PIDs:             stats.PidsStats.Current,
+	}
+	result.MemoryCacheBytes, result.MemoryRSSBytes = memoryCacheAndRSS(stats.MemoryStats)
+
+	for _, network := range stats.Networks {
+		result.NetRxBytes += network.RxBytes
+		result.NetTxBytes += network.TxBytes
+	}
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		// cgroup v1 отдает "Read"/"Write", cgroup v2 - "read"/"write"
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			result.BlockReadBytes += entry.Value
+		case "write":
+			result.BlockWriteBytes += entry.Value
+		}
+	}
+	return result
+}
+
+// memoryCacheAndRSS возвращает страничный кэш и анонимную память.
+// Имена полей memory.stat различаются в cgroup v1 (cache/rss) и v2 (file/anon).
+func memoryCacheAndRSS(mem types.MemoryStats) (cache, rss uint64) {
+	if v, ok := mem.Stats["cache"]; ok {
+		cache = v
+	} else {
+		cache = mem.Stats["file"]
+	}
+	if v, ok := mem.Stats["rss"]; ok {
+		rss = v
+	} else {
+		rss = mem.Stats["anon"]
+	}
+	return cache, rss
+}
+
+// memoryUsageWithoutCache вычитает неактивный кэш так же, как это делает docker stats
+func memoryUsageWithoutCache(mem types.MemoryStats) uint64 {
+	inactive, ok := mem.Stats["total_inactive_file"]
+	if !ok {
+		inactive = mem.Stats["inactive_file"]
+	}
+	if inactive < mem.Usage {
+		return mem.Usage - inactive
+	}
+	return mem.Usage
+}
+
+func calculateCPUPercent(stats *types.StatsJSON) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+
+	if systemDelta > 0.0 && cpuDelta > 0.0 {
+		numCPUs := len(stats.CPUStats.CPUUsage.PercpuUsage)
+		if numCPUs == 0 {
+			numCPUs = 1
+		}
+		return (cpuDelta / systemDelta) * float64(numCPUs) * 100.0
+	}
+
+	return 0.0
+}
@@ -0,0 +1,212 @@
+package collectors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// apiVersionPrefix - префикс версии API в путях запросов клиента Docker (/v1.41/containers/json)
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// newFakeDockerAPI поднимает HTTP-сервер с минимальным подмножеством Docker Engine API
+func newFakeDockerAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	responses := map[string]string{
+		"/containers/json": `[
+			{"Id": "a1", "Names": ["/web"], "Image": "nginx:1.25", "State": "running", "Labels": {"tier": "front"}},
+			{"Id": "c3", "Names": ["/worker"], "Image": "worker:2", "State": "exited"}
+		]`,
+		"/containers/a1/json": `{
+			"Id": "a1", "RestartCount": 3,
+			"State": {"Running": true, "ExitCode": 0, "OOMKilled": false,
+				"StartedAt": "2024-05-01T10:00:00.5Z", "Health": {"Status": "unhealthy"}}
+		}`,
+		"/containers/c3/json": `{
+			"Id": "c3", "RestartCount": 0,
+			"State": {"Running": false, "ExitCode": 137, "OOMKilled": true, "StartedAt": "0001-01-01T00:00:00Z"}
+		}`,
+		"/containers/a1/stats": `{
+			"cpu_stats": {"cpu_usage": {"total_usage": 3000, "percpu_usage": [1500, 1500]}, "system_cpu_usage": 20000},
+			"precpu_stats": {"cpu_usage": {"total_usage": 1000}, "system_cpu_usage": 10000},
+			"memory_stats": {"usage": 5000, "limit": 10000, "stats": {"inactive_file": 1000, "file": 1500, "anon": 3000}},
+			"pids_stats": {"current": 7},
+			"networks": {"eth0": {"rx_bytes": 100, "tx_bytes": 200}, "eth1": {"rx_bytes": 10, "tx_bytes": 20}},
+			"blkio_stats": {"io_service_bytes_recursive": [
+				{"major": 8, "minor": 0, "op": "read", "value": 4096},
+				{"major": 8, "minor": 0, "op": "write", "value": 8192},
+				{"major": 8, "minor": 16, "op": "Write", "value": 1024}
+			]}
+		}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := apiVersionPrefix.ReplaceAllString(r.URL.Path, "")
+		if path == "/_ping" {
+			w.Header().Set("API-Version", "1.41")
+			w.Write([]byte("OK"))
+			return
+		}
+		if path == "/containers/json" && r.URL.Query().Get("all") != "1" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"Id": "a1", "Names": ["/web"], "Image": "nginx:1.25", "State": "running"}]`))
+			return
+		}
+		body, ok := responses[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "No such container"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestDockerRuntime(t *testing.T) {
+	server := newFakeDockerAPI(t)
+	defer server.Close()
+
+	runtime, err := newDockerRuntime(RuntimeDocker, "tcp://"+strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("newDockerRuntime: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	running, err := runtime.List(ctx, false)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(running) != 1 {
+		t.Errorf("List(all=false) returned %d containers, want 1", len(running))
+	}
+
+	containers, err := runtime.List(ctx, true)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("List(all=true) returned %d containers, want 2", len(containers))
+	}
+	// Docker отдает имена с ведущим "/", среда выполнения его убирает
+	if containers[0].Names[0] != "web" || containers[0].Labels["tier"] != "front" || containers[1].State != "exited" {
+		t.Errorf("unexpected containers: %+v", containers)
+	}
+
+	state, err := runtime.Inspect(ctx, "a1")
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	wantStarted := time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC)
+	if !state.Running || state.RestartCount != 3 || state.Health != "unhealthy" || !state.StartedAt.Equal(wantStarted) {
+		t.Errorf("Inspect(a1) = %+v", state)
+	}
+
+	state, err = runtime.Inspect(ctx, "c3")
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if state.Running || state.ExitCode != 137 || !state.OOMKilled || state.Health != "" {
+		t.Errorf("Inspect(c3) = %+v", state)
+	}
+
+	if _, err := runtime.Inspect(ctx, "missing"); err == nil {
+		t.Errorf("Inspect of missing container succeeded")
+	}
+
+	stats, err := runtime.Stats(ctx, "a1")
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	want := RuntimeStats{
+		CPUPercent:       40, // 2000 / 10000 * 2 CPU * 100
+		MemoryUsageBytes: 4000,
+		MemoryLimitBytes: 10000,
+		MemoryCacheBytes: 1500,
+		MemoryRSSBytes:   3000,
+		NetRxBytes:       110,
+		NetTxBytes:       220,
+		BlockReadBytes:   4096,
+		BlockWriteBytes:  9216,
+		PIDs:             7,
+	}
+	if stats != want {
+		t.Errorf("Stats(a1) = %+v, want %+v", stats, want)
+	}
+}
+
+func TestConvertDockerStatsMemory(t *testing.T) {
+	tests := []struct {
+		name      string
+		mem       types.MemoryStats
+		wantUsage uint64
+		wantCache uint64
+		wantRSS   uint64
+	}{
+		{
+			name:      "cgroup v1",
+			mem:       types.MemoryStats{Usage: 1000, Stats: map[string]uint64{"total_inactive_file": 200, "inactive_file": 50, "cache": 300, "rss": 600}},
+			wantUsage: 800,
+			wantCache: 300,
+			wantRSS:   600,
+		},
+		{
+			name:      "cgroup v2",
+			mem:       types.MemoryStats{Usage: 1000, Stats: map[string]uint64{"inactive_file": 100, "file": 400, "anon": 500}},
+			wantUsage: 900,
+			wantCache: 400,
+			wantRSS:   500,
+		},
+		{
+			name:      "inactive larger than usage",
+			mem:       types.MemoryStats{Usage: 100, Stats: map[string]uint64{"inactive_file": 200}},
+			wantUsage: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := convertDockerStats(&types.StatsJSON{Stats: types.Stats{MemoryStats: tt.mem}})
+			if stats.MemoryUsageBytes != tt.wantUsage || stats.MemoryCacheBytes != tt.wantCache || stats.MemoryRSSBytes != tt.wantRSS {
+				t.Errorf("usage/cache/rss = %d/%d/%d, want %d/%d/%d",
+					stats.MemoryUsageBytes, stats.MemoryCacheBytes, stats.MemoryRSSBytes, tt.wantUsage, tt.wantCache, tt.wantRSS)
+			}
+		})
+	}
+}
+
+func TestCalculateCPUPercent(t *testing.T) {
+	tests := []struct {
+		name              string
+		total, preTotal   uint64
+		system, preSystem uint64
+		percpu            []uint64
+		want              float64
+	}{
+		{name: "single cpu without percpu", total: 500, preTotal: 0, system: 1000, preSystem: 0, want: 50},
+		{name: "four cpus", total: 500, preTotal: 0, system: 1000, preSystem: 0, percpu: []uint64{1, 1, 1, 1}, want: 200},
+		{name: "first sample without previous system usage", total: 500, preTotal: 500, system: 1000, preSystem: 1000, want: 0},
+		{name: "counter went backwards", total: 100, preTotal: 500, system: 2000, preSystem: 1000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats types.StatsJSON
+			stats.CPUStats.CPUUsage.TotalUsage = tt.total
+			stats.CPUStats.CPUUsage.PercpuUsage = tt.percpu
+			stats.CPUStats.SystemUsage = tt.system
+			stats.PreCPUStats.CPUUsage.TotalUsage = tt.preTotal
+			stats.PreCPUStats.SystemUsage = tt.preSystem
+			if got := calculateCPUPercent(&stats); got != tt.want {
+				t.Errorf("calculateCPUPercent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
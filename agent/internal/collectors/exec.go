@@ -0,0 +1,257 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultPluginInterval - период запуска плагина, если он не задан
+	defaultPluginInterval = 60 * time.Second
+	// defaultPluginTimeout - таймаут плагина, если он не задан
+	defaultPluginTimeout = 10 * time.Second
+	// defaultPluginOutputLimit - ограничение вывода плагина, если оно не задано
+	defaultPluginOutputLimit = 64 * 1024
+	// defaultPluginUser - пользователь для плагинов, если агент запущен от root
+	defaultPluginUser = "nobody"
+	// pluginPath - PATH окружения плагинов; остальное окружение агента плагинам не передается
+	pluginPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+// Форматы вывода плагинов
+const (
+	PluginFormatJSON       = "json"
+	PluginFormatPrometheus = "prometheus"
+	PluginFormatNagios     = "nagios"
+)
+
+// ExecOptions задает общие ограничения для плагинов
+type ExecOptions struct {
+	User           string // Пользователь, от имени которого запускаются плагины
+	MaxOutputBytes int    // Ограничение stdout плагина
+}
+
+// ExecCollector запускает внешние команды (плагины) по собственному расписанию
+// и добавляет разобранные показатели в раздел custom.
+type ExecCollector struct {
+	plugins []models.ExecPlugin
+	opts    ExecOptions
+	cred    *pluginCredential
+
+	mu      sync.Mutex
+	results map[string][]models.CustomMetric // последний результат по имени плагина
+}
+
+// NewExecCollector проверяет описание плагинов и пользователя для их запуска
+func NewExecCollector(plugins []models.ExecPlugin, opts ExecOptions) (*ExecCollector, error) {
+	names := make(map[string]bool)
+	for i := range plugins {
+		p := &plugins[i]
+		if p.Name == "" || len(p.Command) == 0 {
+			return nil, errors.New("plugin requires name and command")
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("duplicate plugin %q", p.Name)
+		}
+		names[p.Name] = true
+
+		switch p.Format {
+		case "":
+			p.Format = PluginFormatJSON
+		case PluginFormatJSON, PluginFormatPrometheus, PluginFormatNagios:
+		default:
+			return nil, fmt.Errorf("plugin %s: unknown format %q", p.Name, p.Format)
+		}
+		if p.Interval <= 0 {
+			p.Interval = defaultPluginInterval
+		}
+		if p.Timeout <= 0 {
+			p.Timeout = defaultPluginTimeout
+		}
+	}
+
+	if opts.MaxOutputBytes <= 0 {
+		opts.MaxOutputBytes = defaultPluginOutputLimit
+	}
+	// Плагины никогда не запускаются от root
+	if opts.User == "" && os.Geteuid() == 0 {
+		opts.User = defaultPluginUser
+	}
+	cred, err := lookupPluginCredential(opts.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExecCollector{
+		plugins: plugins,
+		opts:    opts,
+		cred:    cred,
+		results: make(map[string][]models.CustomMetric),
+	}, nil
+}
+
+// Collect добавляет последние результаты всех плагинов
+func (c *ExecCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range c.plugins {
+		metrics.Custom = append(metrics.Custom, c.results[p.Name]...)
+	}
+	return nil
+}
+
+// Watch запускает каждый плагин по его расписанию до отмены контекста
+func (c *ExecCollector) Watch(ctx context.Context, emit func(events ...models.Event)) {
+	var wg sync.WaitGroup
+	for _, p := range c.plugins {
+		wg.Add(1)
+		go func(p models.ExecPlugin) {
+			defer wg.Done()
+			c.schedule(ctx, p, emit)
+		}(p)
+	}
+	wg.Wait()
+}
+
+func (c *ExecCollector) schedule(ctx context.Context, p models.ExecPlugin, emit func(events ...models.Event)) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		metrics, err := c.run(ctx, p)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Plugin %s failed: %v", p.Name, err)
+			// Ошибку отражаем отдельным показателем, чтобы на нее можно было настроить алерт
+			metrics = append(metrics, models.CustomMetric{
+				Name:      p.Name,
+				Plugin:    p.Name,
+				Value:     pluginStatusUnknown,
+				Status:    pluginStatusUnknown,
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			})
+			if !failing {
+				emit(models.Event{
+					Timestamp: time.Now(),
+					Source:    "plugin",
+					Type:      "failed",
+					Object:    p.Name,
+					Message:   fmt.Sprintf("Plugin %s failed: %v", p.Name, err),
+				})
+			}
+		}
+		failing = err != nil
+
+		c.mu.Lock()
+		c.results[p.Name] = metrics
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run выполняет плагин с таймаутом и ограничением вывода и разбирает результат
+func (c *ExecCollector) run(ctx context.Context, p models.ExecPlugin) ([]models.CustomMetric, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Command[0], p.Command[1:]...)
+	cmd.Env = []string{pluginPath, "LC_ALL=C"}
+	cmd.Dir = os.TempDir()
+	stdout := &limitedBuffer{limit: c.opts.MaxOutputBytes}
+	cmd.Stdout = stdout
+	cmd.Stderr = &limitedBuffer{limit: 4096}
+	cmd.WaitDelay = time.Second
+	configurePluginCommand(cmd, c.cred)
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s", p.Timeout)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("output exceeds %d bytes", c.opts.MaxOutputBytes)
+	}
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, err
+	}
+
+	var metrics []models.CustomMetric
+	switch p.Format {
+	case PluginFormatNagios:
+		// Для Nagios-плагинов код выхода и есть результат проверки
+		metrics, err = parseNagiosOutput(p.Name, exitCode, stdout.Bytes())
+	case PluginFormatPrometheus:
+		if exitCode != 0 {
+			return nil, fmt.Errorf("exit code %d: %s", exitCode, cmd.Stderr.(*limitedBuffer).String())
+		}
+		metrics, err = parsePrometheusText(stdout.Bytes())
+	default:
+		if exitCode != 0 {
+			return nil, fmt.Errorf("exit code %d: %s", exitCode, cmd.Stderr.(*limitedBuffer).String())
+		}
+		metrics, err = parseJSONPluginOutput(stdout.Bytes())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s output: %w", p.Format, err)
+	}
+
+	now := time.Now()
+	for i := range metrics {
+		metrics[i].Plugin = p.Name
+		metrics[i].Timestamp = now
+	}
+	metrics = append(metrics, models.CustomMetric{
+		Name:      p.Name + "_duration_seconds",
+		Plugin:    p.Name,
+		Value:     duration.Seconds(),
+		Timestamp: now,
+	})
+	return metrics, nil
+}
+
+// limitedBuffer сохраняет не больше limit байт и отмечает, что вывод был обрезан
+type limitedBuffer struct {
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.buf); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf = append(b.buf, p[:room]...)
+		}
+		// Сообщаем об успешной записи, чтобы плагин не получил SIGPIPE и завершился сам
+		return len(p), nil
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Bytes() []byte { return b.buf }
+
+func (b *limitedBuffer) String() string { return string(b.buf) }
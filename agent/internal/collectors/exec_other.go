@@ -0,0 +1,20 @@
+//go:build !unix
+
+package collectors
+
+import (
+	"errors"
+	"os/exec"
+)
+
+type pluginCredential struct{}
+
+// lookupPluginCredential: смена пользователя поддерживается только в unix-системах
+func lookupPluginCredential(name string) (*pluginCredential, error) {
+	if name != "" {
+		return nil, errors.New("plugin user is supported only on unix")
+	}
+	return nil, nil
+}
+
+func configurePluginCommand(cmd *exec.Cmd, cred *pluginCredential) {}
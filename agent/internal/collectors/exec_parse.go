@@ -0,0 +1,244 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Коды результата Nagios-плагинов
+const (
+	pluginStatusOK       = 0
+	pluginStatusWarning  = 1
+	pluginStatusCritical = 2
+	pluginStatusUnknown  = 3
+)
+
+// parseJSONPluginOutput разбирает JSON-вывод плагина. Поддерживаются объект
+// {"queue_depth": 12, "lag": {"value": 3, "labels": {"topic": "orders"}}}
+// и массив [{"name": "queue_depth", "value": 12, "labels": {...}}].
+func parseJSONPluginOutput(data []byte) ([]models.CustomMetric, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty output")
+	}
+
+	type jsonMetric struct {
+		Name   string            `json:"name"`
+		Value  *float64          `json:"value"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	if data[0] == '[' {
+		var list []jsonMetric
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		metrics := make([]models.CustomMetric, 0, len(list))
+		for _, m := range list {
+			if m.Name == "" || m.Value == nil {
+				return nil, errors.New("metric requires name and value")
+			}
+			metrics = append(metrics, models.CustomMetric{Name: m.Name, Value: *m.Value, Labels: m.Labels})
+		}
+		return metrics, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	metrics := make([]models.CustomMetric, 0, len(object))
+	for _, name := range names {
+		raw := object[name]
+		var value float64
+		if err := json.Unmarshal(raw, &value); err == nil {
+			metrics = append(metrics, models.CustomMetric{Name: name, Value: value})
+			continue
+		}
+		var m jsonMetric
+		if err := json.Unmarshal(raw, &m); err != nil || m.Value == nil {
+			return nil, fmt.Errorf("metric %s: expected number or object with value", name)
+		}
+		metrics = append(metrics, models.CustomMetric{Name: name, Value: *m.Value, Labels: m.Labels})
+	}
+	return metrics, nil
+}
+
+// parsePrometheusText разбирает текстовый формат экспозиции Prometheus:
+// строки вида metric_name{label="value"} 12.5 [timestamp]; комментарии пропускаются
+func parsePrometheusText(data []byte) ([]models.CustomMetric, error) {
+	var metrics []models.CustomMetric
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var name, rest string
+		var labels map[string]string
+		if i := strings.IndexByte(line, '{'); i >= 0 && i < strings.IndexAny(line+" ", " \t") {
+			name = line[:i]
+			var err error
+			labels, rest, err = parsePrometheusLabels(line[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+		} else {
+			name, rest, _ = strings.Cut(line, " ")
+		}
+
+		fields := strings.Fields(rest)
+		if name == "" || len(fields) == 0 {
+			return nil, fmt.Errorf("line %d: expected metric name and value", lineNo)
+		}
+		value, err := parsePrometheusValue(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		// +Inf и NaN не представимы в JSON, такие значения пропускаем
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			continue
+		}
+		metrics = append(metrics, models.CustomMetric{Name: name, Value: value, Labels: labels})
+	}
+	return metrics, scanner.Err()
+}
+
+// parsePrometheusLabels разбирает метки после '{' и возвращает остаток строки после '}'
+func parsePrometheusLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return nil, "", errors.New("invalid label")
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+
+		var value strings.Builder
+		closed := false
+		for i := 0; i < len(s); i++ {
+			switch {
+			case s[i] == '\\' && i+1 < len(s):
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+			case s[i] == '"':
+				s = s[i+1:]
+				closed = true
+			default:
+				value.WriteByte(s[i])
+			}
+			if closed {
+				break
+			}
+		}
+		if !closed {
+			return nil, "", errors.New("unterminated label value")
+		}
+		labels[key] = value.String()
+	}
+}
+
+// parsePrometheusValue разбирает значение, включая +Inf, -Inf и NaN
+func parsePrometheusValue(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// parseNagiosOutput разбирает вывод Nagios-плагина "TEXT | 'label'=value[UOM];warn;crit;min;max ...".
+// Результат проверки (код выхода) становится показателем с именем плагина, perfdata - отдельными показателями.
+func parseNagiosOutput(plugin string, exitCode int, data []byte) ([]models.CustomMetric, error) {
+	if exitCode < pluginStatusOK || exitCode > pluginStatusUnknown {
+		exitCode = pluginStatusUnknown
+	}
+
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	text, perfdata, _ := strings.Cut(firstLine, "|")
+
+	metrics := []models.CustomMetric{{
+		Name:    plugin,
+		Value:   float64(exitCode),
+		Status:  exitCode,
+		Message: strings.TrimSpace(text),
+	}}
+
+	for _, item := range splitNagiosPerfdata(perfdata) {
+		label, rest, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid perfdata %q", item)
+		}
+		label = strings.Trim(label, "'")
+		raw, _, _ := strings.Cut(rest, ";")
+		// Отбрасываем единицу измерения (s, ms, %, B, KB, c)
+		end := len(raw)
+		for end > 0 && !strings.ContainsRune("0123456789.", rune(raw[end-1])) {
+			end--
+		}
+		if end == 0 {
+			// Значение "U" означает, что плагин не смог получить показатель
+			continue
+		}
+		value, err := strconv.ParseFloat(raw[:end], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid perfdata value %q", item)
+		}
+		metrics = append(metrics, models.CustomMetric{Name: label, Value: value, Status: exitCode})
+	}
+	return metrics, nil
+}
+
+// splitNagiosPerfdata делит perfdata по пробелам с учетом меток в одинарных кавычках
+func splitNagiosPerfdata(s string) []string {
+	var items []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !quoted:
+			if current.Len() > 0 {
+				items = append(items, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		items = append(items, current.String())
+	}
+	return items
+}
@@ -0,0 +1,199 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONPluginOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []models.CustomMetric
+		err  string
+	}{
+		{
+			name: "object",
+			out:  `{"queue_depth": 12, "lag": {"value": 3, "labels": {"topic": "orders"}}}`,
+			want: []models.CustomMetric{
+				{Name: "lag", Value: 3, Labels: map[string]string{"topic": "orders"}},
+				{Name: "queue_depth", Value: 12},
+			},
+		},
+		{
+			name: "array",
+			out:  "  [{\"name\": \"queue_depth\", \"value\": 0}, {\"name\": \"lag\", \"value\": 1.5, \"labels\": {\"topic\": \"a\"}}]\n",
+			want: []models.CustomMetric{
+				{Name: "queue_depth", Value: 0},
+				{Name: "lag", Value: 1.5, Labels: map[string]string{"topic": "a"}},
+			},
+		},
+		{name: "empty", out: " \n", err: "empty output"},
+		{name: "array without value", out: `[{"name": "lag"}]`, err: "metric requires name and value"},
+		{name: "array without name", out: `[{"value": 1}]`, err: "metric requires name and value"},
+		{name: "object with string", out: `{"lag": "3"}`, err: "metric lag: expected number or object with value"},
+		{name: "object without value", out: `{"lag": {"labels": {}}}`, err: "metric lag: expected number or object with value"},
+		{name: "not JSON", out: `OK - all fine`, err: "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSONPluginOutput([]byte(tt.out))
+			checkPluginOutput(t, got, err, tt.want, tt.err)
+		})
+	}
+}
+
+func TestParsePrometheusText(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []models.CustomMetric
+		err  string
+	}{
+		{
+			name: "exposition",
+			out: `# HELP http_requests_total Requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027 1395066363000
+http_requests_total{method="post", code="400"}    3
+
+queue_depth 12
+temperature -3.5e1
+`,
+			want: []models.CustomMetric{
+				{Name: "http_requests_total", Value: 1027, Labels: map[string]string{"method": "post", "code": "200"}},
+				{Name: "http_requests_total", Value: 3, Labels: map[string]string{"method": "post", "code": "400"}},
+				{Name: "queue_depth", Value: 12},
+				{Name: "temperature", Value: -35},
+			},
+		},
+		{
+			name: "escaped label values",
+			out:  `msdos_file_access_time_seconds{path="C:\\DIR\\FILE.TXT",error="Cannot find file:\n\"FILE.TXT\""} 1.458e+09`,
+			want: []models.CustomMetric{{
+				Name:   "msdos_file_access_time_seconds",
+				Value:  1.458e+09,
+				Labels: map[string]string{"path": `C:\DIR\FILE.TXT`, "error": "Cannot find file:\n\"FILE.TXT\""},
+			}},
+		},
+		{
+			name: "infinity and NaN are skipped",
+			out:  "a +Inf\nb -Inf\nc NaN\nd 1\n",
+			want: []models.CustomMetric{{Name: "d", Value: 1}},
+		},
+		{name: "empty labels", out: `up{} 1`, want: []models.CustomMetric{{Name: "up", Value: 1, Labels: map[string]string{}}}},
+		{name: "missing value", out: "queue_depth\n", err: "line 1: expected metric name and value"},
+		{name: "invalid value", out: "# comment\nqueue_depth twelve\n", err: "line 2: strconv.ParseFloat"},
+		{name: "unterminated label", out: `up{job="api} 1`, err: "line 1: unterminated label value"},
+		{name: "label without quotes", out: `up{job=api} 1`, err: "line 1: invalid label"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePrometheusText([]byte(tt.out))
+			checkPluginOutput(t, got, err, tt.want, tt.err)
+		})
+	}
+}
+
+func TestParseNagiosOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		out      string
+		want     []models.CustomMetric
+		err      string
+	}{
+		{
+			name:     "perfdata",
+			exitCode: pluginStatusWarning,
+			out:      "DISK WARNING - free space: / 3326 MB (8%); | /=35164MB;33873;35756;0;37638 'inode free'=92%;;;0;100 time=0.012s\nlong output\n",
+			want: []models.CustomMetric{
+				{Name: "check_disk", Value: 1, Status: 1, Message: "DISK WARNING - free space: / 3326 MB (8%);"},
+				{Name: "/", Value: 35164, Status: 1},
+				{Name: "inode free", Value: 92, Status: 1},
+				{Name: "time", Value: 0.012, Status: 1},
+			},
+		},
+		{
+			name: "no perfdata",
+			out:  "PING OK - Packet loss = 0%",
+			want: []models.CustomMetric{{Name: "check_disk", Value: 0, Status: 0, Message: "PING OK - Packet loss = 0%"}},
+		},
+		{
+			name:     "unknown value is skipped",
+			exitCode: pluginStatusCritical,
+			out:      "CRITICAL | load=U;5;10 users=3;;",
+			want: []models.CustomMetric{
+				{Name: "check_disk", Value: 2, Status: 2, Message: "CRITICAL"},
+				{Name: "users", Value: 3, Status: 2},
+			},
+		},
+		{
+			name:     "exit code out of range is unknown",
+			exitCode: 127,
+			out:      "sh: check_disk: not found",
+			want:     []models.CustomMetric{{Name: "check_disk", Value: 3, Status: 3, Message: "sh: check_disk: not found"}},
+		},
+		{name: "perfdata without value", out: "OK | load", err: `invalid perfdata "load"`},
+		{name: "invalid perfdata value", out: "OK | load=1.2.3", err: `invalid perfdata value "load=1.2.3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNagiosOutput("check_disk", tt.exitCode, []byte(tt.out))
+			checkPluginOutput(t, got, err, tt.want, tt.err)
+		})
+	}
+}
+
+func TestSplitNagiosPerfdata(t *testing.T) {
+	got := splitNagiosPerfdata(" 'free space'=10%;; \ttime=1s  'a b c'=1 ")
+	want := []string{"'free space'=10%;;", "time=1s", "'a b c'=1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitNagiosPerfdata = %q, want %q", got, want)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 8}
+	for _, chunk := range []string{"abc", "defgh"} {
+		if n, err := b.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if b.truncated || b.String() != "abcdefgh" {
+		t.Fatalf("buffer = %q, truncated %v", b.String(), b.truncated)
+	}
+
+	// Лишний вывод отбрасывается, но плагину сообщается об успешной записи
+	if n, err := b.Write([]byte("ijk")); n != 3 || err != nil {
+		t.Fatalf("Write over the limit = %d, %v", n, err)
+	}
+	if !b.truncated || b.String() != "abcdefgh" {
+		t.Errorf("buffer = %q, truncated %v", b.String(), b.truncated)
+	}
+
+	partial := &limitedBuffer{limit: 4}
+	partial.Write([]byte("abcdef"))
+	if !partial.truncated || string(partial.Bytes()) != "abcd" {
+		t.Errorf("buffer = %q, truncated %v", partial.Bytes(), partial.truncated)
+	}
+}
+
+// checkPluginOutput сравнивает результат разбора вывода плагина с ожидаемым
+func checkPluginOutput(t *testing.T, got []models.CustomMetric, err error, want []models.CustomMetric, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("error = %v, want %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metrics:\n got %+v\nwant %+v", got, want)
+	}
+}
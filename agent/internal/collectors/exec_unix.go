@@ -0,0 +1,61 @@
+//go:build unix
+
+package collectors
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// pluginCredential - пользователь и группа, от имени которых запускаются плагины
+type pluginCredential struct {
+	uid uint32
+	gid uint32
+}
+
+// lookupPluginCredential находит пользователя для плагинов; пустое имя - пользователь агента
+func lookupPluginCredential(name string) (*pluginCredential, error) {
+	if name == "" {
+		return nil, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("plugin user: %w", err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("plugin user %s: invalid uid %q", name, u.Uid)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("plugin user %s: invalid gid %q", name, u.Gid)
+	}
+	if uid == 0 {
+		return nil, fmt.Errorf("plugin user %s is root", name)
+	}
+	// Сменить пользователя может только root; иначе плагины работают от пользователя агента
+	if os.Geteuid() != 0 && uint32(uid) != uint32(os.Geteuid()) {
+		return nil, fmt.Errorf("agent must run as root to start plugins as %s", name)
+	}
+	return &pluginCredential{uid: uint32(uid), gid: uint32(gid)}, nil
+}
+
+// configurePluginCommand запускает плагин в отдельной группе процессов от имени
+// заданного пользователя; по таймауту завершается вся группа, включая дочерние процессы
+func configurePluginCommand(cmd *exec.Cmd, cred *pluginCredential) {
+	attr := &syscall.SysProcAttr{Setpgid: true}
+	if cred != nil {
+		attr.Credential = &syscall.Credential{Uid: cred.uid, Gid: cred.gid, Groups: []uint32{}}
+	}
+	cmd.SysProcAttr = attr
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,84 @@
+//go:build unix
+
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPluginUserIsNeverRoot(t *testing.T) {
+	if _, err := lookupPluginCredential("root"); err == nil || !strings.Contains(err.Error(), "is root") {
+		t.Errorf("lookupPluginCredential(root) error = %v, want refusal", err)
+	}
+	if cred, err := lookupPluginCredential(""); cred != nil || err != nil {
+		t.Errorf("lookupPluginCredential(\"\") = %+v, %v, want the agent user", cred, err)
+	}
+
+	plugins := []models.ExecPlugin{{Name: "echo", Command: []string{"echo"}}}
+	if _, err := NewExecCollector(plugins, ExecOptions{User: "root"}); err == nil {
+		t.Errorf("NewExecCollector accepted root as plugin user")
+	}
+
+	c, err := NewExecCollector(plugins, ExecOptions{})
+	if err != nil {
+		t.Fatalf("NewExecCollector: %v", err)
+	}
+	if os.Geteuid() == 0 {
+		// Агент, запущенный от root, понижает плагины до defaultPluginUser
+		if c.opts.User != defaultPluginUser || c.cred == nil || c.cred.uid == 0 {
+			t.Errorf("plugin user = %q, credential %+v", c.opts.User, c.cred)
+		}
+	} else if c.cred != nil {
+		t.Errorf("credential = %+v, want the agent user", c.cred)
+	}
+}
+
+func TestExecCollectorRun(t *testing.T) {
+	plugins := []models.ExecPlugin{
+		{Name: "queue", Command: []string{"/bin/sh", "-c", `echo '{"queue_depth": 12}'`}},
+		{Name: "check", Command: []string{"/bin/sh", "-c", `echo 'WARNING | load=3'; exit 1`}, Format: PluginFormatNagios},
+		{Name: "noisy", Command: []string{"/bin/sh", "-c", `head -c 4096 /dev/zero`}},
+		{Name: "slow", Command: []string{"/bin/sh", "-c", `sleep 5`}, Timeout: 100 * time.Millisecond},
+		{Name: "failed", Command: []string{"/bin/sh", "-c", `echo boom >&2; exit 2`}},
+	}
+	c, err := NewExecCollector(plugins, ExecOptions{MaxOutputBytes: 1024})
+	if err != nil {
+		t.Fatalf("NewExecCollector: %v", err)
+	}
+
+	tests := []struct {
+		plugin string
+		value  float64 // Значение первого показателя
+		err    string
+	}{
+		{plugin: "queue", value: 12},
+		{plugin: "check", value: pluginStatusWarning},
+		{plugin: "noisy", err: "output exceeds 1024 bytes"},
+		{plugin: "slow", err: "timed out after 100ms"},
+		{plugin: "failed", err: "exit code 2: boom"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.plugin, func(t *testing.T) {
+			metrics, err := c.run(context.Background(), c.plugins[i])
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			// Последний показатель - длительность запуска
+			if len(metrics) < 2 || metrics[0].Value != tt.value || metrics[0].Plugin != tt.plugin ||
+				metrics[len(metrics)-1].Name != tt.plugin+"_duration_seconds" {
+				t.Errorf("metrics = %+v", metrics)
+			}
+		})
+	}
+}
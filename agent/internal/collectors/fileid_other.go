@@ -0,0 +1,15 @@
+//go:build !unix
+
+package collectors
+
+import "os"
+
+// fileID недоступен без inode: ротация между перезапусками определяется только по размеру файла
+func fileID(info os.FileInfo) uint64 {
+	return 0
+}
+
+// fileOwner недоступен без Stat_t: владелец файла не сравнивается
+func fileOwner(info os.FileInfo) (uid, gid uint32, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package collectors
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID возвращает номер inode файла; по нему после перезапуска агента
+// отличается прежний файл от нового, созданного при ротации
+func fileID(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
+
+// fileOwner возвращает UID и GID владельца файла
+func fileOwner(info os.FileInfo) (uid, gid uint32, ok bool) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return st.Uid, st.Gid, true
+	}
+	return 0, 0, false
+}
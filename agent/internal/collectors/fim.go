@@ -0,0 +1,404 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// fimBaselineFile - файл в каталоге состояния агента с последними известными хешами файлов
+	fimBaselineFile = "fim_baseline.json"
+	// defaultFIMInterval - период проверки файлов, если он не задан
+	defaultFIMInterval = 5 * time.Minute
+	// defaultFIMMaxFileSize - файлы больше этого размера не хешируются, изменение определяется по размеру и времени
+	defaultFIMMaxFileSize = 64 << 20
+	// fimDebounce - пауза после уведомления inotify, чтобы серия записей дала одну проверку
+	fimDebounce = time.Second
+)
+
+// FileIntegrityOptions задает параметры контроля целостности файлов
+type FileIntegrityOptions struct {
+	StateDir    string        // Каталог для сохранения хешей между перезапусками
+	Interval    time.Duration // Период полной проверки
+	Inotify     bool          // Проверять сразу после изменения (только Linux)
+	MaxFileSize int64         // Ограничение размера хешируемого файла
+}
+
+// FileIntegrityCollector периодически хеширует заданные файлы (пути, шаблоны или каталоги без вложенных)
+// и сообщает о добавленных, удаленных и измененных файлах событиями с источником fim.
+// Хеши сохраняются в каталоге состояния, поэтому изменения, сделанные при остановленном агенте, тоже обнаруживаются.
+type FileIntegrityCollector struct {
+	paths     []string
+	opts      FileIntegrityOptions
+	stateFile string
+
+	mu       sync.Mutex
+	baseline map[string]fileState
+
+	owners *ownerCache
+}
+
+// fileState - сохраняемое состояние файла
+type fileState struct {
+	Hash    string    `json:"hash,omitempty"` // sha256; пусто для файлов больше MaxFileSize
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	Owner   string    `json:"owner"`
+	ModTime time.Time `json:"mtime"`
+}
+
+// NewFileIntegrityCollector проверяет пути и применяет значения по умолчанию
+func NewFileIntegrityCollector(paths []string, opts FileIntegrityOptions) (*FileIntegrityCollector, error) {
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			return nil, fmt.Errorf("file integrity path %q must be absolute", p)
+		}
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid file integrity pattern %q: %w", p, err)
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultFIMInterval
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = defaultFIMMaxFileSize
+	}
+
+	c := &FileIntegrityCollector{
+		paths:  paths,
+		opts:   opts,
+		owners: newOwnerCache(),
+	}
+	if opts.StateDir != "" {
+		c.stateFile = filepath.Join(opts.StateDir, fimBaselineFile)
+	}
+	return c, nil
+}
+
+// Collect ничего не добавляет в метрики: изменения файлов передаются событиями из Watch
+func (c *FileIntegrityCollector) Collect(metrics *models.AgentMetrics) error {
+	return nil
+}
+
+// Watch проверяет файлы при запуске, затем по интервалу и, если включено, по уведомлениям inotify
+func (c *FileIntegrityCollector) Watch(ctx context.Context, emit func(events ...models.Event)) {
+	trigger := make(chan struct{}, 1)
+	if c.opts.Inotify {
+		if err := watchFileChanges(ctx, c.watchDirs(), trigger); err != nil {
+			log.Printf("File integrity: inotify unavailable, using interval checks: %v", err)
+		}
+	}
+
+	c.scan(emit)
+
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	debounce := time.NewTimer(fimDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.scan(emit)
+		case <-trigger:
+			debounce.Reset(fimDebounce)
+		case <-debounce.C:
+			c.scan(emit)
+		}
+	}
+}
+
+// watchDirs возвращает каталоги, изменения в которых отслеживаются через inotify.
+// Отслеживаются каталоги, а не файлы: редакторы заменяют файл переименованием нового
+func (c *FileIntegrityCollector) watchDirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, p := range c.paths {
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			add(p)
+			continue
+		}
+		dir := filepath.Dir(p)
+		if !hasGlob(dir) {
+			add(dir)
+			continue
+		}
+		matches, _ := filepath.Glob(dir)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.IsDir() {
+				add(m)
+			}
+		}
+	}
+	return dirs
+}
+
+// scan сравнивает текущее состояние файлов с сохраненным и передает события об изменениях
+func (c *FileIntegrityCollector) scan(emit func(events ...models.Event)) {
+	current := c.snapshot()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.baseline
+	if previous == nil {
+		previous = c.loadBaseline()
+	}
+	c.baseline = current
+
+	// Без сохраненного состояния первая проверка только запоминает файлы
+	if previous == nil {
+		c.saveBaseline()
+		return
+	}
+
+	events := diffFileStates(previous, current, time.Now())
+	if len(events) > 0 {
+		c.saveBaseline()
+		emit(events...)
+	}
+}
+
+// snapshot собирает состояние всех файлов, попадающих под пути и шаблоны
+func (c *FileIntegrityCollector) snapshot() map[string]fileState {
+	states := make(map[string]fileState)
+	for _, pattern := range c.paths {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				continue
+			}
+			if !info.IsDir() {
+				c.addFileState(states, m, info)
+				continue
+			}
+			// Каталог проверяется без вложенных каталогов
+			entries, err := os.ReadDir(m)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				path := filepath.Join(m, e.Name())
+				if info, err := os.Stat(path); err == nil && !info.IsDir() {
+					c.addFileState(states, path, info)
+				}
+			}
+		}
+	}
+	return states
+}
+
+func (c *FileIntegrityCollector) addFileState(states map[string]fileState, path string, info os.FileInfo) {
+	if !info.Mode().IsRegular() {
+		return
+	}
+	state := fileState{
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		Owner:   c.owners.owner(info),
+		ModTime: info.ModTime().UTC(),
+	}
+	if info.Size() <= c.opts.MaxFileSize {
+		hash, err := hashFile(path)
+		if err != nil {
+			// Файл без доступа на чтение не сравниваем, чтобы не сообщать о ложном изменении
+			log.Printf("File integrity: %v", err)
+			return
+		}
+		state.Hash = hash
+	}
+	states[path] = state
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// diffFileStates формирует события fim: added, removed и modified с прежними и новыми значениями
+func diffFileStates(previous, current map[string]fileState, now time.Time) []models.Event {
+	var events []models.Event
+	for path, cur := range current {
+		old, ok := previous[path]
+		if !ok {
+			events = append(events, models.Event{
+				Timestamp:  now,
+				Source:     "fim",
+				Type:       "added",
+				Object:     path,
+				Message:    fmt.Sprintf("File %s added", path),
+				Attributes: fileStateAttributes(nil, &cur),
+			})
+			continue
+		}
+
+		var changed []string
+		if old.Hash != cur.Hash || old.Size != cur.Size || (cur.Hash == "" && !old.ModTime.Equal(cur.ModTime)) {
+			changed = append(changed, "content")
+		}
+		if old.Mode != cur.Mode {
+			changed = append(changed, "mode")
+		}
+		if old.Owner != cur.Owner {
+			changed = append(changed, "owner")
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		attrs := fileStateAttributes(&old, &cur)
+		attrs["changed"] = strings.Join(changed, ",")
+		events = append(events, models.Event{
+			Timestamp:  now,
+			Source:     "fim",
+			Type:       "modified",
+			Object:     path,
+			Message:    fmt.Sprintf("File %s modified (%s)", path, strings.Join(changed, ", ")),
+			Attributes: attrs,
+		})
+	}
+	for path, old := range previous {
+		if _, ok := current[path]; !ok {
+			events = append(events, models.Event{
+				Timestamp:  now,
+				Source:     "fim",
+				Type:       "removed",
+				Object:     path,
+				Message:    fmt.Sprintf("File %s removed", path),
+				Attributes: fileStateAttributes(&old, nil),
+			})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Object < events[j].Object })
+	return events
+}
+
+func fileStateAttributes(old, cur *fileState) map[string]string {
+	attrs := make(map[string]string)
+	if old != nil {
+		attrs["old_hash"] = old.Hash
+		attrs["old_mode"] = old.Mode
+		attrs["old_owner"] = old.Owner
+	}
+	if cur != nil {
+		attrs["new_hash"] = cur.Hash
+		attrs["new_mode"] = cur.Mode
+		attrs["new_owner"] = cur.Owner
+		attrs["size"] = strconv.FormatInt(cur.Size, 10)
+	}
+	for k, v := range attrs {
+		if v == "" {
+			delete(attrs, k)
+		}
+	}
+	return attrs
+}
+
+func (c *FileIntegrityCollector) loadBaseline() map[string]fileState {
+	if c.stateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.stateFile)
+	if err != nil {
+		return nil
+	}
+	var baseline map[string]fileState
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		log.Printf("Ignoring corrupted %s: %v", c.stateFile, err)
+		return nil
+	}
+	return baseline
+}
+
+func (c *FileIntegrityCollector) saveBaseline() {
+	if c.stateFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(c.baseline, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := WriteFileAtomic(c.stateFile, data, 0o600); err != nil {
+		log.Printf("Failed to save file integrity baseline: %v", err)
+	}
+}
+
+// ownerCache переводит UID и GID в имена, запоминая результат
+type ownerCache struct {
+	mu     sync.Mutex
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+func newOwnerCache() *ownerCache {
+	return &ownerCache{users: make(map[uint32]string), groups: make(map[uint32]string)}
+}
+
+// owner возвращает владельца файла в виде пользователь:группа
+func (o *ownerCache) owner(info os.FileInfo) string {
+	uid, gid, ok := fileOwner(info)
+	if !ok {
+		return ""
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	name, ok := o.users[uid]
+	if !ok {
+		name = strconv.FormatUint(uint64(uid), 10)
+		if u, err := user.LookupId(name); err == nil {
+			name = u.Username
+		}
+		o.users[uid] = name
+	}
+	group, ok := o.groups[gid]
+	if !ok {
+		group = strconv.FormatUint(uint64(gid), 10)
+		if g, err := user.LookupGroupId(group); err == nil {
+			group = g.Name
+		}
+		o.groups[gid] = group
+	}
+	return name + ":" + group
+}
+
+// errInotifyUnsupported возвращается на системах без inotify
+var errInotifyUnsupported = errors.New("inotify is only available on linux")
@@ -0,0 +1,53 @@
+//go:build linux
+
+package collectors
+
+import (
+	"context"
+	"os"
+	"syscall"
+)
+
+// inotifyMask - изменения в каталоге, после которых файлы проверяются заново
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB |
+	syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// watchFileChanges подписывается на изменения в каталогах и сигнализирует в trigger.
+// Если каталог удален и создан заново, подписка теряется, и изменения находит проверка по интервалу
+func watchFileChanges(ctx context.Context, dirs []string, trigger chan<- struct{}) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	watched := 0
+	for _, dir := range dirs {
+		if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err == nil {
+			watched++
+		}
+	}
+	if watched == 0 {
+		syscall.Close(fd)
+		return syscall.ENOENT
+	}
+
+	// Неблокирующий дескриптор регистрируется в планировщике Go, поэтому Close прерывает Read
+	f := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			if _, err := f.Read(buf); err != nil {
+				return
+			}
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return nil
+}
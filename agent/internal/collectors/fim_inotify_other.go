@@ -0,0 +1,10 @@
+//go:build !linux
+
+package collectors
+
+import "context"
+
+// watchFileChanges недоступен без inotify: изменения находит проверка по интервалу
+func watchFileChanges(ctx context.Context, dirs []string, trigger chan<- struct{}) error {
+	return errInotifyUnsupported
+}
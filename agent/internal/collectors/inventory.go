@@ -0,0 +1,119 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/host"
+	"github.com/shirou/gopsutil/mem"
+)
+
+// InventoryCollector собирает сведения о хосте (ОС, ядро, процессор, память, адреса) при каждом запуске;
+// период задает реестр (по умолчанию раз в час). Если сбор не удался, отдается прежняя копия
+// с обновленным временем работы.
+type InventoryCollector struct {
+	mu   sync.Mutex
+	last *models.HostInventory
+}
+
+func NewInventoryCollector() *InventoryCollector {
+	return &InventoryCollector{}
+}
+
+func (c *InventoryCollector) Collect(metrics *models.AgentMetrics) error {
+	inv, err := collectInventory()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.last = inv
+		metrics.Inventory = inv
+		return nil
+	}
+	if c.last == nil {
+		return err
+	}
+
+	prev := *c.last
+	if !prev.BootTime.IsZero() {
+		prev.UptimeSeconds = uint64(time.Since(prev.BootTime).Seconds())
+	}
+	metrics.Inventory = &prev
+	return err
+}
+
+// collectInventory собирает сведения о хосте
+func collectInventory() (*models.HostInventory, error) {
+	info, err := host.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &models.HostInventory{
+		Hostname:           info.Hostname,
+		OS:                 info.OS,
+		Platform:           info.Platform,
+		PlatformFamily:     info.PlatformFamily,
+		PlatformVersion:    info.PlatformVersion,
+		KernelVersion:      info.KernelVersion,
+		Arch:               info.KernelArch,
+		UptimeSeconds:      info.Uptime,
+		Virtualization:     info.VirtualizationSystem,
+		VirtualizationRole: info.VirtualizationRole,
+		MachineID:          info.HostID,
+		CollectedAt:        time.Now(),
+	}
+	if info.BootTime > 0 {
+		inv.BootTime = time.Unix(int64(info.BootTime), 0).UTC()
+	}
+
+	// Остальные сведения необязательны: ошибка не мешает отдать основную часть
+	if cpus, err := cpu.Info(); err == nil && len(cpus) > 0 {
+		inv.CPUModel = strings.TrimSpace(cpus[0].ModelName)
+	}
+	if cores, err := cpu.Counts(false); err == nil {
+		inv.CPUCores = cores
+	}
+	if threads, err := cpu.Counts(true); err == nil {
+		inv.CPUThreads = threads
+	}
+	if memory, err := mem.VirtualMemory(); err == nil {
+		inv.MemoryTotal = memory.Total
+	}
+	inv.IPAddresses = interfaceAddresses()
+
+	return inv, nil
+}
+
+// interfaceAddresses возвращает адреса активных интерфейсов без loopback и link-local
+func interfaceAddresses() []string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			addresses = append(addresses, ipNet.IP.String())
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
@@ -0,0 +1,28 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"testing"
+)
+
+func TestInventoryCollectorCollectsEveryRun(t *testing.T) {
+	c := NewInventoryCollector()
+
+	var first, second models.AgentMetrics
+	if err := c.Collect(&first); err != nil {
+		t.Skipf("host inventory unavailable: %v", err)
+	}
+	if err := c.Collect(&second); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if first.Inventory == nil || second.Inventory == nil {
+		t.Fatalf("inventory not reported")
+	}
+	// Период задает реестр, коллектор не отдает сохраненную копию
+	if !second.Inventory.CollectedAt.After(first.Inventory.CollectedAt) {
+		t.Errorf("second run reused inventory collected at %v", first.Inventory.CollectedAt)
+	}
+	if first.Inventory == second.Inventory {
+		t.Errorf("runs share the same inventory value")
+	}
+}
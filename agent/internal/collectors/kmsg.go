@@ -0,0 +1,304 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/host"
+)
+
+// DefaultKernelLogPath - журнал ядра, доступный на чтение root
+const DefaultKernelLogPath = "/dev/kmsg"
+
+const (
+	// kmsgRecordSize - максимальный размер записи /dev/kmsg; чтение возвращает одну запись целиком
+	kmsgRecordSize = 8192
+	// kernelEventBurst - сколько одинаковых событий (тип и объект) передается в минуту;
+	// остальные учитываются в атрибуте suppressed следующего события
+	kernelEventBurst = 10
+)
+
+// Типы событий ядра
+const (
+	KernelOOMKill  = "oom_kill"
+	KernelSegfault = "segfault"
+	KernelHungTask = "hung_task"
+	KernelFSError  = "fs_error"
+	KernelIOError  = "io_error"
+)
+
+var (
+	// oom-kill:constraint=CONSTRAINT_MEMCG,...,oom_memcg=/system.slice/x.service,task_memcg=/system.slice/x.service,task=java,pid=123,uid=0
+	kmsgOOMContextRe = regexp.MustCompile(`^oom-kill:(.*)`)
+	// Out of memory: Killed process 123 (java) total-vm:1000kB, anon-rss:500kB, file-rss:0kB, shmem-rss:0kB, UID:0 ...
+	// Memory cgroup out of memory: Killed process 123 (java) ..., в старых ядрах - без префикса
+	kmsgOOMKillRe = regexp.MustCompile(`^(?:(Memory cgroup out of memory|Out of memory)[^:]*: )?Killed process (\d+) \((.*?)\)(.*)`)
+	kmsgRSSRe     = regexp.MustCompile(`(anon|file|shmem)-rss:(\d+)kB`)
+	// java[123]: segfault at 0 ip 00007f0 sp 00007ff error 4 in libc.so.6[7f0+1000]
+	kmsgSegfaultRe = regexp.MustCompile(`^(.+?)\[(\d+)\]: segfault at (\S+) ip (\S+) sp \S+ error (\d+)(?: in ([^\[\s]+))?`)
+	// traps: java[123] general protection fault ip:7f0 sp:7ff error:0 in libc.so.6[7f0+1000]
+	kmsgTrapRe = regexp.MustCompile(`^traps: (.+?)\[(\d+)\] (.+?) ip:(\S+)(?:.* in ([^\[\s]+))?`)
+	// INFO: task kworker/0:1:123 blocked for more than 120 seconds.
+	kmsgHungTaskRe = regexp.MustCompile(`^INFO: task (.+):(\d+) blocked for more than (\d+) seconds`)
+	// EXT4-fs error (device sda1): ..., BTRFS error (device sda1: state EA): ..., XFS (sda1): Metadata corruption detected ...
+	kmsgExtErrorRe   = regexp.MustCompile(`^(EXT[234]-fs) error \(device ([^)]+)\): (.*)`)
+	kmsgBtrfsErrorRe = regexp.MustCompile(`^(BTRFS) (?:error|critical) \(device ([^)\s:]+)[^)]*\): (.*)`)
+	kmsgXFSErrorRe   = regexp.MustCompile(`^(XFS) \(([^)]+)\): (.*(?:[Cc]orruption|[Ee]rror|[Ss]hutdown).*)`)
+	// blk_update_request: I/O error, dev sda, sector 123 op 0x0:(READ) ...
+	kmsgIOErrorRe = regexp.MustCompile(`I/O error, dev (\w+), sector (\d+)`)
+)
+
+// KernelLogCollector читает журнал ядра /dev/kmsg и выделяет из него события: завершение процессов
+// OOM killer (процесс, cgroup, RSS), segfault, зависшие задачи и ошибки файловых систем и дисков.
+// Чтение начинается с конца журнала, поэтому после перезапуска агента старые сообщения не повторяются.
+type KernelLogCollector struct {
+	path     string
+	bootTime time.Time
+
+	// oomContext - cgroup из строки oom-kill по PID; ядро пишет ее перед строкой Killed process
+	oomContext map[string]map[string]string
+
+	mu     sync.Mutex
+	limits map[string]*kernelEventLimit
+}
+
+// kernelEventLimit - счетчик событий одного типа и объекта за текущую минуту
+type kernelEventLimit struct {
+	window     time.Time
+	count      int
+	suppressed int
+}
+
+// NewKernelLogCollector создает коллектор; пустой path означает /dev/kmsg.
+// Возвращает ошибку, если журнал недоступен (агент запущен не от root или в контейнере)
+func NewKernelLogCollector(path string) (*KernelLogCollector, error) {
+	if path == "" {
+		path = DefaultKernelLogPath
+	}
+	f, err := openKernelLog(path)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	c := &KernelLogCollector{
+		path:       path,
+		oomContext: make(map[string]map[string]string),
+		limits:     make(map[string]*kernelEventLimit),
+	}
+	if boot, err := host.BootTime(); err == nil {
+		c.bootTime = time.Unix(int64(boot), 0)
+	}
+	return c, nil
+}
+
+// Collect ничего не делает: события передаются через Watch
+func (c *KernelLogCollector) Collect(metrics *models.AgentMetrics) error {
+	return nil
+}
+
+// Watch читает новые записи журнала ядра до отмены контекста; после ошибки чтения журнал открывается заново
+func (c *KernelLogCollector) Watch(ctx context.Context, emit func(events ...models.Event)) {
+	for {
+		if err := c.follow(ctx, emit); err != nil && ctx.Err() == nil {
+			log.Printf("Kernel log read failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(30 * time.Second):
+		}
+	}
+}
+
+func (c *KernelLogCollector) follow(ctx context.Context, emit func(events ...models.Event)) error {
+	f, err := openKernelLog(c.path)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		f.Close()
+	}()
+
+	buf := make([]byte, kmsgRecordSize)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			// EPIPE означает, что часть записей перезаписана до чтения: продолжаем со следующей
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			return err
+		}
+		if e, ok := c.parseRecord(string(buf[:n]), time.Now()); ok {
+			emit(e)
+		}
+	}
+}
+
+// parseRecord разбирает запись /dev/kmsg вида "приоритет,номер,микросекунды,флаги;сообщение"
+// и возвращает событие, если сообщение соответствует одному из известных шаблонов
+func (c *KernelLogCollector) parseRecord(record string, now time.Time) (models.Event, bool) {
+	header, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return models.Event{}, false
+	}
+	// Строки после первой - словарь "ключ=значение" устройства
+	message, _, _ = strings.Cut(message, "\n")
+
+	timestamp := now
+	if fields := strings.Split(header, ","); len(fields) >= 3 && !c.bootTime.IsZero() {
+		// Время записи отсчитывается от загрузки без учета сна, поэтому после сна может оказаться в будущем
+		if usec, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			if t := c.bootTime.Add(time.Duration(usec) * time.Microsecond); t.Before(now) {
+				timestamp = t
+			}
+		}
+	}
+
+	e, ok := c.parseMessage(message)
+	if !ok {
+		return e, false
+	}
+	e.Source = "kernel"
+	e.Timestamp = timestamp
+	e.Message = message
+	return e, c.allow(&e, now)
+}
+
+// parseMessage определяет тип события и его атрибуты по тексту сообщения
+func (c *KernelLogCollector) parseMessage(message string) (models.Event, bool) {
+	if m := kmsgOOMContextRe.FindStringSubmatch(message); m != nil {
+		attrs := make(map[string]string)
+		for _, kv := range strings.Split(m[1], ",") {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				attrs[k] = v
+			}
+		}
+		if pid := attrs["pid"]; pid != "" {
+			// Контекст нужен только до следующей строки Killed process того же PID
+			for k := range c.oomContext {
+				delete(c.oomContext, k)
+			}
+			c.oomContext[pid] = attrs
+		}
+		return models.Event{}, false
+	}
+
+	if m := kmsgOOMKillRe.FindStringSubmatch(message); m != nil {
+		attrs := map[string]string{"pid": m[2]}
+		if strings.HasPrefix(m[1], "Memory cgroup") {
+			attrs["constraint"] = "memcg"
+		}
+		var rss int64
+		for _, r := range kmsgRSSRe.FindAllStringSubmatch(m[4], -1) {
+			kb, _ := strconv.ParseInt(r[2], 10, 64)
+			attrs[r[1]+"_rss_kb"] = r[2]
+			rss += kb
+		}
+		attrs["rss_kb"] = strconv.FormatInt(rss, 10)
+		if ctx, ok := c.oomContext[m[2]]; ok {
+			if cg := ctx["task_memcg"]; cg != "" {
+				attrs["cgroup"] = cg
+			}
+			if cg := ctx["oom_memcg"]; cg != "" {
+				attrs["oom_cgroup"] = cg
+			}
+			delete(c.oomContext, m[2])
+		}
+		return models.Event{Type: KernelOOMKill, Object: m[3], Attributes: attrs}, true
+	}
+
+	if m := kmsgSegfaultRe.FindStringSubmatch(message); m != nil {
+		attrs := map[string]string{"pid": m[2], "address": m[3], "ip": m[4], "error": m[5]}
+		if m[6] != "" {
+			attrs["module"] = m[6]
+		}
+		return models.Event{Type: KernelSegfault, Object: m[1], Attributes: attrs}, true
+	}
+
+	if m := kmsgTrapRe.FindStringSubmatch(message); m != nil {
+		attrs := map[string]string{"pid": m[2], "fault": m[3], "ip": m[4]}
+		if m[5] != "" {
+			attrs["module"] = m[5]
+		}
+		return models.Event{Type: KernelSegfault, Object: m[1], Attributes: attrs}, true
+	}
+
+	if m := kmsgHungTaskRe.FindStringSubmatch(message); m != nil {
+		return models.Event{
+			Type:       KernelHungTask,
+			Object:     m[1],
+			Attributes: map[string]string{"pid": m[2], "blocked_seconds": m[3]},
+		}, true
+	}
+
+	for _, re := range []*regexp.Regexp{kmsgExtErrorRe, kmsgBtrfsErrorRe, kmsgXFSErrorRe} {
+		if m := re.FindStringSubmatch(message); m != nil {
+			return models.Event{
+				Type:       KernelFSError,
+				Object:     m[2],
+				Attributes: map[string]string{"filesystem": m[1], "error": m[3]},
+			}, true
+		}
+	}
+
+	if m := kmsgIOErrorRe.FindStringSubmatch(message); m != nil {
+		return models.Event{
+			Type:       KernelIOError,
+			Object:     m[1],
+			Attributes: map[string]string{"sector": m[2]},
+		}, true
+	}
+	return models.Event{}, false
+}
+
+// allow ограничивает число одинаковых событий в минуту, чтобы поток ошибок диска
+// не вытеснил из буфера остальные события
+func (c *KernelLogCollector) allow(e *models.Event, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := e.Type + "\x00" + e.Object
+	l, ok := c.limits[key]
+	if !ok {
+		l = &kernelEventLimit{}
+		c.limits[key] = l
+	}
+	if now.Sub(l.window) >= time.Minute {
+		l.window = now
+		l.count = 0
+	}
+	if l.count >= kernelEventBurst {
+		l.suppressed++
+		return false
+	}
+	l.count++
+	if l.suppressed > 0 {
+		e.Attributes["suppressed"] = fmt.Sprint(l.suppressed)
+		l.suppressed = 0
+	}
+
+	// Счетчики давно не встречавшихся событий не нужны
+	for k, v := range c.limits {
+		if now.Sub(v.window) > time.Hour {
+			delete(c.limits, k)
+		}
+	}
+	return true
+}
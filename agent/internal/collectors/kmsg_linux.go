@@ -0,0 +1,23 @@
+//go:build linux
+
+package collectors
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// openKernelLog открывает /dev/kmsg и переходит в конец журнала. Неблокирующий дескриптор
+// регистрируется в планировщике Go, поэтому Close прерывает ожидающий Read
+func openKernelLog(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
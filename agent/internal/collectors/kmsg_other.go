@@ -0,0 +1,13 @@
+//go:build !linux
+
+package collectors
+
+import (
+	"errors"
+	"os"
+)
+
+// openKernelLog: журнал ядра в формате /dev/kmsg есть только в Linux
+func openKernelLog(path string) (*os.File, error) {
+	return nil, errors.New("kernel log is supported only on Linux")
+}
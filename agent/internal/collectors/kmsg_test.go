@@ -0,0 +1,205 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestKernelLogCollector создает коллектор без открытия /dev/kmsg; система загружена в boot
+func newTestKernelLogCollector(boot time.Time) *KernelLogCollector {
+	return &KernelLogCollector{
+		bootTime:   boot,
+		oomContext: make(map[string]map[string]string),
+		limits:     make(map[string]*kernelEventLimit),
+	}
+}
+
+func TestKernelLogParseRecord(t *testing.T) {
+	boot := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	now := boot.Add(30 * time.Hour)
+
+	tests := []struct {
+		name    string
+		records []string // Записи /dev/kmsg; событие ожидается от последней
+		want    *models.Event
+	}{
+		{
+			name: "memcg oom kill",
+			records: []string{
+				"6,1523,86400000000,-;oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=/,mems_allowed=0," +
+					"oom_memcg=/system.slice/app.service,task_memcg=/system.slice/app.service,task=java,pid=48213,uid=1001",
+				"3,1524,86400000100,-;Memory cgroup out of memory: Killed process 48213 (java) total-vm:8123456kB, " +
+					"anon-rss:2097152kB, file-rss:1024kB, shmem-rss:0kB, UID:1001 pgtables:4500kB oom_score_adj:0",
+			},
+			want: &models.Event{
+				Type:   KernelOOMKill,
+				Object: "java",
+				Attributes: map[string]string{
+					"pid": "48213", "constraint": "memcg", "anon_rss_kb": "2097152", "file_rss_kb": "1024",
+					"shmem_rss_kb": "0", "rss_kb": "2098176",
+					"cgroup": "/system.slice/app.service", "oom_cgroup": "/system.slice/app.service",
+				},
+				Timestamp: boot.Add(86400000100 * time.Microsecond),
+			},
+		},
+		{
+			name: "global oom kill without context",
+			records: []string{
+				"3,201,5000000,-;Out of memory: Killed process 911 (postgres) total-vm:3145728kB, anon-rss:1048576kB, " +
+					"file-rss:0kB, shmem-rss:65536kB, UID:113 pgtables:2300kB oom_score_adj:-900",
+			},
+			want: &models.Event{
+				Type:   KernelOOMKill,
+				Object: "postgres",
+				Attributes: map[string]string{
+					"pid": "911", "anon_rss_kb": "1048576", "file_rss_kb": "0", "shmem_rss_kb": "65536", "rss_kb": "1114112",
+				},
+				Timestamp: boot.Add(5 * time.Second),
+			},
+		},
+		{
+			name:    "segfault",
+			records: []string{"6,3012,7200000000,-;nginx[2291]: segfault at 0 ip 00007f3a2c1b5e1d sp 00007ffd6a1c38a8 error 4 in libc.so.6[7f3a2c028000+195000] likely on CPU 3 (core 3, socket 0)"},
+			want: &models.Event{
+				Type:       KernelSegfault,
+				Object:     "nginx",
+				Attributes: map[string]string{"pid": "2291", "address": "0", "ip": "00007f3a2c1b5e1d", "error": "4", "module": "libc.so.6"},
+				Timestamp:  boot.Add(2 * time.Hour),
+			},
+		},
+		{
+			name:    "trap",
+			records: []string{"6,3013,7200000000,-;traps: python3[7781] general protection fault ip:7f01c4d3a1b2 sp:7ffc9e8a7f40 error:0 in libpython3.10.so.1.0[7f01c4c00000+2c0000]"},
+			want: &models.Event{
+				Type:       KernelSegfault,
+				Object:     "python3",
+				Attributes: map[string]string{"pid": "7781", "fault": "general protection fault", "ip": "7f01c4d3a1b2", "module": "libpython3.10.so.1.0"},
+				Timestamp:  boot.Add(2 * time.Hour),
+			},
+		},
+		{
+			name:    "hung task",
+			records: []string{"3,4410,10800000000,-;INFO: task jbd2/sda1-8:312 blocked for more than 120 seconds."},
+			want: &models.Event{
+				Type:       KernelHungTask,
+				Object:     "jbd2/sda1-8",
+				Attributes: map[string]string{"pid": "312", "blocked_seconds": "120"},
+				Timestamp:  boot.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:    "ext4 error",
+			records: []string{"2,5120,10800000000,-;EXT4-fs error (device sdb1): ext4_find_entry:1683: inode #2: comm ls: reading directory lblock 0\nSUBSYSTEM=block\nDEVICE=b8:17"},
+			want: &models.Event{
+				Type:       KernelFSError,
+				Object:     "sdb1",
+				Attributes: map[string]string{"filesystem": "EXT4-fs", "error": "ext4_find_entry:1683: inode #2: comm ls: reading directory lblock 0"},
+				Timestamp:  boot.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:    "btrfs error",
+			records: []string{"3,5121,10800000000,-;BTRFS error (device nvme0n1p3: state EA): bdev /dev/nvme0n1p3 errs: wr 0, rd 0, flush 0, corrupt 1, gen 0"},
+			want: &models.Event{
+				Type:       KernelFSError,
+				Object:     "nvme0n1p3",
+				Attributes: map[string]string{"filesystem": "BTRFS", "error": "bdev /dev/nvme0n1p3 errs: wr 0, rd 0, flush 0, corrupt 1, gen 0"},
+				Timestamp:  boot.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:    "xfs corruption",
+			records: []string{"1,5122,10800000000,-;XFS (dm-0): Corruption of in-memory data (0x8) detected at xfs_trans_cancel+0x13b/0x160 [xfs]. Shutting down filesystem."},
+			want: &models.Event{
+				Type:       KernelFSError,
+				Object:     "dm-0",
+				Attributes: map[string]string{"filesystem": "XFS", "error": "Corruption of in-memory data (0x8) detected at xfs_trans_cancel+0x13b/0x160 [xfs]. Shutting down filesystem."},
+				Timestamp:  boot.Add(3 * time.Hour),
+			},
+		},
+		{
+			name:    "io error",
+			records: []string{"3,6001,10800000000,-;blk_update_request: I/O error, dev sdc, sector 2048 op 0x0:(READ) flags 0x0 phys_seg 1 prio class 0"},
+			want: &models.Event{
+				Type:       KernelIOError,
+				Object:     "sdc",
+				Attributes: map[string]string{"sector": "2048"},
+				Timestamp:  boot.Add(3 * time.Hour),
+			},
+		},
+		{
+			// Время записи после сна системы оказывается в будущем и заменяется текущим
+			name:    "timestamp after suspend",
+			records: []string{"3,6002,144000000000,-;INFO: task kworker/u8:2:1187 blocked for more than 241 seconds."},
+			want: &models.Event{
+				Type:       KernelHungTask,
+				Object:     "kworker/u8:2",
+				Attributes: map[string]string{"pid": "1187", "blocked_seconds": "241"},
+				Timestamp:  now,
+			},
+		},
+		{name: "xfs info", records: []string{"5,900,3000000,-;XFS (sda2): Mounting V5 Filesystem"}},
+		{name: "unrelated message", records: []string{"6,901,3000000,-;e1000e: eth0 NIC Link is Up 1000 Mbps Full Duplex"}},
+		{name: "no header", records: []string{"Out of memory: Killed process 911 (postgres)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestKernelLogCollector(boot)
+			var got models.Event
+			var ok bool
+			for _, r := range tt.records {
+				got, ok = c.parseRecord(r, now)
+			}
+			if tt.want == nil {
+				if ok {
+					t.Errorf("unexpected event %+v", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no event")
+			}
+			want := *tt.want
+			want.Source = "kernel"
+			want.Message = got.Message
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("event:\n got %+v\nwant %+v", got, want)
+			}
+			if got.Message == "" || got.Message[len(got.Message)-1] == '\n' {
+				t.Errorf("message = %q", got.Message)
+			}
+		})
+	}
+}
+
+func TestKernelLogRateLimit(t *testing.T) {
+	c := newTestKernelLogCollector(time.Time{})
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	record := "3,6001,10800000000,-;blk_update_request: I/O error, dev sdc, sector 2048 op 0x0:(READ)"
+
+	for i := 0; i < kernelEventBurst; i++ {
+		if _, ok := c.parseRecord(record, now); !ok {
+			t.Fatalf("event %d suppressed", i+1)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if _, ok := c.parseRecord(record, now.Add(time.Second)); ok {
+			t.Fatalf("event above the burst limit passed")
+		}
+	}
+	// События другого объекта не ограничиваются
+	if _, ok := c.parseRecord("3,6002,10800000000,-;blk_update_request: I/O error, dev sdd, sector 8", now); !ok {
+		t.Errorf("event of another device suppressed")
+	}
+
+	// Через минуту событие проходит с числом пропущенных
+	e, ok := c.parseRecord(record, now.Add(time.Minute))
+	if !ok || e.Attributes["suppressed"] != "5" {
+		t.Errorf("event after a minute = %+v, %v", e, ok)
+	}
+	if e.Timestamp != now.Add(time.Minute) {
+		t.Errorf("timestamp without boot time = %v, want the current time", e.Timestamp)
+	}
+}
@@ -0,0 +1,523 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// logOffsetsFile - файл в каталоге состояния агента с позициями чтения журналов
+	logOffsetsFile = "log_offsets.json"
+	// maxLogBytesPerCollect ограничивает объем чтения за один сбор; остаток дочитывается в следующий раз
+	maxLogBytesPerCollect = 64 << 20
+	// maxLogSampleLength - максимальная длина строки-примера
+	maxLogSampleLength = 512
+)
+
+// logCountWindows - скользящие окна, за которые считаются совпадения (log.<журнал>.<шаблон>_5m в алертах).
+// Окна не зависят от того, как часто ЦМ опрашивает агент: совпадения не теряются и не считаются дважды
+var logCountWindows = []struct {
+	label  string
+	period time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+}
+
+// LogCollector дочитывает настроенные журналы и считает строки, совпавшие с именованными шаблонами.
+// Позиции чтения сохраняются в каталоге состояния, поэтому после перезапуска агент продолжает с того же места.
+type LogCollector struct {
+	stateFile string
+
+	mu      sync.Mutex
+	sources []logSource
+	tails   map[string]*logTail  // состояние чтения по имени журнала
+	history map[string][]logRead // чтения за самое длинное окно по имени журнала
+}
+
+// logRead - результат одного чтения журнала для подсчета совпадений за окна
+type logRead struct {
+	at     time.Time
+	lines  int
+	counts map[string]int
+}
+
+// logSource - журнал со скомпилированными шаблонами
+type logSource struct {
+	name        string
+	path        string
+	patterns    []logPattern
+	sampleLines int
+}
+
+type logPattern struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// logTail - открытый журнал и позиция чтения в нем
+type logTail struct {
+	path   string
+	file   *os.File
+	id     uint64
+	offset int64
+	// rotatedTo - путь, по которому уже лежит новый файл; старый дочитывается перед переключением
+	rotatedTo string
+}
+
+// logOffset - сохраняемая позиция чтения журнала
+type logOffset struct {
+	Path   string `json:"path"`
+	ID     uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// NewLogCollector создает коллектор; stateDir - каталог для файла позиций (пустой - позиции не сохраняются)
+func NewLogCollector(stateDir string, sources []models.LogSource) (*LogCollector, error) {
+	compiled, err := compileLogSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &LogCollector{
+		sources: compiled,
+		tails:   make(map[string]*logTail),
+		history: make(map[string][]logRead),
+	}
+	if stateDir != "" {
+		c.stateFile = filepath.Join(stateDir, logOffsetsFile)
+	}
+	return c, nil
+}
+
+// compileLogSources проверяет описание журналов и компилирует шаблоны
+func compileLogSources(sources []models.LogSource) ([]logSource, error) {
+	compiled := make([]logSource, 0, len(sources))
+	names := make(map[string]bool)
+	for _, src := range sources {
+		if src.Name == "" || src.Path == "" {
+			return nil, errors.New("log source requires name and path")
+		}
+		if names[src.Name] {
+			return nil, fmt.Errorf("duplicate log source %q", src.Name)
+		}
+		names[src.Name] = true
+
+		ls := logSource{name: src.Name, path: src.Path, sampleLines: src.SampleLines}
+		for _, p := range src.Patterns {
+			if p.Name == "" {
+				return nil, fmt.Errorf("log %s: pattern requires name", src.Name)
+			}
+			re, err := regexp.Compile(p.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("log %s: invalid regex for %s: %w", src.Name, p.Name, err)
+			}
+			ls.patterns = append(ls.patterns, logPattern{name: p.Name, regex: re})
+		}
+		compiled = append(compiled, ls)
+	}
+	return compiled, nil
+}
+
+// SetSources заменяет список журналов. Позиции и счетчики за окна журналов, оставшихся в списке, сохраняются.
+func (c *LogCollector) SetSources(sources []models.LogSource) error {
+	compiled, err := compileLogSources(sources)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keep := make(map[string]bool)
+	for _, src := range compiled {
+		keep[src.name] = true
+	}
+	for name, tail := range c.tails {
+		if !keep[name] || tail.path != c.sourcePath(compiled, name) {
+			tail.close()
+			delete(c.tails, name)
+		}
+	}
+	for name := range c.history {
+		if !keep[name] || c.sourcePath(c.sources, name) != c.sourcePath(compiled, name) {
+			delete(c.history, name)
+		}
+	}
+	c.sources = compiled
+	return nil
+}
+
+// logConfigSchema - схема настроек коллектора logs
+var logConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"sources": {
+			"type": "array",
+			"description": "Отслеживаемые журналы",
+			"items": {
+				"type": "object",
+				"required": ["name", "path"],
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"path": {"type": "string", "minLength": 1},
+					"sample_lines": {"type": "integer", "minimum": 0},
+					"patterns": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"required": ["name", "regex"],
+							"properties": {
+								"name": {"type": "string", "minLength": 1},
+								"regex": {"type": "string", "format": "regex"}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`)
+
+// LogConfig - настройки коллектора logs
+type LogConfig struct {
+	Sources []models.LogSource `json:"sources"`
+}
+
+func (c *LogCollector) ConfigSchema() *Schema {
+	return logConfigSchema
+}
+
+func (c *LogCollector) Config() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := LogConfig{Sources: make([]models.LogSource, 0, len(c.sources))}
+	for _, src := range c.sources {
+		spec := models.LogSource{Name: src.name, Path: src.path, SampleLines: src.sampleLines, Patterns: []models.LogPattern{}}
+		for _, p := range src.patterns {
+			spec.Patterns = append(spec.Patterns, models.LogPattern{Name: p.name, Regex: p.regex.String()})
+		}
+		cfg.Sources = append(cfg.Sources, spec)
+	}
+	return cfg
+}
+
+func (c *LogCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(LogConfig)
+	if err := decodeConfig(logConfigSchema, raw, &cfg); err != nil {
+		return err
+	}
+	return c.SetSources(cfg.Sources)
+}
+
+func (c *LogCollector) sourcePath(sources []logSource, name string) string {
+	for _, src := range sources {
+		if src.name == name {
+			return src.path
+		}
+	}
+	return ""
+}
+
+func (c *LogCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.sources) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	saved := c.loadOffsets()
+	var errs []error
+	infos := make([]models.LogFileInfo, 0, len(c.sources))
+	for _, src := range c.sources {
+		info := models.LogFileInfo{
+			Name:   src.name,
+			Path:   src.path,
+			Counts: make(map[string]int, len(src.patterns)),
+		}
+		for _, p := range src.patterns {
+			info.Counts[p.name] = 0
+		}
+
+		tail, err := c.tailFor(src, saved)
+		if err == nil {
+			err = tail.read(&info, func(line []byte) { matchLogLine(src, &info, line) })
+			info.Offset = tail.offset
+		}
+		if err != nil {
+			info.Error = err.Error()
+			errs = append(errs, fmt.Errorf("log %s: %w", src.name, err))
+		}
+		info.Windows = c.countWindows(src, info, now)
+		infos = append(infos, info)
+	}
+
+	c.saveOffsets()
+	metrics.Logs = infos
+	return errors.Join(errs...)
+}
+
+// countWindows запоминает результат чтения и считает строки и совпадения за окна logCountWindows
+func (c *LogCollector) countWindows(src logSource, info models.LogFileInfo, now time.Time) map[string]models.LogWindow {
+	longest := logCountWindows[len(logCountWindows)-1].period
+	history := append(c.history[src.name], logRead{at: now, lines: info.Lines, counts: info.Counts})
+	expired := 0
+	for expired < len(history) && now.Sub(history[expired].at) >= longest {
+		expired++
+	}
+	if expired > 0 {
+		history = append([]logRead(nil), history[expired:]...)
+	}
+	c.history[src.name] = history
+
+	windows := make(map[string]models.LogWindow, len(logCountWindows))
+	for _, w := range logCountWindows {
+		window := models.LogWindow{Counts: make(map[string]int, len(src.patterns))}
+		for _, p := range src.patterns {
+			window.Counts[p.name] = 0
+		}
+		for _, r := range history {
+			if now.Sub(r.at) >= w.period {
+				continue
+			}
+			window.Lines += r.lines
+			for name, n := range r.counts {
+				// Совпадения удаленных шаблонов не учитываются
+				if _, ok := window.Counts[name]; ok {
+					window.Counts[name] += n
+				}
+			}
+		}
+		windows[w.label] = window
+	}
+	return windows
+}
+
+// tailFor возвращает открытый журнал, обрабатывая ротацию (файл заменен) и усечение (copytruncate)
+func (c *LogCollector) tailFor(src logSource, saved map[string]logOffset) (*logTail, error) {
+	tail := c.tails[src.name]
+	if tail != nil {
+		tail.checkRotation()
+		return tail, nil
+	}
+
+	var off *logOffset
+	if o, ok := saved[src.name]; ok {
+		off = &o
+	}
+	tail, err := openLogTail(src.path, off)
+	if err != nil {
+		return nil, err
+	}
+	c.tails[src.name] = tail
+	return tail, nil
+}
+
+// openLogTail открывает журнал. Чтение продолжается с сохраненной позиции, если это тот же файл;
+// иначе начинается с конца, чтобы не считать старые строки как новые
+func openLogTail(path string, saved *logOffset) (*logTail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	tail := &logTail{path: path, file: file, id: fileID(info)}
+	if saved != nil && saved.Path == path && saved.ID == tail.id && saved.Offset <= info.Size() {
+		tail.offset = saved.Offset
+	} else {
+		tail.offset = info.Size()
+	}
+	return tail, nil
+}
+
+// checkRotation отмечает замену файла по пути журнала и сбрасывает позицию при усечении
+func (t *logTail) checkRotation() {
+	current, err := os.Stat(t.path)
+	if err != nil {
+		// Файл переименован, а новый еще не создан: дочитываем старый
+		return
+	}
+	opened, err := t.file.Stat()
+	if err == nil && os.SameFile(opened, current) {
+		if current.Size() < t.offset {
+			t.offset = 0
+		}
+		return
+	}
+
+	// Ротация: дочитывать старый файл будем в этом сборе, затем переключимся на новый
+	t.rotatedTo = t.path
+}
+
+// read дочитывает новые полные строки и передает их в handle.
+// Незавершенная строка в конце файла остается до следующего сбора.
+func (t *logTail) read(info *models.LogFileInfo, handle func(line []byte)) error {
+	for {
+		n, err := t.readFile(info, handle)
+		if err != nil {
+			return err
+		}
+		if t.rotatedTo == "" || n >= maxLogBytesPerCollect {
+			return nil
+		}
+
+		// Старый файл дочитан, переходим к новому с начала
+		file, err := os.Open(t.rotatedTo)
+		if err != nil {
+			return err
+		}
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return err
+		}
+		t.file.Close()
+		t.file = file
+		t.id = fileID(stat)
+		t.offset = 0
+		t.rotatedTo = ""
+		info.Rotated = true
+	}
+}
+
+func (t *logTail) readFile(info *models.LogFileInfo, handle func(line []byte)) (int64, error) {
+	if _, err := t.file.Seek(t.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReaderSize(io.LimitReader(t.file, maxLogBytesPerCollect), 64*1024)
+	var read int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			read += int64(len(line))
+			info.Lines++
+			handle(line[:len(line)-1])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return read, err
+		}
+	}
+	t.offset += read
+	info.BytesRead += read
+	return read, nil
+}
+
+// matchLogLine увеличивает счетчики шаблонов, под которые попала строка
+func matchLogLine(src logSource, info *models.LogFileInfo, line []byte) {
+	for _, p := range src.patterns {
+		if !p.regex.Match(line) {
+			continue
+		}
+		info.Counts[p.name]++
+		if src.sampleLines > 0 && len(info.Samples[p.name]) < src.sampleLines {
+			if info.Samples == nil {
+				info.Samples = make(map[string][]string)
+			}
+			info.Samples[p.name] = append(info.Samples[p.name], logSample(line))
+		}
+	}
+}
+
+// logSample обрезает строку-пример до maxLogSampleLength, не разрывая символы UTF-8
+func logSample(line []byte) string {
+	if len(line) <= maxLogSampleLength {
+		return string(line)
+	}
+	cut := maxLogSampleLength
+	for cut > 0 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return string(line[:cut]) + "..."
+}
+
+func (t *logTail) close() {
+	if t.file != nil {
+		t.file.Close()
+	}
+}
+
+// loadOffsets читает сохраненные позиции; отсутствие файла не считается ошибкой
+func (c *LogCollector) loadOffsets() map[string]logOffset {
+	offsets := make(map[string]logOffset)
+	if c.stateFile == "" {
+		return offsets
+	}
+	data, err := os.ReadFile(c.stateFile)
+	if err != nil {
+		return offsets
+	}
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		log.Printf("Ignoring corrupted %s: %v", c.stateFile, err)
+	}
+	return offsets
+}
+
+// saveOffsets атомарно записывает позиции: временный файл и переименование
+func (c *LogCollector) saveOffsets() {
+	if c.stateFile == "" {
+		return
+	}
+	offsets := make(map[string]logOffset, len(c.tails))
+	for name, tail := range c.tails {
+		offsets[name] = logOffset{Path: tail.path, ID: tail.id, Offset: tail.offset}
+	}
+	data, err := json.MarshalIndent(offsets, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := WriteFileAtomic(c.stateFile, data, 0o600); err != nil {
+		log.Printf("Failed to save log offsets: %v", err)
+	}
+}
+
+// WriteFileAtomic записывает файл через временный файл в том же каталоге и rename,
+// чтобы при сбое на диске не оставалось наполовину записанного состояния
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,161 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLogCollectorSetConfig(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app.log")
+	other := filepath.Join(dir, "other.log")
+	if err := os.WriteFile(app, []byte("ERROR before start\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, []byte("panic: before start\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	appendLine := func(path, line string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := NewLogCollector("", []models.LogSource{
+		{Name: "app", Path: app, Patterns: []models.LogPattern{{Name: "error", Regex: `\bERROR\b`}}},
+	})
+	if err != nil {
+		t.Fatalf("NewLogCollector: %v", err)
+	}
+	// Новый журнал читается с конца
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	appendLine(app, "ERROR one")
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if got := metrics.Logs[0].Counts["error"]; got != 1 {
+		t.Fatalf("error count = %d, want 1", got)
+	}
+	if got := metrics.Logs[0].Windows["5m"].Counts["error"]; got != 1 {
+		t.Fatalf("5m error count = %d, want 1", got)
+	}
+
+	invalid := []string{
+		`{"sources": [{"name": "app"}]}`,
+		`{"sources": [{"name": "app", "path": "/x", "patterns": [{"name": "bad", "regex": "("}]}]}`,
+		`{"sources": [{"name": "app", "path": "/x"}, {"name": "app", "path": "/y"}]}`,
+		`{"sources": [{"name": "app", "path": "/x", "sample_lines": -1}]}`,
+	}
+	for _, raw := range invalid {
+		if err := c.SetConfig([]byte(raw)); err == nil {
+			t.Errorf("SetConfig(%s) succeeded", raw)
+		}
+	}
+
+	raw := `{"sources": [
+		{"name": "app", "path": "` + app + `", "patterns": [{"name": "error", "regex": "\\bERROR\\b"}]},
+		{"name": "other", "path": "` + other + `", "sample_lines": 2, "patterns": [{"name": "panic", "regex": "^panic:"}]}
+	]}`
+	if err := c.SetConfig([]byte(raw)); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	cfg := c.Config().(LogConfig)
+	if len(cfg.Sources) != 2 || cfg.Sources[1].SampleLines != 2 || cfg.Sources[1].Patterns[0].Regex != "^panic:" {
+		t.Fatalf("config = %+v", cfg)
+	}
+
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// Журнал, оставшийся в списке, продолжает читаться с прежней позиции
+	appendLine(app, "ERROR two")
+	appendLine(other, "panic: boom")
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(metrics.Logs) != 2 {
+		t.Fatalf("got %d logs, want 2", len(metrics.Logs))
+	}
+	if got := metrics.Logs[0].Counts["error"]; got != 1 {
+		t.Errorf("app error count = %d, want 1 (only the new line)", got)
+	}
+	// Счетчики за окно журнала, оставшегося в списке, учитывают и чтения до смены настроек
+	if got := metrics.Logs[0].Windows["5m"].Counts["error"]; got != 2 {
+		t.Errorf("app 5m error count = %d, want 2", got)
+	}
+	if got := metrics.Logs[1].Counts["panic"]; got != 1 {
+		t.Errorf("other panic count = %d, want 1", got)
+	}
+}
+
+func TestLogCollectorWindows(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(app, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewLogCollector("", []models.LogSource{
+		{Name: "app", Path: app, Patterns: []models.LogPattern{{Name: "error", Regex: `\bERROR\b`}}},
+	})
+	if err != nil {
+		t.Fatalf("NewLogCollector: %v", err)
+	}
+	src := c.sources[0]
+	now := time.Now()
+
+	// Чтения 50, 10 и 3 минуты назад и только что
+	reads := []struct {
+		ago   time.Duration
+		lines int
+		count int
+	}{
+		{ago: 50 * time.Minute, lines: 7, count: 4},
+		{ago: 10 * time.Minute, lines: 5, count: 2},
+		{ago: 3 * time.Minute, lines: 3, count: 1},
+		{ago: 0, lines: 2, count: 1},
+	}
+	var windows map[string]models.LogWindow
+	for _, r := range reads {
+		info := models.LogFileInfo{Lines: r.lines, Counts: map[string]int{"error": r.count}}
+		windows = c.countWindows(src, info, now.Add(-r.ago))
+	}
+	want := map[string]models.LogWindow{
+		"1m":  {Lines: 2, Counts: map[string]int{"error": 1}},
+		"5m":  {Lines: 5, Counts: map[string]int{"error": 2}},
+		"15m": {Lines: 10, Counts: map[string]int{"error": 4}},
+		"1h":  {Lines: 17, Counts: map[string]int{"error": 8}},
+	}
+	if !reflect.DeepEqual(windows, want) {
+		t.Errorf("windows = %+v, want %+v", windows, want)
+	}
+
+	// Чтения старше часа забываются
+	windows = c.countWindows(src, models.LogFileInfo{Counts: map[string]int{"error": 0}}, now.Add(time.Hour))
+	if got := len(c.history["app"]); got != 1 {
+		t.Errorf("history keeps %d reads, want 1", got)
+	}
+	if got := windows["1h"].Counts["error"]; got != 0 {
+		t.Errorf("1h error count = %d, want 0", got)
+	}
+
+	// Счетчики журнала, удаленного из настроек, сбрасываются
+	if err := c.SetSources(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.history["app"]; ok {
+		t.Errorf("history kept for a removed log")
+	}
+}
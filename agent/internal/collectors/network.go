@@ -4,13 +4,65 @@ import (
 	"agent/internal/models"
 	"fmt"
 	"github.com/shirou/gopsutil/net"
+	"sync"
 )
 
 // NetworkCollector собирает информацию о TCP и UDP портах
-type NetworkCollector struct{}
+type NetworkCollector struct {
+	mu      sync.Mutex
+	options map[string]bool // tcp, udp
+}
 
 func NewNetworkCollector() *NetworkCollector {
-	return &NetworkCollector{}
+	return &NetworkCollector{
+		options: map[string]bool{"tcp": true, "udp": true},
+	}
+}
+
+// Options возвращает отслеживаемые протоколы
+func (c *NetworkCollector) Options() map[string]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return copyOptions(c.options)
+}
+
+// SetOptions включает и отключает отслеживание TCP и UDP
+func (c *NetworkCollector) SetOptions(options map[string]bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return setOptions(c.options, options)
+}
+
+// networkConfigSchema - схема настроек коллектора network
+var networkConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"tcp": {"type": "boolean", "description": "Собирать TCP-порты"},
+		"udp": {"type": "boolean", "description": "Собирать UDP-порты"}
+	}
+}`)
+
+// NetworkConfig - настройки коллектора network
+type NetworkConfig struct {
+	TCP bool `json:"tcp"`
+	UDP bool `json:"udp"`
+}
+
+func (c *NetworkCollector) ConfigSchema() *Schema {
+	return networkConfigSchema
+}
+
+func (c *NetworkCollector) Config() interface{} {
+	options := c.Options()
+	return NetworkConfig{TCP: options["tcp"], UDP: options["udp"]}
+}
+
+func (c *NetworkCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(NetworkConfig)
+	if err := decodeConfig(networkConfigSchema, raw, &cfg); err != nil {
+		return err
+	}
+	return c.SetOptions(map[string]bool{"tcp": cfg.TCP, "udp": cfg.UDP})
 }
 
 // func (c *NetworkCollector) Collect(metrics *models.AgentMetrics) error {
@@ -52,25 +104,28 @@ func NewNetworkCollector() *NetworkCollector {
 // 	return nil
 // }
 
-func (c *NetworkCollector) ChangeConfig(collType CollectorType, newconfig []string) {
-	if collType == Network {
-	}
-}
-
 func (c *NetworkCollector) Collect(metrics *models.AgentMetrics) error {
+	options := c.Options()
+	var connections []net.ConnectionStat
+
 	// Собираем TCP соединения
-	tcpConns, err := net.Connections("tcp")
-	if err != nil {
-		return err
+	if options["tcp"] {
+		tcpConns, err := net.Connections("tcp")
+		if err != nil {
+			return err
+		}
+		connections = append(connections, tcpConns...)
 	}
 
 	// Собираем UDP соединения
-	udpConns, err := net.Connections("udp")
-	if err != nil {
-		return err
+	if options["udp"] {
+		udpConns, err := net.Connections("udp")
+		if err != nil {
+			return err
+		}
+		connections = append(connections, udpConns...)
 	}
 
-	connections := append(tcpConns, udpConns...)
 	portMap := make(map[string]models.PortInfo)
 
 	for _, conn := range connections {
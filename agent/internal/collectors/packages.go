@@ -0,0 +1,331 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// packageRescanInterval - период принудительного перечитывания базы пакетов,
+	// даже если время изменения ее файлов не поменялось
+	packageRescanInterval = time.Hour
+	// rpmQueryTimeout ограничивает время выполнения rpm -qa
+	rpmQueryTimeout = 60 * time.Second
+	// dpkgStatusPath - база установленных пакетов dpkg
+	dpkgStatusPath = "/var/lib/dpkg/status"
+)
+
+// Менеджеры пакетов
+const (
+	PackageManagerDpkg = "dpkg"
+	PackageManagerRPM  = "rpm"
+)
+
+// rpmDatabasePaths - файлы базы rpm, по времени изменения которых определяется установка пакетов
+var rpmDatabasePaths = []string{
+	"/var/lib/rpm/rpmdb.sqlite",
+	"/var/lib/rpm/Packages",
+	"/usr/lib/sysimage/rpm/rpmdb.sqlite",
+}
+
+// PackageCollector собирает список установленных пакетов из базы dpkg или вывода rpm -qa.
+// Первый отчет содержит полный список, следующие - только изменения относительно предыдущего списка.
+type PackageCollector struct {
+	manager string
+	dbPath  string // файл базы, время изменения которого отслеживается
+
+	mu        sync.Mutex
+	packages  []models.Package
+	hash      string
+	baseHash  string
+	changes   []models.PackageChange
+	sentFull  bool
+	dbModTime time.Time
+	scannedAt time.Time
+}
+
+// NewPackageCollector определяет менеджер пакетов хоста
+func NewPackageCollector() (*PackageCollector, error) {
+	if _, err := os.Stat(dpkgStatusPath); err == nil {
+		return &PackageCollector{manager: PackageManagerDpkg, dbPath: dpkgStatusPath}, nil
+	}
+	if _, err := exec.LookPath("rpm"); err == nil {
+		c := &PackageCollector{manager: PackageManagerRPM}
+		for _, p := range rpmDatabasePaths {
+			if _, err := os.Stat(p); err == nil {
+				c.dbPath = p
+				break
+			}
+		}
+		return c, nil
+	}
+	return nil, errors.New("no supported package manager (dpkg, rpm)")
+}
+
+func (c *PackageCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.refresh(); err != nil && c.hash == "" {
+		return err
+	}
+
+	report := &models.PackageInventory{
+		Manager:     c.manager,
+		Hash:        c.hash,
+		Count:       len(c.packages),
+		CollectedAt: c.scannedAt,
+	}
+	if !c.sentFull {
+		report.Full = true
+		report.Packages = c.packages
+		c.sentFull = true
+	} else if len(c.changes) > 0 {
+		// Последние изменения повторяются до следующих, чтобы ЦМ получил их при любом интервале опроса
+		report.BaseHash = c.baseHash
+		report.Changes = c.changes
+	}
+	metrics.Packages = report
+	return nil
+}
+
+// Snapshot возвращает полный список пакетов для GET /packages
+func (c *PackageCollector) Snapshot() (*models.PackageInventory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.refresh(); err != nil && c.hash == "" {
+		return nil, err
+	}
+	return &models.PackageInventory{
+		Manager:     c.manager,
+		Hash:        c.hash,
+		Full:        true,
+		Count:       len(c.packages),
+		Packages:    c.packages,
+		CollectedAt: c.scannedAt,
+	}, nil
+}
+
+// refresh перечитывает базу пакетов, если она изменилась или давно не читалась
+func (c *PackageCollector) refresh() error {
+	var modTime time.Time
+	if c.dbPath != "" {
+		if st, err := os.Stat(c.dbPath); err == nil {
+			modTime = st.ModTime()
+		}
+	}
+	if c.hash != "" && modTime.Equal(c.dbModTime) && time.Since(c.scannedAt) < packageRescanInterval {
+		return nil
+	}
+
+	var packages []models.Package
+	var err error
+	switch c.manager {
+	case PackageManagerDpkg:
+		packages, err = readDpkgStatus(c.dbPath)
+	case PackageManagerRPM:
+		packages, err = queryRPMPackages()
+	}
+	if err != nil {
+		return err
+	}
+	sortPackages(packages)
+	hash := packageListHash(packages)
+
+	if c.hash != "" && hash != c.hash {
+		c.changes = diffPackages(c.packages, packages)
+		c.baseHash = c.hash
+	}
+	c.packages = packages
+	c.hash = hash
+	c.dbModTime = modTime
+	c.scannedAt = time.Now()
+	return nil
+}
+
+// readDpkgStatus разбирает базу dpkg и возвращает установленные пакеты
+func readDpkgStatus(path string) ([]models.Package, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseDpkgStatus(f)
+}
+
+// parseDpkgStatus разбирает записи формата /var/lib/dpkg/status, разделенные пустыми строками.
+// Учитываются только пакеты в состоянии installed
+func parseDpkgStatus(r io.Reader) ([]models.Package, error) {
+	var packages []models.Package
+	var pkg models.Package
+	var status string
+
+	flush := func() {
+		fields := strings.Fields(status)
+		if pkg.Name != "" && pkg.Version != "" && len(fields) == 3 && fields[2] == "installed" {
+			packages = append(packages, pkg)
+		}
+		pkg = models.Package{}
+		status = ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		// Продолжения многострочных полей (Description, Conffiles) не нужны
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Architecture":
+			pkg.Arch = value
+		case "Status":
+			status = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return packages, nil
+}
+
+// queryRPMPackages получает список пакетов через rpm -qa
+func queryRPMPackages() ([]models.Package, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpmQueryTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "rpm", "-qa", "--queryformat", `%{NAME}\t%{EPOCH}\t%{VERSION}-%{RELEASE}\t%{ARCH}\n`)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("rpm -qa failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseRPMOutput(out), nil
+}
+
+// parseRPMOutput разбирает строки "имя\tepoch\tversion-release\tarch"; epoch (none) опускается
+func parseRPMOutput(out []byte) []models.Package {
+	var packages []models.Package
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 || fields[0] == "" {
+			continue
+		}
+		pkg := models.Package{Name: fields[0], Version: fields[2], Arch: fields[3]}
+		if fields[1] != "(none)" && fields[1] != "" && fields[1] != "0" {
+			pkg.Version = fields[1] + ":" + pkg.Version
+		}
+		if pkg.Arch == "(none)" {
+			pkg.Arch = ""
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+func sortPackages(packages []models.Package) {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		if packages[i].Arch != packages[j].Arch {
+			return packages[i].Arch < packages[j].Arch
+		}
+		return packages[i].Version < packages[j].Version
+	})
+}
+
+// packageListHash считает хеш отсортированного списка пакетов; ЦМ по нему сверяет свою копию
+func packageListHash(packages []models.Package) string {
+	h := sha256.New()
+	for _, p := range packages {
+		fmt.Fprintf(h, "%s\t%s\t%s\n", p.Name, p.Arch, p.Version)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// diffPackages сравнивает два списка пакетов. Пакет может быть установлен в нескольких версиях
+// (ядра в rpm), поэтому списки сравниваются по имени, архитектуре и версии, а пара
+// удаление + установка одного имени и архитектуры объединяется в смену версии
+func diffPackages(old, new []models.Package) []models.PackageChange {
+	key := func(p models.Package) string { return p.Name + "\x00" + p.Arch + "\x00" + p.Version }
+
+	oldSet := make(map[string]bool, len(old))
+	for _, p := range old {
+		oldSet[key(p)] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, p := range new {
+		newSet[key(p)] = true
+	}
+
+	type nameArch struct{ name, arch string }
+	removed := make(map[nameArch][]string)
+	added := make(map[nameArch][]string)
+	var order []nameArch
+	for _, p := range old {
+		if !newSet[key(p)] {
+			na := nameArch{p.Name, p.Arch}
+			if removed[na] == nil && added[na] == nil {
+				order = append(order, na)
+			}
+			removed[na] = append(removed[na], p.Version)
+		}
+	}
+	for _, p := range new {
+		if !oldSet[key(p)] {
+			na := nameArch{p.Name, p.Arch}
+			if removed[na] == nil && added[na] == nil {
+				order = append(order, na)
+			}
+			added[na] = append(added[na], p.Version)
+		}
+	}
+
+	var changes []models.PackageChange
+	for _, na := range order {
+		r, a := removed[na], added[na]
+		if len(r) == 1 && len(a) == 1 {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, OldVersion: r[0], NewVersion: a[0]})
+			continue
+		}
+		for _, v := range r {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, OldVersion: v})
+		}
+		for _, v := range a {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, NewVersion: v})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,166 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dpkgStatus - фрагмент /var/lib/dpkg/status с многострочными полями и пакетами не в состоянии installed
+const dpkgStatus = `Package: openssl
+Status: install ok installed
+Priority: optional
+Section: utils
+Installed-Size: 2100
+Maintainer: Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>
+Architecture: amd64
+Version: 3.0.2-0ubuntu1.12
+Depends: libc6 (>= 2.34), libssl3 (>= 3.0.2-0ubuntu1.2)
+Conffiles:
+ /etc/ssl/openssl.cnf 6b4b2b4a0ea5eba0a5c4a3f2f4a8d6d1
+Description: Secure Sockets Layer toolkit - cryptographic utility
+ This package is part of the OpenSSL project's implementation of the SSL
+ .
+ It contains the general-purpose command line binary /usr/bin/openssl,
+Homepage: https://www.openssl.org/
+
+Package: apache2
+Status: deinstall ok config-files
+Priority: optional
+Architecture: amd64
+Version: 2.4.52-1ubuntu4.6
+
+Package: libc6
+Status: install ok installed
+Architecture: i386
+Multi-Arch: same
+Version: 2.35-0ubuntu3.4
+Description: GNU C Library: Shared libraries
+
+Package: tzdata
+Status: install ok half-configured
+Architecture: all
+Version: 2024a-0ubuntu0.22.04
+
+Package: base-files
+Essential: yes
+Status: install ok installed
+Architecture: amd64
+Version: 12ubuntu4.6`
+
+func TestParseDpkgStatus(t *testing.T) {
+	got, err := parseDpkgStatus(strings.NewReader(dpkgStatus))
+	if err != nil {
+		t.Fatalf("parseDpkgStatus: %v", err)
+	}
+	want := []models.Package{
+		{Name: "openssl", Version: "3.0.2-0ubuntu1.12", Arch: "amd64"},
+		{Name: "libc6", Version: "2.35-0ubuntu3.4", Arch: "i386"},
+		{Name: "base-files", Version: "12ubuntu4.6", Arch: "amd64"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("packages:\n got %+v\nwant %+v", got, want)
+	}
+
+	if got, err := parseDpkgStatus(strings.NewReader("")); err != nil || len(got) != 0 {
+		t.Errorf("empty status = %+v, %v", got, err)
+	}
+}
+
+func TestParseRPMOutput(t *testing.T) {
+	out := "bash\t(none)\t5.1.8-6.el9\tx86_64\n" +
+		"openssl\t1\t3.0.7-24.el9\tx86_64\n" +
+		"gpg-pubkey\t(none)\tfd431d51-4ae0493b\t(none)\n" +
+		"tzdata\t0\t2024a-1.el9\tnoarch\n" +
+		"broken line\n\n"
+	want := []models.Package{
+		{Name: "bash", Version: "5.1.8-6.el9", Arch: "x86_64"},
+		{Name: "openssl", Version: "1:3.0.7-24.el9", Arch: "x86_64"},
+		{Name: "gpg-pubkey", Version: "fd431d51-4ae0493b"},
+		{Name: "tzdata", Version: "2024a-1.el9", Arch: "noarch"},
+	}
+	if got := parseRPMOutput([]byte(out)); !reflect.DeepEqual(got, want) {
+		t.Errorf("packages:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDiffPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new []models.Package
+		want     []models.PackageChange
+	}{
+		{
+			name: "no changes",
+			old:  []models.Package{{Name: "bash", Version: "5.1-6", Arch: "amd64"}},
+			new:  []models.Package{{Name: "bash", Version: "5.1-6", Arch: "amd64"}},
+		},
+		{
+			name: "install, remove and update",
+			old: []models.Package{
+				{Name: "bash", Version: "5.1-6", Arch: "amd64"},
+				{Name: "nano", Version: "6.2-1", Arch: "amd64"},
+				{Name: "openssl", Version: "3.0.2-0ubuntu1.10", Arch: "amd64"},
+			},
+			new: []models.Package{
+				{Name: "bash", Version: "5.1-6", Arch: "amd64"},
+				{Name: "curl", Version: "7.81.0-1", Arch: "amd64"},
+				{Name: "openssl", Version: "3.0.2-0ubuntu1.12", Arch: "amd64"},
+			},
+			want: []models.PackageChange{
+				{Name: "nano", Arch: "amd64", OldVersion: "6.2-1"},
+				{Name: "openssl", Arch: "amd64", OldVersion: "3.0.2-0ubuntu1.10", NewVersion: "3.0.2-0ubuntu1.12"},
+				{Name: "curl", Arch: "amd64", NewVersion: "7.81.0-1"},
+			},
+		},
+		{
+			// Ядра rpm устанавливаются в нескольких версиях: установка новой и удаление старой - не смена версии
+			name: "several installed versions",
+			old: []models.Package{
+				{Name: "kernel", Version: "5.14.0-362", Arch: "x86_64"},
+				{Name: "kernel", Version: "5.14.0-427", Arch: "x86_64"},
+			},
+			new: []models.Package{
+				{Name: "kernel", Version: "5.14.0-427", Arch: "x86_64"},
+				{Name: "kernel", Version: "5.14.0-503", Arch: "x86_64"},
+				{Name: "kernel", Version: "5.14.0-504", Arch: "x86_64"},
+			},
+			want: []models.PackageChange{
+				{Name: "kernel", Arch: "x86_64", OldVersion: "5.14.0-362"},
+				{Name: "kernel", Arch: "x86_64", NewVersion: "5.14.0-503"},
+				{Name: "kernel", Arch: "x86_64", NewVersion: "5.14.0-504"},
+			},
+		},
+		{
+			// Разные архитектуры - разные пакеты
+			name: "architecture change",
+			old:  []models.Package{{Name: "libc6", Version: "2.35-0ubuntu3.4", Arch: "i386"}},
+			new:  []models.Package{{Name: "libc6", Version: "2.35-0ubuntu3.4", Arch: "amd64"}},
+			want: []models.PackageChange{
+				{Name: "libc6", Arch: "i386", OldVersion: "2.35-0ubuntu3.4"},
+				{Name: "libc6", Arch: "amd64", NewVersion: "2.35-0ubuntu3.4"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffPackages(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageListHash(t *testing.T) {
+	a := []models.Package{{Name: "curl", Version: "7.81.0-1"}, {Name: "bash", Version: "5.1-6", Arch: "amd64"}}
+	b := []models.Package{{Name: "bash", Version: "5.1-6", Arch: "amd64"}, {Name: "curl", Version: "7.81.0-1"}}
+	sortPackages(a)
+	if packageListHash(a) != packageListHash(b) {
+		t.Errorf("hash depends on the package order")
+	}
+	b[1].Version = "7.81.0-2"
+	if packageListHash(a) == packageListHash(b) {
+		t.Errorf("hash does not depend on the version")
+	}
+}
@@ -0,0 +1,229 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultProbeTimeout - таймаут проверки, если он не задан
+	defaultProbeTimeout = 5 * time.Second
+	// probeBodyLimit - сколько байт тела ответа читается для проверки body_regex
+	probeBodyLimit = 1 << 20
+)
+
+// Типы проверок
+const (
+	ProbeHTTP = "http"
+	ProbeTCP  = "tcp"
+	ProbeUnix = "unix"
+)
+
+// ProbeCollector проверяет доступность локальных сервисов: HTTP-запросом с проверкой
+// кода и тела ответа, TCP-подключением или подключением к Unix-сокету.
+// Все проверки выполняются параллельно в каждом цикле сбора.
+type ProbeCollector struct {
+	mu     sync.Mutex
+	probes []compiledProbe
+}
+
+// compiledProbe - проверка с разобранным регулярным выражением
+type compiledProbe struct {
+	models.Probe
+	bodyRe *regexp.Regexp
+}
+
+func NewProbeCollector(probes []models.Probe) (*ProbeCollector, error) {
+	c := &ProbeCollector{}
+	if err := c.SetProbes(probes); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetProbes проверяет и устанавливает список проверок; при ошибке текущий список не меняется
+func (c *ProbeCollector) SetProbes(probes []models.Probe) error {
+	compiled, err := compileProbes(probes)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.probes = compiled
+	c.mu.Unlock()
+	return nil
+}
+
+func compileProbes(probes []models.Probe) ([]compiledProbe, error) {
+	compiled := make([]compiledProbe, 0, len(probes))
+	names := make(map[string]bool)
+	for _, p := range probes {
+		if p.Name == "" || p.Target == "" {
+			return nil, errors.New("probe requires name and target")
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("duplicate probe %q", p.Name)
+		}
+		names[p.Name] = true
+
+		switch p.Type {
+		case ProbeHTTP:
+			u, err := url.Parse(p.Target)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("probe %s: invalid URL %q", p.Name, p.Target)
+			}
+		case ProbeTCP:
+			if _, _, err := net.SplitHostPort(p.Target); err != nil {
+				return nil, fmt.Errorf("probe %s: invalid address %q: %w", p.Name, p.Target, err)
+			}
+		case ProbeUnix:
+			if !filepath.IsAbs(p.Target) {
+				return nil, fmt.Errorf("probe %s: socket path must be absolute", p.Name)
+			}
+		default:
+			return nil, fmt.Errorf("probe %s: unknown type %q", p.Name, p.Type)
+		}
+		if p.Type != ProbeHTTP && (p.ExpectedStatus != 0 || p.BodyRegex != "") {
+			return nil, fmt.Errorf("probe %s: expected_status and body_regex apply only to http probes", p.Name)
+		}
+
+		cp := compiledProbe{Probe: p}
+		if p.BodyRegex != "" {
+			re, err := regexp.Compile(p.BodyRegex)
+			if err != nil {
+				return nil, fmt.Errorf("probe %s: invalid body_regex: %w", p.Name, err)
+			}
+			cp.bodyRe = re
+		}
+		if cp.Timeout <= 0 {
+			cp.Timeout = defaultProbeTimeout
+		}
+		compiled = append(compiled, cp)
+	}
+	return compiled, nil
+}
+
+func (c *ProbeCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	probes := c.probes
+	c.mu.Unlock()
+	if len(probes) == 0 {
+		return nil
+	}
+
+	results := make([]models.ProbeResult, len(probes))
+	var wg sync.WaitGroup
+	for i := range probes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runProbe(probes[i])
+		}(i)
+	}
+	wg.Wait()
+
+	metrics.Probes = append(metrics.Probes, results...)
+	return nil
+}
+
+// runProbe выполняет одну проверку
+func runProbe(p compiledProbe) models.ProbeResult {
+	result := models.ProbeResult{
+		Name:   p.Name,
+		Type:   p.Type,
+		Target: p.Target,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch p.Type {
+	case ProbeHTTP:
+		err = probeHTTP(ctx, p, &result)
+	case ProbeTCP, ProbeUnix:
+		network := "tcp"
+		if p.Type == ProbeUnix {
+			network = "unix"
+		}
+		var conn net.Conn
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, network, p.Target)
+		if err == nil {
+			conn.Close()
+		}
+	}
+	result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Up = true
+	return result
+}
+
+// probeHTTP выполняет HTTP-запрос без перехода по редиректам: код ответа относится к самому адресу
+func probeHTTP(ctx context.Context, p compiledProbe, result *models.ProbeResult) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "monitoring-agent-probe")
+
+	transport := &http.Transport{
+		Proxy:             nil,
+		DisableKeepAlives: true,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: p.TLSSkipVerify},
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		expiry := resp.TLS.PeerCertificates[0].NotAfter
+		result.CertExpiry = &expiry
+		result.CertDaysLeft = time.Until(expiry).Hours() / 24
+	}
+
+	if p.ExpectedStatus != 0 {
+		if resp.StatusCode != p.ExpectedStatus {
+			return fmt.Errorf("unexpected status %d, want %d", resp.StatusCode, p.ExpectedStatus)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if p.bodyRe != nil {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, probeBodyLimit))
+		if err != nil {
+			return fmt.Errorf("read body: %w", err)
+		}
+		if !p.bodyRe.Match(body) {
+			return errors.New("body does not match body_regex")
+		}
+	}
+	return nil
+}
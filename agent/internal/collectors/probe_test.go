@@ -0,0 +1,263 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newProbeTestHandler возвращает обработчик с типичными ответами проверяемых сервисов
+func newProbeTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "db": "up"}`))
+	})
+	mux.HandleFunc("/degraded", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "degraded", "db": "down"}`))
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/error", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	return mux
+}
+
+func mustCompileProbe(t *testing.T, p models.Probe) compiledProbe {
+	t.Helper()
+	compiled, err := compileProbes([]models.Probe{p})
+	if err != nil {
+		t.Fatalf("compileProbes: %v", err)
+	}
+	return compiled[0]
+}
+
+func TestProbeHTTP(t *testing.T) {
+	server := httptest.NewServer(newProbeTestHandler())
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		probe      models.Probe
+		wantUp     bool
+		wantStatus int
+		wantErr    string
+	}{
+		{name: "any 2xx", probe: models.Probe{Target: "/health"}, wantUp: true, wantStatus: 200},
+		{name: "5xx is down", probe: models.Probe{Target: "/error"}, wantStatus: 503, wantErr: "unexpected status 503"},
+		{name: "expected status matches", probe: models.Probe{Target: "/error", ExpectedStatus: 503}, wantUp: true, wantStatus: 503},
+		{name: "expected status mismatch", probe: models.Probe{Target: "/health", ExpectedStatus: 204}, wantStatus: 200, wantErr: "unexpected status 200, want 204"},
+		{name: "body regex match", probe: models.Probe{Target: "/health", BodyRegex: `"db":\s*"up"`}, wantUp: true, wantStatus: 200},
+		{name: "body regex mismatch", probe: models.Probe{Target: "/degraded", BodyRegex: `"db":\s*"up"`}, wantStatus: 200, wantErr: "body does not match"},
+		// Редирект не выполняется: код ответа относится к самому адресу, а не к /error
+		{name: "redirect not followed", probe: models.Probe{Target: "/redirect"}, wantUp: true, wantStatus: 302},
+		{name: "redirect with expected status", probe: models.Probe{Target: "/redirect", ExpectedStatus: 200}, wantStatus: 302, wantErr: "unexpected status 302"},
+		{name: "timeout", probe: models.Probe{Target: "/slow", Timeout: 50 * time.Millisecond}, wantErr: "deadline exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.probe
+			p.Name, p.Type, p.Target = "web", ProbeHTTP, server.URL+p.Target
+			result := runProbe(mustCompileProbe(t, p))
+
+			if result.Name != "web" || result.Type != ProbeHTTP || result.Target != p.Target {
+				t.Errorf("result identity = %q/%q/%q", result.Name, result.Type, result.Target)
+			}
+			if result.Up != tt.wantUp || result.StatusCode != tt.wantStatus {
+				t.Errorf("up/status = %v/%d, want %v/%d (error %q)", result.Up, result.StatusCode, tt.wantUp, tt.wantStatus, result.Error)
+			}
+			if tt.wantErr == "" && result.Error != "" {
+				t.Errorf("unexpected error %q", result.Error)
+			}
+			if tt.wantErr != "" && !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("error = %q, want %q", result.Error, tt.wantErr)
+			}
+			if result.LatencyMs <= 0 {
+				t.Errorf("latency = %v", result.LatencyMs)
+			}
+			if result.CertExpiry != nil {
+				t.Errorf("plain HTTP reported certificate expiry")
+			}
+		})
+	}
+}
+
+func TestProbeHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(newProbeTestHandler())
+	defer server.Close()
+	notAfter := server.Certificate().NotAfter
+
+	// Самоподписанный сертификат тестового сервера принимается только с tls_skip_verify
+	result := runProbe(mustCompileProbe(t, models.Probe{Name: "tls", Type: ProbeHTTP, Target: server.URL + "/health"}))
+	if result.Up || !strings.Contains(result.Error, "certificate") {
+		t.Errorf("unverified certificate: up %v error %q", result.Up, result.Error)
+	}
+
+	result = runProbe(mustCompileProbe(t, models.Probe{Name: "tls", Type: ProbeHTTP, Target: server.URL + "/health", TLSSkipVerify: true}))
+	if !result.Up || result.StatusCode != 200 {
+		t.Fatalf("up/status = %v/%d (error %q)", result.Up, result.StatusCode, result.Error)
+	}
+	if result.CertExpiry == nil || !result.CertExpiry.Equal(notAfter) {
+		t.Errorf("cert expiry = %v, want %v", result.CertExpiry, notAfter)
+	}
+	wantDays := time.Until(notAfter).Hours() / 24
+	if math.Abs(result.CertDaysLeft-wantDays) > 0.01 {
+		t.Errorf("cert days left = %v, want %v", result.CertDaysLeft, wantDays)
+	}
+
+	// Сведения о сертификате заполняются и при неожиданном коде ответа
+	result = runProbe(mustCompileProbe(t, models.Probe{Name: "tls", Type: ProbeHTTP, Target: server.URL + "/error", TLSSkipVerify: true}))
+	if result.Up || result.CertExpiry == nil {
+		t.Errorf("up %v cert expiry %v", result.Up, result.CertExpiry)
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := listener.Addr().String()
+	defer listener.Close()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := closedListener.Addr().String()
+	closedListener.Close()
+
+	result := runProbe(mustCompileProbe(t, models.Probe{Name: "db", Type: ProbeTCP, Target: open}))
+	if !result.Up || result.Error != "" {
+		t.Errorf("open port: up %v error %q", result.Up, result.Error)
+	}
+
+	result = runProbe(mustCompileProbe(t, models.Probe{Name: "db", Type: ProbeTCP, Target: closed}))
+	if result.Up || !strings.Contains(result.Error, "refused") {
+		t.Errorf("closed port: up %v error %q", result.Up, result.Error)
+	}
+}
+
+func TestProbeUnix(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	result := runProbe(mustCompileProbe(t, models.Probe{Name: "fpm", Type: ProbeUnix, Target: socket}))
+	if !result.Up || result.Error != "" {
+		t.Errorf("listening socket: up %v error %q", result.Up, result.Error)
+	}
+
+	result = runProbe(mustCompileProbe(t, models.Probe{Name: "fpm", Type: ProbeUnix, Target: socket + ".missing"}))
+	if result.Up || result.Error == "" {
+		t.Errorf("missing socket: up %v error %q", result.Up, result.Error)
+	}
+}
+
+func TestProbeCollectorCollect(t *testing.T) {
+	server := httptest.NewServer(newProbeTestHandler())
+	defer server.Close()
+
+	c, err := NewProbeCollector([]models.Probe{
+		{Name: "health", Type: ProbeHTTP, Target: server.URL + "/health"},
+		{Name: "error", Type: ProbeHTTP, Target: server.URL + "/error"},
+	})
+	if err != nil {
+		t.Fatalf("NewProbeCollector: %v", err)
+	}
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// Результаты идут в порядке проверок
+	if len(metrics.Probes) != 2 || metrics.Probes[0].Name != "health" || !metrics.Probes[0].Up || metrics.Probes[1].Up {
+		t.Errorf("probes = %+v", metrics.Probes)
+	}
+
+	// Ошибочный список не заменяет текущий
+	if err := c.SetProbes([]models.Probe{{Name: "bad", Type: "icmp", Target: "host"}}); err == nil {
+		t.Fatalf("SetProbes accepted invalid probe")
+	}
+	metrics = models.AgentMetrics{}
+	if err := c.Collect(&metrics); err != nil || len(metrics.Probes) != 2 {
+		t.Errorf("probes after rejected update = %d (%v)", len(metrics.Probes), err)
+	}
+}
+
+func TestCompileProbes(t *testing.T) {
+	tests := []struct {
+		name    string
+		probes  []models.Probe
+		wantErr string
+	}{
+		{name: "empty list"},
+		{
+			name: "valid probes",
+			probes: []models.Probe{
+				{Name: "web", Type: ProbeHTTP, Target: "https://localhost:8443/health", ExpectedStatus: 200, BodyRegex: "ok"},
+				{Name: "db", Type: ProbeTCP, Target: "127.0.0.1:5432"},
+				{Name: "ipv6", Type: ProbeTCP, Target: "[::1]:6379"},
+				{Name: "fpm", Type: ProbeUnix, Target: "/run/php/php-fpm.sock"},
+			},
+		},
+		{name: "missing name", probes: []models.Probe{{Type: ProbeTCP, Target: "127.0.0.1:80"}}, wantErr: "requires name and target"},
+		{name: "missing target", probes: []models.Probe{{Name: "db", Type: ProbeTCP}}, wantErr: "requires name and target"},
+		{
+			name:    "duplicate name",
+			probes:  []models.Probe{{Name: "db", Type: ProbeTCP, Target: "127.0.0.1:5432"}, {Name: "db", Type: ProbeTCP, Target: "127.0.0.1:5433"}},
+			wantErr: `duplicate probe "db"`,
+		},
+		{name: "unknown type", probes: []models.Probe{{Name: "ping", Type: "icmp", Target: "127.0.0.1"}}, wantErr: `unknown type "icmp"`},
+		{name: "http without scheme", probes: []models.Probe{{Name: "web", Type: ProbeHTTP, Target: "localhost:8080/health"}}, wantErr: "invalid URL"},
+		{name: "http with unsupported scheme", probes: []models.Probe{{Name: "web", Type: ProbeHTTP, Target: "ftp://localhost/"}}, wantErr: "invalid URL"},
+		{name: "http without host", probes: []models.Probe{{Name: "web", Type: ProbeHTTP, Target: "http:///health"}}, wantErr: "invalid URL"},
+		{name: "tcp without port", probes: []models.Probe{{Name: "db", Type: ProbeTCP, Target: "127.0.0.1"}}, wantErr: "invalid address"},
+		{name: "relative socket path", probes: []models.Probe{{Name: "fpm", Type: ProbeUnix, Target: "run/php.sock"}}, wantErr: "must be absolute"},
+		{name: "status on tcp probe", probes: []models.Probe{{Name: "db", Type: ProbeTCP, Target: "127.0.0.1:5432", ExpectedStatus: 200}}, wantErr: "apply only to http"},
+		{name: "body regex on unix probe", probes: []models.Probe{{Name: "fpm", Type: ProbeUnix, Target: "/run/php.sock", BodyRegex: "ok"}}, wantErr: "apply only to http"},
+		{name: "invalid body regex", probes: []models.Probe{{Name: "web", Type: ProbeHTTP, Target: "http://localhost/", BodyRegex: "("}}, wantErr: "invalid body_regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := compileProbes(tt.probes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(compiled) != len(tt.probes) {
+				t.Fatalf("compiled %d probes, want %d", len(compiled), len(tt.probes))
+			}
+			for _, p := range compiled {
+				if p.Timeout != defaultProbeTimeout {
+					t.Errorf("probe %s timeout = %v, want default", p.Name, p.Timeout)
+				}
+				if (p.BodyRegex != "") != (p.bodyRe != nil) {
+					t.Errorf("probe %s body regex not compiled", p.Name)
+				}
+			}
+		})
+	}
+}
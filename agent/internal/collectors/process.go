@@ -2,68 +2,433 @@ package collectors
 
 import (
 	"agent/internal/models"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/shirou/gopsutil/process"
 )
 
-// ProcessCollector собирает информацию о процессах
+// ProcessCollector собирает информацию о процессах.
+// Между циклами сбора хранит дескрипторы процессов по PID, чтобы считать CPU и I/O за интервал,
+// а не за все время жизни процесса.
 type ProcessCollector struct {
-	processes []string // список отслеживаемых процессов
+	matchers []processMatcher // правила отбора отслеживаемых процессов
+	mu       sync.Mutex
+	cache    map[int32]*cachedProcess
+	// lifecycle отслеживает запуски и завершения (только при заданных правилах отбора)
+	lifecycle *processLifecycle
+}
+
+// cachedProcess хранит дескриптор процесса и счетчики с предыдущего сбора
+type cachedProcess struct {
+	proc       *process.Process
+	createTime int64
+	cpuSeconds float64
+	readBytes  uint64
+	writeBytes uint64
+	hasIO      bool
+	at         time.Time
 }
 
 func NewProcessCollector(processes []string) *ProcessCollector {
 	return &ProcessCollector{
-		processes: processes,
+		matchers:  matchersFromNames(processes),
+		cache:     make(map[int32]*cachedProcess),
+		lifecycle: newProcessLifecycle(),
+	}
+}
+
+// processConfigSchema - схема настроек коллектора process
+var processConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"matchers": {
+			"type": "array",
+			"description": "Правила отбора отслеживаемых процессов",
+			"items": {
+				"type": "object",
+				"properties": {
+					"alias": {"type": "string"},
+					"name": {"type": "string"},
+					"name_regex": {"type": "string", "format": "regex"},
+					"cmdline": {"type": "string"},
+					"cmdline_regex": {"type": "string", "format": "regex"},
+					"user": {"type": "string"},
+					"exe": {"type": "string"},
+					"cgroup": {"type": "string"},
+					"systemd_unit": {"type": "string"}
+				}
+			}
+		}
+	}
+}`)
+
+// ProcessConfig - настройки коллектора process
+type ProcessConfig struct {
+	Matchers []models.ProcessMatcher `json:"matchers"`
+}
+
+func (c *ProcessCollector) ConfigSchema() *Schema {
+	return processConfigSchema
+}
+
+func (c *ProcessCollector) Config() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := ProcessConfig{Matchers: make([]models.ProcessMatcher, 0, len(c.matchers))}
+	for _, m := range c.matchers {
+		cfg.Matchers = append(cfg.Matchers, m.spec)
 	}
+	return cfg
 }
 
-func (c *ProcessCollector) ChangeConfig(collType CollectorType, newconfig []string) {
-	if collType == Process {
-		c.processes = newconfig
+func (c *ProcessCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(ProcessConfig)
+	if err := decodeConfig(processConfigSchema, raw, &cfg); err != nil {
+		return err
 	}
+	return c.SetMatchers(cfg.Matchers)
+}
+
+// SetNames заменяет правила отбора списком точных имен процессов
+func (c *ProcessCollector) SetNames(names []string) {
+	c.mu.Lock()
+	c.matchers = matchersFromNames(names)
+	c.mu.Unlock()
+}
+
+// SetMatchers заменяет правила отбора процессов; при ошибке в правилах конфигурация не меняется
+func (c *ProcessCollector) SetMatchers(specs []models.ProcessMatcher) error {
+	matchers, err := compileProcessMatchers(specs)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.matchers = matchers
+	c.mu.Unlock()
+	return nil
 }
 
 func (c *ProcessCollector) Collect(metrics *models.AgentMetrics) error {
-	processes, err := process.Processes()
+	pids, err := process.Pids()
 	if err != nil {
 		return err
 	}
 
+	c.mu.Lock()
+	matchers := c.matchers
+	c.mu.Unlock()
+
+	now := time.Now()
 	var processInfos []models.ProcessInfo
+	seen := make(map[int32]bool)
 
-	for _, p := range processes {
-		name, err := p.Name()
+	for _, pid := range pids {
+		// Если заданы правила отбора, процесс относится к первому подходящему. Проверка идет
+		// до поиска в кэше: остальные процессы хоста не кэшируются и их время запуска не читается
+		name, alias, ok := matchProcess(pid, matchers)
+		if !ok {
+			continue
+		}
+
+		entry, err := c.lookup(pid)
 		if err != nil {
 			continue
 		}
 
-		// Если указан список процессов и текущего в нем нет - пропускаем
-		if len(c.processes) > 0 {
-			found := false
-			for _, targetName := range c.processes {
-				if targetName == name {
-					found = true
-					break
-				}
+		processInfo := c.describeProcess(entry, name, now)
+		processInfo.Alias = alias
+		seen[pid] = true
+		processInfos = append(processInfos, processInfo)
+	}
+
+	// Забываем завершившиеся и неотслеживаемые процессы
+	for pid := range c.cache {
+		if !seen[pid] {
+			delete(c.cache, pid)
+		}
+	}
+
+	if len(matchers) > 0 {
+		current := aliasPIDs(matchers)
+		for _, p := range processInfos {
+			current[p.Alias][p.PID] = true
+		}
+		metrics.Events = append(metrics.Events, c.lifecycle.update(current, now)...)
+	} else {
+		c.lifecycle.reset()
+	}
+
+	metrics.Processes = processInfos
+	metrics.ProcessGroups = c.groupProcesses(matchers, processInfos, now)
+	return nil
+}
+
+// Watch проверяет PID отслеживаемых процессов чаще, чем идет сбор метрик,
+// чтобы завершения и перезапуски фиксировались с точным временем
+func (c *ProcessCollector) Watch(ctx context.Context, emit func(events ...models.Event)) {
+	ticker := time.NewTicker(lifecycleScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if events := c.scanLifecycle(time.Now()); len(events) > 0 {
+				emit(events...)
 			}
-			if !found {
-				continue
+		}
+	}
+}
+
+// scanLifecycle находит PID отслеживаемых процессов без сбора метрик
+func (c *ProcessCollector) scanLifecycle(now time.Time) []models.Event {
+	c.mu.Lock()
+	matchers := c.matchers
+	c.mu.Unlock()
+	if len(matchers) == 0 {
+		return nil
+	}
+
+	pids, err := process.Pids()
+	if err != nil {
+		return nil
+	}
+
+	current := aliasPIDs(matchers)
+	for _, pid := range pids {
+		if _, alias, ok := matchProcess(pid, matchers); ok {
+			current[alias][pid] = true
+		}
+	}
+
+	return c.lifecycle.update(current, now)
+}
+
+// matchProcess читает имя процесса и находит первое подходящее правило. Без правил подходит любой процесс.
+// Остальные поля (командная строка, пользователь, cgroup) читаются, только если их проверяет правило
+func matchProcess(pid int32, matchers []processMatcher) (name, alias string, ok bool) {
+	// Дескриптор без NewProcess: проверка существования PID не нужна, ее заменяет чтение имени
+	candidate := &processCandidate{proc: &process.Process{Pid: pid}}
+	name, err := candidate.proc.Name()
+	if err != nil {
+		return "", "", false
+	}
+	if len(matchers) == 0 {
+		return name, "", true
+	}
+
+	candidate.name = name
+	for i := range matchers {
+		if matchers[i].matches(candidate) {
+			return name, matchers[i].spec.Alias, true
+		}
+	}
+	return name, "", false
+}
+
+// aliasPIDs создает пустые множества PID для всех псевдонимов
+func aliasPIDs(matchers []processMatcher) map[string]map[int32]bool {
+	current := make(map[string]map[int32]bool, len(matchers))
+	for i := range matchers {
+		current[matchers[i].spec.Alias] = make(map[int32]bool)
+	}
+	return current
+}
+
+// lookup возвращает закэшированный дескриптор процесса.
+// Если PID переиспользован другим процессом (изменилось время запуска), запись создается заново.
+func (c *ProcessCollector) lookup(pid int32) (*cachedProcess, error) {
+	// Дескриптор gopsutil кэширует время запуска, поэтому сверяемся со свежим
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, ok := c.cache[pid]; ok && entry.createTime == createTime {
+		return entry, nil
+	}
+
+	entry := &cachedProcess{proc: p, createTime: createTime}
+	c.cache[pid] = entry
+	return entry, nil
+}
+
+// describeProcess собирает подробную информацию об одном процессе.
+// Ошибки чтения отдельных полей не критичны: поле остается нулевым.
+func (c *ProcessCollector) describeProcess(entry *cachedProcess, name string, now time.Time) models.ProcessInfo {
+	p := entry.proc
+	info := models.ProcessInfo{
+		PID:  p.Pid,
+		Name: name,
+	}
+
+	if memPercent, err := p.MemoryPercent(); err == nil {
+		info.MemPercent = float64(memPercent)
+	}
+	if memInfo, err := p.MemoryInfo(); err == nil {
+		info.RSSBytes = memInfo.RSS
+		info.VMSBytes = memInfo.VMS
+		info.MemoryMB = float64(memInfo.RSS) / 1024 / 1024
+	}
+	info.PPID, _ = p.Ppid()
+	info.Username, _ = p.Username()
+	if status, err := p.Status(); err == nil {
+		info.State = processStateName(status)
+	}
+	info.NumThreads, _ = p.NumThreads()
+	info.NumFDs, _ = p.NumFDs()
+	if limits, err := p.Rlimit(); err == nil {
+		for _, l := range limits {
+			// Отрицательное значение соответствует RLIM_INFINITY
+			if l.Resource == process.RLIMIT_NOFILE && l.Soft > 0 {
+				info.FDLimit = uint64(l.Soft)
 			}
 		}
+	}
 
-		pid := p.Pid
-		cpuPercent, _ := p.CPUPercent()
-		memPercent, _ := p.MemoryPercent()
+	info.StartTime = time.UnixMilli(entry.createTime)
+	info.UptimeSeconds = now.Sub(info.StartTime).Seconds()
 
-		processInfo := models.ProcessInfo{
-			PID:        pid,
-			Name:       name,
-			CPUPercent: cpuPercent,
-			MemPercent: float64(memPercent),
+	// На первом цикле для процесса предыдущих значений нет, скорости остаются нулевыми
+	first := entry.at.IsZero()
+	elapsed := now.Sub(entry.at).Seconds()
+
+	if times, err := p.Times(); err == nil {
+		cpuSeconds := times.User + times.System
+		if !first && elapsed > 0 && cpuSeconds >= entry.cpuSeconds {
+			info.CPUPercent = (cpuSeconds - entry.cpuSeconds) / elapsed * 100
 		}
+		entry.cpuSeconds = cpuSeconds
+	}
 
-		processInfos = append(processInfos, processInfo)
+	if io, err := p.IOCounters(); err == nil {
+		if !first && entry.hasIO && elapsed > 0 {
+			info.ReadBytesPerSec = counterRate(entry.readBytes, io.ReadBytes, elapsed)
+			info.WriteBytesPerSec = counterRate(entry.writeBytes, io.WriteBytes, elapsed)
+		}
+		entry.readBytes = io.ReadBytes
+		entry.writeBytes = io.WriteBytes
+		entry.hasIO = true
 	}
 
-	metrics.Processes = processInfos
-	return nil
+	entry.at = now
+	return info
+}
+
+// groupProcesses суммирует показатели процессов с одинаковым псевдонимом (или именем).
+// При заданных правилах отбора группа есть у каждого псевдонима, даже если процесс не запущен,
+// чтобы правила вида process.nginx.count < 1 могли сработать.
+func (c *ProcessCollector) groupProcesses(matchers []processMatcher, processes []models.ProcessInfo, now time.Time) []models.ProcessGroupInfo {
+	var groups []models.ProcessGroupInfo
+	index := make(map[string]int)
+
+	for i := range matchers {
+		alias := matchers[i].spec.Alias
+		if _, ok := index[alias]; !ok {
+			groups = append(groups, models.ProcessGroupInfo{Name: alias})
+			index[alias] = len(groups) - 1
+		}
+	}
+
+	for _, p := range processes {
+		key := processKey(p)
+		i, ok := index[key]
+		if !ok {
+			groups = append(groups, models.ProcessGroupInfo{Name: key})
+			i = len(groups) - 1
+			index[key] = i
+		}
+
+		g := &groups[i]
+		g.Count++
+		g.CPUPercent += p.CPUPercent
+		g.MemPercent += p.MemPercent
+		g.MemoryMB += p.MemoryMB
+		g.RSSBytes += p.RSSBytes
+		g.VMSBytes += p.VMSBytes
+		g.NumThreads += p.NumThreads
+		g.NumFDs += p.NumFDs
+		g.ReadBytesPerSec += p.ReadBytesPerSec
+		g.WriteBytesPerSec += p.WriteBytesPerSec
+	}
+
+	for i := range groups {
+		groups[i].Restarts10m = c.lifecycle.restartCount(groups[i].Name, now)
+	}
+
+	return groups
+}
+
+// processKey возвращает имя, под которым процесс виден в алертах
+func processKey(p models.ProcessInfo) string {
+	if p.Alias != "" {
+		return p.Alias
+	}
+	return p.Name
+}
+
+// counterRate вычисляет скорость роста счетчика; сброс счетчика дает 0
+func counterRate(prev, cur uint64, elapsedSeconds float64) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / elapsedSeconds
+}
+
+// pidDiff возвращает PID из a, отсутствующие в b, по возрастанию
+func pidDiff(a, b map[int32]bool) []int32 {
+	var diff []int32
+	for pid := range a {
+		if !b[pid] {
+			diff = append(diff, pid)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i] < diff[j] })
+	return diff
+}
+
+// joinPIDs форматирует список PID через запятую
+func joinPIDs(pids []int32) string {
+	parts := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		parts = append(parts, strconv.Itoa(int(pid)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// processStateName переводит однобуквенное состояние из /proc/<pid>/status в читаемое
+func processStateName(status string) string {
+	switch status {
+	case "R":
+		return "running"
+	case "S":
+		return "sleeping"
+	case "D":
+		return "disk-sleep"
+	case "I":
+		return "idle"
+	case "T", "t":
+		return "stopped"
+	case "Z":
+		return "zombie"
+	case "X", "x":
+		return "dead"
+	case "W":
+		return "waiting"
+	case "L":
+		return "locked"
+	default:
+		return "unknown"
+	}
 }
@@ -0,0 +1,143 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// restartWindow - окно, за которое считается restarts_10m
+	restartWindow = 10 * time.Minute
+	// crashLoopThreshold - число перезапусков в окне, после которого процесс считается зацикленным
+	crashLoopThreshold = 3
+	// lifecycleScanInterval - период проверки PID между циклами сбора
+	lifecycleScanInterval = 5 * time.Second
+)
+
+// processLifecycle отслеживает появление, завершение и перезапуски процессов по псевдонимам
+type processLifecycle struct {
+	mu           sync.Mutex
+	lastPIDs     map[string]map[int32]bool // PID псевдонима при предыдущей проверке
+	exited       map[string]bool           // процесс завершился и еще не запущен заново
+	crashLooping map[string]bool
+	restarts     map[string]*eventWindow
+}
+
+func newProcessLifecycle() *processLifecycle {
+	return &processLifecycle{
+		lastPIDs:     make(map[string]map[int32]bool),
+		exited:       make(map[string]bool),
+		crashLooping: make(map[string]bool),
+		restarts:     make(map[string]*eventWindow),
+	}
+}
+
+// reset забывает состояние, например при отключении правил отбора
+func (l *processLifecycle) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastPIDs = make(map[string]map[int32]bool)
+	l.exited = make(map[string]bool)
+	l.crashLooping = make(map[string]bool)
+	l.restarts = make(map[string]*eventWindow)
+}
+
+// update сравнивает текущие PID каждого псевдонима с предыдущей проверкой и возвращает события.
+// Псевдонимы, которых не было в прошлой проверке (новая конфигурация), только запоминаются.
+func (l *processLifecycle) update(current map[string]map[int32]bool, now time.Time) []models.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var events []models.Event
+	for alias, pids := range current {
+		prev, known := l.lastPIDs[alias]
+		if !known {
+			continue
+		}
+
+		gone := pidDiff(prev, pids)
+		appeared := pidDiff(pids, prev)
+
+		switch {
+		case len(prev) == 0 && len(pids) > 0:
+			events = append(events, lifecycleEvent(now, "started", alias,
+				fmt.Sprintf("process %s started: pid %s", alias, joinPIDs(appeared)),
+				map[string]string{"pids": joinPIDs(appeared)}))
+			// Запуск после завершения - тоже перезапуск, даже если между ними прошло несколько проверок
+			if l.exited[alias] {
+				events = append(events, l.countRestart(alias, now)...)
+			}
+			l.exited[alias] = false
+
+		case len(prev) > 0 && len(pids) == 0:
+			events = append(events, lifecycleEvent(now, "exited", alias,
+				fmt.Sprintf("process %s exited: pid %s", alias, joinPIDs(gone)),
+				map[string]string{"pids": joinPIDs(gone)}))
+			l.exited[alias] = true
+
+		case len(gone) > 0 && len(appeared) > 0:
+			events = append(events, lifecycleEvent(now, "restarted", alias,
+				fmt.Sprintf("process %s restarted: pid %s -> %s", alias, joinPIDs(gone), joinPIDs(appeared)),
+				map[string]string{"old_pids": joinPIDs(gone), "new_pids": joinPIDs(appeared)}))
+			events = append(events, l.countRestart(alias, now)...)
+		}
+	}
+
+	// Снимаем признак зацикливания, когда перезапуски вышли из окна
+	for alias := range l.crashLooping {
+		if l.restartCountLocked(alias, now) < crashLoopThreshold {
+			delete(l.crashLooping, alias)
+		}
+	}
+
+	l.lastPIDs = current
+	return events
+}
+
+// countRestart учитывает перезапуск и сообщает о зацикливании при превышении порога
+func (l *processLifecycle) countRestart(alias string, now time.Time) []models.Event {
+	w, ok := l.restarts[alias]
+	if !ok {
+		w = newEventWindow(restartWindow)
+		l.restarts[alias] = w
+	}
+	w.add(now)
+
+	count := w.count(now)
+	if count < crashLoopThreshold || l.crashLooping[alias] {
+		return nil
+	}
+	l.crashLooping[alias] = true
+	return []models.Event{lifecycleEvent(now, "crash_looping", alias,
+		fmt.Sprintf("process %s restarted %d times in %s", alias, count, restartWindow),
+		map[string]string{"restarts": strconv.Itoa(count), "window": restartWindow.String()})}
+}
+
+// restartCount возвращает число перезапусков псевдонима за окно
+func (l *processLifecycle) restartCount(alias string, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.restartCountLocked(alias, now)
+}
+
+func (l *processLifecycle) restartCountLocked(alias string, now time.Time) int {
+	w, ok := l.restarts[alias]
+	if !ok {
+		return 0
+	}
+	return w.count(now)
+}
+
+func lifecycleEvent(now time.Time, eventType, alias, message string, attrs map[string]string) models.Event {
+	return models.Event{
+		Timestamp:  now,
+		Source:     "process",
+		Type:       eventType,
+		Object:     alias,
+		Message:    message,
+		Attributes: attrs,
+	}
+}
@@ -0,0 +1,201 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// processMatcher - скомпилированное правило отбора процессов
+type processMatcher struct {
+	spec         models.ProcessMatcher
+	nameRegex    *regexp.Regexp
+	cmdlineRegex *regexp.Regexp
+}
+
+// compileProcessMatchers проверяет и компилирует правила отбора
+func compileProcessMatchers(specs []models.ProcessMatcher) ([]processMatcher, error) {
+	matchers := make([]processMatcher, 0, len(specs))
+	for _, spec := range specs {
+		m := processMatcher{spec: spec}
+
+		if m.spec.Alias == "" {
+			m.spec.Alias = spec.Name
+		}
+		if m.spec.Alias == "" {
+			return nil, fmt.Errorf("process matcher requires alias or name")
+		}
+
+		if spec.NameRegex != "" {
+			re, err := regexp.Compile(spec.NameRegex)
+			if err != nil {
+				return nil, fmt.Errorf("matcher %s: invalid name_regex: %w", m.spec.Alias, err)
+			}
+			m.nameRegex = re
+		}
+		if spec.CmdlineRegex != "" {
+			re, err := regexp.Compile(spec.CmdlineRegex)
+			if err != nil {
+				return nil, fmt.Errorf("matcher %s: invalid cmdline_regex: %w", m.spec.Alias, err)
+			}
+			m.cmdlineRegex = re
+		}
+
+		// Правило без условий отбирает процессы с именем, равным псевдониму
+		if spec.Name == "" && spec.NameRegex == "" && spec.Cmdline == "" && spec.CmdlineRegex == "" &&
+			spec.User == "" && spec.Exe == "" && spec.Cgroup == "" && spec.SystemdUnit == "" {
+			m.spec.Name = m.spec.Alias
+		}
+
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// matchersFromNames строит правила точного совпадения имени для списка процессов
+func matchersFromNames(names []string) []processMatcher {
+	specs := make([]models.ProcessMatcher, 0, len(names))
+	for _, name := range names {
+		specs = append(specs, models.ProcessMatcher{Alias: name, Name: name})
+	}
+	// Правила без регулярных выражений скомпилируются без ошибок
+	matchers, _ := compileProcessMatchers(specs)
+	return matchers
+}
+
+// ProcessMatchersWithNames дополняет правила отбора правилами точного совпадения для имен из names.
+// Имена, совпадающие с псевдонимом одного из правил, пропускаются: ЦМ передает оба списка для одних и тех же процессов
+func ProcessMatchersWithNames(names []string, specs []models.ProcessMatcher) []models.ProcessMatcher {
+	aliases := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if spec.Alias != "" {
+			aliases[spec.Alias] = true
+		} else {
+			aliases[spec.Name] = true
+		}
+	}
+
+	merged := make([]models.ProcessMatcher, 0, len(names)+len(specs))
+	for _, name := range names {
+		if !aliases[name] {
+			merged = append(merged, models.ProcessMatcher{Alias: name, Name: name})
+			aliases[name] = true
+		}
+	}
+	return append(merged, specs...)
+}
+
+// processCandidate лениво читает атрибуты процесса, нужные правилам отбора
+type processCandidate struct {
+	proc *process.Process
+	name string
+
+	cmdline, username, exe, cgroup *string
+}
+
+func (pc *processCandidate) getCmdline() string {
+	if pc.cmdline == nil {
+		v, _ := pc.proc.Cmdline()
+		pc.cmdline = &v
+	}
+	return *pc.cmdline
+}
+
+func (pc *processCandidate) getUsername() string {
+	if pc.username == nil {
+		v, _ := pc.proc.Username()
+		pc.username = &v
+	}
+	return *pc.username
+}
+
+func (pc *processCandidate) getExe() string {
+	if pc.exe == nil {
+		v, _ := pc.proc.Exe()
+		pc.exe = &v
+	}
+	return *pc.exe
+}
+
+func (pc *processCandidate) getCgroup() string {
+	if pc.cgroup == nil {
+		v := readProcessCgroup(pc.proc.Pid)
+		pc.cgroup = &v
+	}
+	return *pc.cgroup
+}
+
+// matches проверяет, удовлетворяет ли процесс всем условиям правила
+func (m *processMatcher) matches(pc *processCandidate) bool {
+	if m.spec.Name != "" && m.spec.Name != pc.name {
+		return false
+	}
+	if m.nameRegex != nil && !m.nameRegex.MatchString(pc.name) {
+		return false
+	}
+	if m.spec.User != "" && m.spec.User != pc.getUsername() {
+		return false
+	}
+	if m.spec.Exe != "" && m.spec.Exe != pc.getExe() {
+		return false
+	}
+	if m.spec.Cmdline != "" && !strings.Contains(pc.getCmdline(), m.spec.Cmdline) {
+		return false
+	}
+	if m.cmdlineRegex != nil && !m.cmdlineRegex.MatchString(pc.getCmdline()) {
+		return false
+	}
+	if m.spec.Cgroup != "" && !strings.Contains(pc.getCgroup(), m.spec.Cgroup) {
+		return false
+	}
+	if m.spec.SystemdUnit != "" && !cgroupHasUnit(pc.getCgroup(), m.spec.SystemdUnit) {
+		return false
+	}
+	return true
+}
+
+// readProcessCgroup возвращает путь cgroup процесса из /proc/<pid>/cgroup.
+// Для cgroup v2 это единственная строка вида "0::/system.slice/nginx.service".
+func readProcessCgroup(pid int32) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		// Иерархия v2 приоритетнее, для v1 берем контроллер name=systemd
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if parts[1] == "name=systemd" {
+			paths = append([]string{parts[2]}, paths...)
+		} else {
+			paths = append(paths, parts[2])
+		}
+	}
+	if len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
+// cgroupHasUnit проверяет, входит ли юнит в путь cgroup
+func cgroupHasUnit(cgroupPath, unit string) bool {
+	for _, segment := range strings.Split(cgroupPath, "/") {
+		if segment == unit {
+			return true
+		}
+	}
+	return false
+}
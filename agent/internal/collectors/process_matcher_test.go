@@ -0,0 +1,139 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testProcessCandidate - процесс с заранее известными атрибутами; /proc не читается
+func testProcessCandidate(name, cmdline, user, exe, cgroup string) *processCandidate {
+	return &processCandidate{name: name, cmdline: &cmdline, username: &user, exe: &exe, cgroup: &cgroup}
+}
+
+func TestProcessMatcherMatches(t *testing.T) {
+	java := testProcessCandidate("java", "/usr/bin/java -Xmx2g -jar /opt/billing/billing-api-2.4.jar --port 8080",
+		"billing", "/usr/lib/jvm/java-17/bin/java", "/system.slice/billing-api.service")
+	nginx := testProcessCandidate("nginx", "nginx: worker process", "www-data", "/usr/sbin/nginx",
+		"/system.slice/nginx.service")
+	php := testProcessCandidate("php-fpm8.2", "php-fpm: pool www", "www-data", "/usr/sbin/php-fpm8.2",
+		"/kubepods.slice/kubepods-burstable.slice/cri-containerd-4f2a.scope")
+
+	tests := []struct {
+		name string
+		spec models.ProcessMatcher
+		want []bool // java, nginx, php
+	}{
+		{name: "alias only", spec: models.ProcessMatcher{Alias: "nginx"}, want: []bool{false, true, false}},
+		{name: "name", spec: models.ProcessMatcher{Alias: "web", Name: "nginx"}, want: []bool{false, true, false}},
+		{name: "name regex", spec: models.ProcessMatcher{Alias: "php", NameRegex: `^php-fpm\d`}, want: []bool{false, false, true}},
+		{name: "cmdline", spec: models.ProcessMatcher{Alias: "workers", Cmdline: "worker process"}, want: []bool{false, true, false}},
+		{name: "cmdline regex", spec: models.ProcessMatcher{Alias: "billing-api", Name: "java", CmdlineRegex: `billing-.*\.jar`}, want: []bool{true, false, false}},
+		{name: "user", spec: models.ProcessMatcher{Alias: "www", User: "www-data"}, want: []bool{false, true, true}},
+		{name: "user and name", spec: models.ProcessMatcher{Alias: "www", User: "www-data", Name: "nginx"}, want: []bool{false, true, false}},
+		{name: "exe", spec: models.ProcessMatcher{Alias: "jvm", Exe: "/usr/lib/jvm/java-17/bin/java"}, want: []bool{true, false, false}},
+		{name: "cgroup", spec: models.ProcessMatcher{Alias: "pods", Cgroup: "kubepods"}, want: []bool{false, false, true}},
+		{name: "systemd unit", spec: models.ProcessMatcher{Alias: "billing", SystemdUnit: "billing-api.service"}, want: []bool{true, false, false}},
+		// Юнит сравнивается с целым сегментом пути cgroup
+		{name: "systemd unit prefix", spec: models.ProcessMatcher{Alias: "billing", SystemdUnit: "billing-api"}, want: []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchers, err := compileProcessMatchers([]models.ProcessMatcher{tt.spec})
+			if err != nil {
+				t.Fatalf("compileProcessMatchers: %v", err)
+			}
+			for i, pc := range []*processCandidate{java, nginx, php} {
+				if got := matchers[0].matches(pc); got != tt.want[i] {
+					t.Errorf("matches(%s) = %v, want %v", pc.name, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileProcessMatchers(t *testing.T) {
+	matchers, err := compileProcessMatchers([]models.ProcessMatcher{
+		{Name: "redis-server"},
+		{Alias: "billing", CmdlineRegex: "billing"},
+	})
+	if err != nil {
+		t.Fatalf("compileProcessMatchers: %v", err)
+	}
+	// Без псевдонима используется имя
+	if matchers[0].spec.Alias != "redis-server" || matchers[1].spec.Name != "" {
+		t.Errorf("specs = %+v, %+v", matchers[0].spec, matchers[1].spec)
+	}
+
+	for _, tt := range []struct {
+		spec models.ProcessMatcher
+		err  string
+	}{
+		{spec: models.ProcessMatcher{User: "root"}, err: "process matcher requires alias or name"},
+		{spec: models.ProcessMatcher{Alias: "a", NameRegex: "("}, err: "matcher a: invalid name_regex"},
+		{spec: models.ProcessMatcher{Alias: "b", CmdlineRegex: "[z-a]"}, err: "matcher b: invalid cmdline_regex"},
+	} {
+		if _, err := compileProcessMatchers([]models.ProcessMatcher{tt.spec}); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("compileProcessMatchers(%+v) error = %v, want %q", tt.spec, err, tt.err)
+		}
+	}
+}
+
+func TestProcessMatchersWithNames(t *testing.T) {
+	specs := []models.ProcessMatcher{
+		{Alias: "billing-api", Name: "java", CmdlineRegex: `billing-.*\.jar`},
+		{Name: "redis-server"},
+	}
+	// ЦМ передает в processes псевдонимы всех правил, а имена без правил добавляются как точные совпадения
+	got := ProcessMatchersWithNames([]string{"nginx", "billing-api", "redis-server", "nginx", "cron"}, specs)
+	want := []models.ProcessMatcher{
+		{Alias: "nginx", Name: "nginx"},
+		{Alias: "cron", Name: "cron"},
+		{Alias: "billing-api", Name: "java", CmdlineRegex: `billing-.*\.jar`},
+		{Name: "redis-server"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchers:\n got %+v\nwant %+v", got, want)
+	}
+
+	if got := ProcessMatchersWithNames(nil, specs); !reflect.DeepEqual(got, specs) {
+		t.Errorf("matchers without names = %+v", got)
+	}
+}
+
+func TestProcessCollectorSetNames(t *testing.T) {
+	c := NewProcessCollector([]string{"nginx"})
+	if err := c.SetMatchers([]models.ProcessMatcher{{Alias: "bad", NameRegex: "("}}); err == nil {
+		t.Fatalf("SetMatchers accepted an invalid regex")
+	}
+	// Неверные правила не меняют текущие
+	if cfg := c.Config().(ProcessConfig); len(cfg.Matchers) != 1 || cfg.Matchers[0].Name != "nginx" {
+		t.Errorf("matchers after a rejected update = %+v", cfg.Matchers)
+	}
+
+	c.SetNames([]string{"redis-server", "cron"})
+	want := []models.ProcessMatcher{{Alias: "redis-server", Name: "redis-server"}, {Alias: "cron", Name: "cron"}}
+	if cfg := c.Config().(ProcessConfig); !reflect.DeepEqual(cfg.Matchers, want) {
+		t.Errorf("matchers = %+v, want %+v", cfg.Matchers, want)
+	}
+}
+
+func TestCgroupHasUnit(t *testing.T) {
+	tests := []struct {
+		path, unit string
+		want       bool
+	}{
+		{"/system.slice/nginx.service", "nginx.service", true},
+		{"/system.slice/nginx.service/worker", "nginx.service", true},
+		{"/user.slice/user-1000.slice/session-4.scope", "session-4.scope", true},
+		{"/system.slice/nginx.service", "nginx", false},
+		{"/system.slice/nginx-exporter.service", "nginx.service", false},
+		{"", "nginx.service", false},
+	}
+	for _, tt := range tests {
+		if got := cgroupHasUnit(tt.path, tt.unit); got != tt.want {
+			t.Errorf("cgroupHasUnit(%q, %q) = %v, want %v", tt.path, tt.unit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultProcRoot - точка монтирования procfs по умолчанию
+const DefaultProcRoot = "/proc"
+
+// PSICollector читает pressure stall information из /proc/pressure/{cpu,memory,io}.
+// В отличие от процентов загрузки PSI показывает, сколько времени задачи простаивали в ожидании ресурса.
+type PSICollector struct {
+	dir string
+}
+
+// NewPSICollector создает коллектор; пустой procRoot означает /proc.
+// Возвращает ошибку, если ядро собрано без PSI или PSI выключен (psi=0).
+func NewPSICollector(procRoot string) (*PSICollector, error) {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+	dir := filepath.Join(procRoot, "pressure")
+	if _, err := readPressureFile(filepath.Join(dir, "memory")); err != nil {
+		return nil, fmt.Errorf("pressure stall information is not available: %w", err)
+	}
+	return &PSICollector{dir: dir}, nil
+}
+
+func (c *PSICollector) Collect(metrics *models.AgentMetrics) error {
+	psi := readPressureDir(c.dir, "")
+	if psi == nil {
+		return fmt.Errorf("failed to read %s", c.dir)
+	}
+	metrics.System.PSI = psi
+	return nil
+}
+
+// readPressureDir читает файлы cpu, memory и io с заданным суффиксом
+// (/proc/pressure/memory или <cgroup>/memory.pressure). Возвращает nil, если ни один файл не прочитан.
+func readPressureDir(dir, suffix string) *models.PSIMetrics {
+	var psi models.PSIMetrics
+	found := false
+	for name, res := range map[string]*models.PSIResource{
+		"cpu":    &psi.CPU,
+		"memory": &psi.Memory,
+		"io":     &psi.IO,
+	} {
+		r, err := readPressureFile(filepath.Join(dir, name+suffix))
+		if err != nil {
+			continue
+		}
+		*res = r
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return &psi
+}
+
+// readPressureFile разбирает файл pressure:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressureFile(path string) (models.PSIResource, error) {
+	var res models.PSIResource
+	file, err := os.Open(path)
+	if err != nil {
+		return res, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var stats *models.PSIStats
+		switch fields[0] {
+		case "some":
+			stats = &res.Some
+		case "full":
+			stats = &res.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stats.Avg10, _ = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, _ = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, _ = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.Total, _ = strconv.ParseUint(value, 10, 64)
+			}
+		}
+	}
+	return res, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPSICollector(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		// Ядра до 5.13 выводят для cpu только строку some
+		"pressure/cpu": "some avg10=12.34 avg60=8.10 avg300=2.05 total=4812337019\n",
+		"pressure/memory": "some avg10=0.52 avg60=0.31 avg300=0.08 total=91231876\n" +
+			"full avg10=0.20 avg60=0.11 avg300=0.02 total=40512345\n",
+		"pressure/io": "some avg10=25.00 avg60=19.47 avg300=6.31 total=1283475611\n" +
+			"full avg10=21.75 avg60=16.02 avg300=5.12 total=1047732298\n",
+	})
+	c, err := NewPSICollector(root)
+	if err != nil {
+		t.Fatalf("NewPSICollector: %v", err)
+	}
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	want := &models.PSIMetrics{
+		CPU: models.PSIResource{Some: models.PSIStats{Avg10: 12.34, Avg60: 8.10, Avg300: 2.05, Total: 4812337019}},
+		Memory: models.PSIResource{
+			Some: models.PSIStats{Avg10: 0.52, Avg60: 0.31, Avg300: 0.08, Total: 91231876},
+			Full: models.PSIStats{Avg10: 0.20, Avg60: 0.11, Avg300: 0.02, Total: 40512345},
+		},
+		IO: models.PSIResource{
+			Some: models.PSIStats{Avg10: 25, Avg60: 19.47, Avg300: 6.31, Total: 1283475611},
+			Full: models.PSIStats{Avg10: 21.75, Avg60: 16.02, Avg300: 5.12, Total: 1047732298},
+		},
+	}
+	if !reflect.DeepEqual(metrics.System.PSI, want) {
+		t.Errorf("psi:\n got %+v\nwant %+v", metrics.System.PSI, want)
+	}
+}
+
+func TestPSICollectorUnavailable(t *testing.T) {
+	// Ядро без PSI: каталога /proc/pressure нет
+	if _, err := NewPSICollector(t.TempDir()); err == nil || !strings.Contains(err.Error(), "pressure stall information is not available") {
+		t.Errorf("NewPSICollector error = %v", err)
+	}
+
+	// Каталог пропал после запуска
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"pressure/memory": "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"})
+	c, err := NewPSICollector(root)
+	if err != nil {
+		t.Fatalf("NewPSICollector: %v", err)
+	}
+	c.dir = t.TempDir()
+	if err := c.Collect(&models.AgentMetrics{}); err == nil {
+		t.Errorf("Collect succeeded without pressure files")
+	}
+}
+
+func TestReadPressureDirCgroup(t *testing.T) {
+	root := t.TempDir()
+	// В cgroup без контроллеров cpu и io есть только memory.pressure
+	writeTestFiles(t, root, map[string]string{
+		"system.slice/nginx.service/memory.pressure": "some avg10=1.00 avg60=0.50 avg300=0.25 total=1000\n" +
+			"full avg10=0.40 avg60=0.20 avg300=0.10 total=400\n",
+		"system.slice/nginx.service/memory.current": "268435456\n",
+	})
+	psi := readPressureDir(filepath.Join(root, "system.slice/nginx.service"), ".pressure")
+	want := &models.PSIMetrics{Memory: models.PSIResource{
+		Some: models.PSIStats{Avg10: 1, Avg60: 0.5, Avg300: 0.25, Total: 1000},
+		Full: models.PSIStats{Avg10: 0.4, Avg60: 0.2, Avg300: 0.1, Total: 400},
+	}}
+	if !reflect.DeepEqual(psi, want) {
+		t.Errorf("psi = %+v, want %+v", psi, want)
+	}
+
+	if psi := readPressureDir(filepath.Join(root, "system.slice"), ".pressure"); psi != nil {
+		t.Errorf("psi without pressure files = %+v, want nil", psi)
+	}
+}
+
+func TestReadPressureFileSkipsUnknownFields(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"cpu": "\nsome avg10=1.00 avg60=bad avg300 total=12 extra=5\nfuture avg10=9.00\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	})
+	res, err := readPressureFile(filepath.Join(root, "cpu"))
+	if err != nil {
+		t.Fatalf("readPressureFile: %v", err)
+	}
+	want := models.PSIResource{Some: models.PSIStats{Avg10: 1, Total: 12}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("resource = %+v, want %+v", res, want)
+	}
+}
@@ -0,0 +1,467 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// RegistryTick - период проверки, каким коллекторам пора запускаться
+	RegistryTick = time.Second
+	// minCollectorInterval - минимальный интервал коллектора
+	minCollectorInterval = time.Second
+	// dueTolerance компенсирует дрожание тиков, чтобы коллектор с интервалом 10s не пропускал тик
+	dueTolerance = RegistryTick / 2
+	// DefaultCollectorTimeout - срок одного запуска коллектора, если он не задан явно
+	DefaultCollectorTimeout = 10 * time.Second
+	// publishWait - сколько снимок ждет запущенные коллекторы. Не успевшие к этому сроку
+	// записывают результат в свою ячейку по завершении, и он попадает в следующий снимок
+	publishWait = RegistryTick / 2
+)
+
+// Ошибки типизированных настроек коллекторов
+var (
+	ErrUnknownCollector     = errors.New("unknown collector")
+	ErrCollectorUnavailable = errors.New("collector is unavailable")
+	ErrNotConfigurable      = errors.New("collector has no settings")
+)
+
+// Optioner - необязательный интерфейс коллекторов, части сбора которых можно отключить
+// (CPU и диски у system, UDP у network)
+type Optioner interface {
+	Options() map[string]bool
+	SetOptions(options map[string]bool) error
+}
+
+// Registry хранит коллекторы по именам. У каждого коллектора свой интервал и признак включения;
+// результат последнего запуска хранится отдельно и подставляется в каждый снимок метрик,
+// поэтому редко запускаемые коллекторы (inventory раз в час) не пропадают из ответа агента.
+// Коллекторы запускаются параллельно, каждый со своим сроком: медленный коллектор (system
+// с замером CPU за секунду) не задерживает снимок остальных и попадает в следующий, а у зависшего
+// (например, при недоступном Docker) секция в снимке отсутствует.
+type Registry struct {
+	mu              sync.RWMutex
+	entries         []*registryEntry
+	byName          map[string]*registryEntry
+	defaultInterval time.Duration
+	updated         bool // После последнего снимка завершился хотя бы один запуск
+
+	// Контекст и обработчик событий наблюдателей; заданы после StartWatchers
+	watchCtx  context.Context
+	watchWG   *sync.WaitGroup
+	watchEmit func(events ...models.Event)
+}
+
+type registryEntry struct {
+	name        string
+	collector   Collector // nil, если коллектор недоступен на этом хосте
+	unavailable string    // Причина недоступности
+	interval    time.Duration
+	timeout     time.Duration
+	enabled     bool
+
+	slot    models.AgentMetrics // Результат последнего запуска без событий
+	events  []models.Event      // События запусков, еще не попавшие в снимок
+	lastRun time.Time
+	status  models.CollectorRunStatus
+	running bool // Запуск, не завершившийся к сроку, еще выполняется; новый не начинается
+	runs    uint64
+
+	stopWatch context.CancelFunc
+}
+
+// NewRegistry создает реестр; defaultInterval используется для коллекторов без собственного интервала
+func NewRegistry(defaultInterval time.Duration) *Registry {
+	return &Registry{
+		byName:          make(map[string]*registryEntry),
+		defaultInterval: defaultInterval,
+	}
+}
+
+// Register добавляет включенный коллектор; interval 0 означает интервал по умолчанию
+func (r *Registry) Register(name string, c Collector, interval time.Duration) {
+	r.add(&registryEntry{name: name, collector: c, interval: interval, enabled: true})
+}
+
+// RegisterUnavailable отмечает коллектор, который не удалось создать, чтобы причина была видна в GET /collectors
+func (r *Registry) RegisterUnavailable(name string, err error) {
+	r.add(&registryEntry{name: name, unavailable: err.Error()})
+}
+
+func (r *Registry) add(e *registryEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.byName[e.name]; ok {
+		*old = *e
+		return
+	}
+	r.entries = append(r.entries, e)
+	r.byName[e.name] = e
+}
+
+// Collectors возвращает доступные коллекторы, включая отключенные
+func (r *Registry) Collectors() []Collector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	collectors := make([]Collector, 0, len(r.entries))
+	for _, e := range r.entries {
+		if e.collector != nil {
+			collectors = append(collectors, e.collector)
+		}
+	}
+	return collectors
+}
+
+// SetDefaultInterval меняет интервал коллекторов, для которых он не задан явно
+func (r *Registry) SetDefaultInterval(interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultInterval = interval
+}
+
+// Configure применяет настройки коллекторов. Настройки сначала проверяются целиком:
+// при ошибке ни один коллектор не меняется. Настройки недоступных на хосте коллекторов
+// пропускаются: ЦМ рассылает одинаковые настройки всем агентам
+func (r *Registry) Configure(settings []models.CollectorSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range settings {
+		e, ok := r.byName[s.Name]
+		if !ok {
+			return fmt.Errorf("unknown collector %q", s.Name)
+		}
+		if e.collector == nil {
+			continue
+		}
+		if s.Interval < 0 || (s.Interval > 0 && time.Duration(s.Interval)*time.Second < minCollectorInterval) {
+			return fmt.Errorf("collector %s: invalid interval %d", s.Name, s.Interval)
+		}
+		if s.Timeout < 0 {
+			return fmt.Errorf("collector %s: invalid timeout %d", s.Name, s.Timeout)
+		}
+		if len(s.Options) > 0 {
+			o, ok := e.collector.(Optioner)
+			if !ok {
+				return fmt.Errorf("collector %s has no options", s.Name)
+			}
+			for name := range s.Options {
+				if _, ok := o.Options()[name]; !ok {
+					return fmt.Errorf("collector %s: unknown option %q", s.Name, name)
+				}
+			}
+		}
+	}
+
+	for _, s := range settings {
+		e := r.byName[s.Name]
+		if e.collector == nil {
+			continue
+		}
+		if s.Interval > 0 {
+			e.interval = time.Duration(s.Interval) * time.Second
+		}
+		if s.Timeout > 0 {
+			e.timeout = time.Duration(s.Timeout) * time.Second
+		}
+		if len(s.Options) > 0 {
+			if err := e.collector.(Optioner).SetOptions(s.Options); err != nil {
+				return fmt.Errorf("collector %s: %w", s.Name, err)
+			}
+		}
+		if s.Enabled != nil && *s.Enabled != e.enabled {
+			e.enabled = *s.Enabled
+			if e.enabled {
+				r.startWatch(e)
+			} else {
+				// Старый результат отключенного коллектора больше не попадает в метрики
+				e.slot = models.AgentMetrics{}
+				e.events = nil
+				e.lastRun = time.Time{}
+				e.status = models.CollectorRunStatus{}
+				if e.stopWatch != nil {
+					e.stopWatch()
+					e.stopWatch = nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// configurable возвращает коллектор с типизированными настройками по имени
+func (r *Registry) configurable(name string) (Configurable, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCollector, name)
+	}
+	if e.collector == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCollectorUnavailable, e.unavailable)
+	}
+	c, ok := e.collector.(Configurable)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotConfigurable, name)
+	}
+	return c, nil
+}
+
+// Config возвращает схему и текущие настройки коллектора
+func (r *Registry) Config(name string) (*Schema, interface{}, error) {
+	c, err := r.configurable(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.ConfigSchema(), c.Config(), nil
+}
+
+// SetConfig проверяет настройки коллектора по его схеме и применяет их
+func (r *Registry) SetConfig(name string, raw []byte) error {
+	c, err := r.configurable(name)
+	if err != nil {
+		return err
+	}
+	return c.SetConfig(raw)
+}
+
+// StartWatchers запускает наблюдение за событиями у включенных коллекторов, реализующих Watcher.
+// Коллекторы, включенные позже, начинают наблюдение при включении
+func (r *Registry) StartWatchers(ctx context.Context, wg *sync.WaitGroup, emit func(events ...models.Event)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watchCtx, r.watchWG, r.watchEmit = ctx, wg, emit
+	for _, e := range r.entries {
+		if e.enabled {
+			r.startWatch(e)
+		}
+	}
+}
+
+// startWatch вызывается под r.mu
+func (r *Registry) startWatch(e *registryEntry) {
+	w, ok := e.collector.(Watcher)
+	if !ok || r.watchCtx == nil || r.watchCtx.Err() != nil || e.stopWatch != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(r.watchCtx)
+	e.stopWatch = cancel
+	r.watchWG.Add(1)
+	go func() {
+		defer r.watchWG.Done()
+		w.Watch(ctx, r.watchEmit)
+	}()
+}
+
+// RunDue параллельно запускает коллекторы, которым пора собирать метрики, и собирает в metrics
+// последние результаты и состояние всех включенных коллекторов. Снимок ждет запущенные коллекторы
+// не дольше publishWait; остальные допишут результат позже, и он попадет в следующий снимок.
+// События попадают в metrics один раз. Возвращает false, если ни один коллектор не запускался
+// и не завершался после предыдущего снимка
+func (r *Registry) RunDue(ctx context.Context, now time.Time, metrics *models.AgentMetrics) bool {
+	r.mu.Lock()
+	var due []*registryEntry
+	for _, e := range r.entries {
+		if e.enabled && e.collector != nil && !e.running && (e.lastRun.IsZero() || now.Sub(e.lastRun) >= r.intervalOf(e)-dueTolerance) {
+			e.running = true
+			due = append(due, e)
+		}
+	}
+	updated := r.updated
+	r.mu.Unlock()
+	if len(due) == 0 && !updated {
+		return false
+	}
+
+	done := make(chan struct{}, len(due))
+	for _, e := range due {
+		go func(e *registryEntry) {
+			r.run(ctx, e, now)
+			done <- struct{}{}
+		}(e)
+	}
+
+	wait := time.NewTimer(publishWait)
+	defer wait.Stop()
+waitLoop:
+	for range due {
+		select {
+		case <-done:
+		case <-wait.C:
+			break waitLoop
+		case <-ctx.Done():
+			break waitLoop
+		}
+	}
+
+	r.mu.Lock()
+	for _, e := range r.entries {
+		if !e.enabled || e.lastRun.IsZero() {
+			continue
+		}
+		mergeMetrics(reflect.ValueOf(metrics).Elem(), reflect.ValueOf(&e.slot).Elem())
+		if metrics.CollectorStatus == nil {
+			metrics.CollectorStatus = make(map[string]models.CollectorRunStatus)
+		}
+		metrics.CollectorStatus[e.name] = e.status
+		metrics.Events = append(metrics.Events, e.events...)
+		e.events = nil
+	}
+	r.updated = false
+	r.mu.Unlock()
+	return true
+}
+
+// collectResult - результат одного запуска коллектора
+type collectResult struct {
+	slot models.AgentMetrics
+	err  error
+}
+
+// run запускает коллектор и ждет его не дольше срока, затем записывает результат в ячейку коллектора.
+// Коллектор, не успевший к сроку, продолжает работу в фоне, но его результат отбрасывается
+func (r *Registry) run(ctx context.Context, e *registryEntry, now time.Time) {
+	r.mu.RLock()
+	timeout := r.timeoutOf(e)
+	r.mu.RUnlock()
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan collectResult, 1)
+	go func() {
+		done <- collect(runCtx, e.collector)
+	}()
+
+	var res collectResult
+	status := models.CollectorRunStatus{Status: models.CollectorOK, Timestamp: now}
+	select {
+	case res = <-done:
+		if res.err != nil {
+			log.Printf("Collector %s error: %v", e.name, res.err)
+			status.Status = models.CollectorError
+			status.Message = res.err.Error()
+		}
+	case <-runCtx.Done():
+		log.Printf("Collector %s did not finish in %s", e.name, timeout)
+		status.Status = models.CollectorTimeout
+		status.Message = fmt.Sprintf("collector did not finish in %s", timeout)
+		go func() {
+			<-done
+			r.mu.Lock()
+			e.running = false
+			r.mu.Unlock()
+		}()
+	}
+	status.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if status.Status != models.CollectorTimeout {
+		e.running = false
+	}
+	// Коллектор могли отключить во время сбора
+	if e.enabled {
+		e.slot = res.slot
+		e.slot.Events = nil
+		e.events = append(e.events, res.slot.Events...)
+		e.lastRun = now
+		e.status = status
+		r.updated = true
+	}
+	e.runs++
+}
+
+// collect вызывает коллектор; паника коллектора возвращается как ошибка и не останавливает агент
+func collect(ctx context.Context, c Collector) (res collectResult) {
+	defer func() {
+		if p := recover(); p != nil {
+			res.err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	if cc, ok := c.(ContextCollector); ok {
+		res.err = cc.CollectContext(ctx, &res.slot)
+	} else {
+		res.err = c.Collect(&res.slot)
+	}
+	return res
+}
+
+// intervalOf вызывается под r.mu
+func (r *Registry) intervalOf(e *registryEntry) time.Duration {
+	if e.interval > 0 {
+		return e.interval
+	}
+	return r.defaultInterval
+}
+
+// timeoutOf вызывается под r.mu
+func (r *Registry) timeoutOf(e *registryEntry) time.Duration {
+	if e.timeout > 0 {
+		return e.timeout
+	}
+	return DefaultCollectorTimeout
+}
+
+// Status возвращает состояние коллекторов в порядке имен
+func (r *Registry) Status() []models.CollectorStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]models.CollectorStatus, 0, len(r.entries))
+	for _, e := range r.entries {
+		st := models.CollectorStatus{
+			Name:            e.name,
+			Available:       e.collector != nil,
+			Enabled:         e.enabled,
+			IntervalSeconds: r.intervalOf(e).Seconds(),
+			TimeoutSeconds:  r.timeoutOf(e).Seconds(),
+			Watching:        e.stopWatch != nil,
+			LastDurationMs:  e.status.DurationMs,
+			LastStatus:      e.status.Status,
+			LastError:       e.status.Message,
+			Running:         e.running,
+			Runs:            e.runs,
+		}
+		if e.collector == nil {
+			st.LastError = e.unavailable
+		}
+		if o, ok := e.collector.(Optioner); ok {
+			st.Options = o.Options()
+		}
+		if !e.lastRun.IsZero() {
+			lastRun := e.lastRun
+			st.LastRun = &lastRun
+		}
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// mergeMetrics дополняет dst непустыми полями src: срезы добавляются, остальные значения
+// заменяются, вложенные структуры (System) объединяются по полям
+func mergeMetrics(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		s, d := src.Field(i), dst.Field(i)
+		if !d.CanSet() || s.IsZero() {
+			continue
+		}
+		switch {
+		case s.Kind() == reflect.Slice:
+			d.Set(reflect.AppendSlice(d, s))
+		case s.Kind() == reflect.Struct && s.Type() != reflect.TypeOf(time.Time{}):
+			mergeMetrics(d, s)
+		default:
+			d.Set(s)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+// funcCollector - коллектор из функции для тестов реестра
+type funcCollector func(ctx context.Context, metrics *models.AgentMetrics) error
+
+func (f funcCollector) Collect(metrics *models.AgentMetrics) error {
+	return f(context.Background(), metrics)
+}
+
+func (f funcCollector) CollectContext(ctx context.Context, metrics *models.AgentMetrics) error {
+	return f(ctx, metrics)
+}
+
+func TestRegistryRunDueDoesNotWaitForSlowCollectors(t *testing.T) {
+	slowDone := make(chan struct{})
+	r := NewRegistry(time.Minute)
+	r.Register("fast", funcCollector(func(ctx context.Context, m *models.AgentMetrics) error {
+		m.Probes = []models.ProbeResult{{Name: "web", Up: true}}
+		m.Events = []models.Event{{Source: "fast"}}
+		return nil
+	}), 0)
+	r.Register("slow", funcCollector(func(ctx context.Context, m *models.AgentMetrics) error {
+		defer close(slowDone)
+		time.Sleep(publishWait + 300*time.Millisecond)
+		m.Sensors = []models.SensorReading{{Chip: "coretemp"}}
+		m.Events = []models.Event{{Source: "slow"}}
+		return nil
+	}), 0)
+
+	ctx := context.Background()
+	now := time.Now()
+	start := time.Now()
+	var first models.AgentMetrics
+	if !r.RunDue(ctx, now, &first) {
+		t.Fatalf("RunDue did not run due collectors")
+	}
+	if elapsed := time.Since(start); elapsed >= publishWait+200*time.Millisecond {
+		t.Errorf("RunDue waited %v for the slow collector", elapsed)
+	}
+	if len(first.Probes) != 1 || first.Sensors != nil {
+		t.Errorf("first snapshot: probes %d sensors %v", len(first.Probes), first.Sensors)
+	}
+	if _, ok := first.CollectorStatus["slow"]; ok {
+		t.Errorf("slow collector reported before its first run finished")
+	}
+	if len(first.Events) != 1 || first.Events[0].Source != "fast" {
+		t.Errorf("first snapshot events = %+v", first.Events)
+	}
+
+	// Пока медленный коллектор работает, нового снимка нет: запускать некого, результатов нет
+	var idle models.AgentMetrics
+	if r.RunDue(ctx, now.Add(time.Second), &idle) {
+		t.Errorf("RunDue published a snapshot without new results")
+	}
+
+	<-slowDone
+	time.Sleep(50 * time.Millisecond)
+
+	// Результат медленного коллектора попадает в следующий снимок, события fast не повторяются
+	var second models.AgentMetrics
+	if !r.RunDue(ctx, now.Add(2*time.Second), &second) {
+		t.Fatalf("RunDue did not publish the late result")
+	}
+	if len(second.Sensors) != 1 || len(second.Probes) != 1 {
+		t.Errorf("second snapshot: probes %d sensors %d", len(second.Probes), len(second.Sensors))
+	}
+	if st := second.CollectorStatus["slow"]; st.Status != models.CollectorOK {
+		t.Errorf("slow status = %+v", st)
+	}
+	if len(second.Events) != 1 || second.Events[0].Source != "slow" {
+		t.Errorf("second snapshot events = %+v", second.Events)
+	}
+
+	var third models.AgentMetrics
+	if r.RunDue(ctx, now.Add(3*time.Second), &third) {
+		t.Errorf("RunDue published a snapshot without new results")
+	}
+}
+
+func TestRegistryRunDueTimeout(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.Register("hung", funcCollector(func(ctx context.Context, m *models.AgentMetrics) error {
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond) // Коллектор замечает отмену не сразу
+		m.Probes = []models.ProbeResult{{Name: "late"}}
+		return ctx.Err()
+	}), 0)
+	if err := r.Configure([]models.CollectorSettings{{Name: "hung", Timeout: 1}}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	var first models.AgentMetrics
+	if !r.RunDue(ctx, now, &first) {
+		t.Fatalf("RunDue did not run the collector")
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	var snapshot models.AgentMetrics
+	for !r.RunDue(ctx, now.Add(time.Second), &snapshot) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout status was never published")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if st := snapshot.CollectorStatus["hung"]; st.Status != models.CollectorTimeout {
+		t.Errorf("status = %+v, want timeout", st)
+	}
+	if snapshot.Probes != nil {
+		t.Errorf("result of the timed out run was published: %+v", snapshot.Probes)
+	}
+}
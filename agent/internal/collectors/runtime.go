@@ -0,0 +1,160 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Поддерживаемые среды выполнения контейнеров
+const (
+	RuntimeAuto       = "auto"
+	RuntimeDocker     = "docker"
+	RuntimePodman     = "podman"
+	RuntimeContainerd = "containerd"
+	RuntimeCRIO       = "cri-o"
+)
+
+// ContainerRuntime абстрагирует среду выполнения контейнеров.
+// Реализации переводят ответы своего API в общие структуры, из которых коллектор строит ContainerInfo.
+type ContainerRuntime interface {
+	// Name возвращает имя среды выполнения (docker, podman, containerd, cri-o)
+	Name() string
+	// List возвращает контейнеры; all - включая остановленные
+	List(ctx context.Context, all bool) ([]RuntimeContainer, error)
+	// Inspect возвращает состояние контейнера: перезапуски, код выхода, OOM, healthcheck
+	Inspect(ctx context.Context, id string) (RuntimeState, error)
+	// Stats возвращает разовый снимок потребления ресурсов
+	Stats(ctx context.Context, id string) (RuntimeStats, error)
+}
+
+// statsStreamer - необязательный интерфейс сред, умеющих отдавать статистику потоком
+type statsStreamer interface {
+	// StreamStats вызывает update на каждый снимок до ошибки или отмены контекста
+	StreamStats(ctx context.Context, id string, update func(RuntimeStats)) error
+}
+
+// eventSource - необязательный интерфейс сред, умеющих сообщать о событиях контейнеров
+type eventSource interface {
+	// Events передает события начиная с since до ошибки или отмены контекста
+	Events(ctx context.Context, since time.Time, emit func(RuntimeEvent)) error
+}
+
+// RuntimeContainer - контейнер из списка среды выполнения
+type RuntimeContainer struct {
+	ID     string
+	Names  []string
+	Image  string
+	State  string // running, exited, created, ...
+	Labels map[string]string
+}
+
+// RuntimeState - состояние контейнера из inspect
+type RuntimeState struct {
+	Running      bool
+	RestartCount int
+	ExitCode     int
+	OOMKilled    bool
+	Health       string
+	StartedAt    time.Time
+}
+
+// RuntimeStats - снимок потребления ресурсов; сетевые и дисковые счетчики накопительные
+type RuntimeStats struct {
+	CPUPercent       float64
+	MemoryUsageBytes uint64 // без учета страничного кэша
+	MemoryLimitBytes uint64
+	MemoryCacheBytes uint64
+	MemoryRSSBytes   uint64
+	NetRxBytes       uint64
+	NetTxBytes       uint64
+	BlockReadBytes   uint64
+	BlockWriteBytes  uint64
+	PIDs             uint64
+}
+
+// RuntimeEvent - событие контейнера
+type RuntimeEvent struct {
+	Time       time.Time
+	Action     string // create, start, die, oom, kill, health_status
+	ID         string
+	Name       string
+	Image      string
+	Attributes map[string]string // метки контейнера и сведения о событии (exitCode, signal, ...)
+}
+
+// runtimeSocket - сокет, по которому определяется среда выполнения
+type runtimeSocket struct {
+	runtime string
+	path    string
+}
+
+// defaultRuntimeSockets возвращает сокеты в порядке проверки при автоопределении
+func defaultRuntimeSockets() []runtimeSocket {
+	sockets := []runtimeSocket{
+		{RuntimeDocker, "/var/run/docker.sock"},
+		{RuntimePodman, "/run/podman/podman.sock"},
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		sockets = append(sockets, runtimeSocket{RuntimePodman, filepath.Join(dir, "podman", "podman.sock")})
+	}
+	return append(sockets,
+		runtimeSocket{RuntimeContainerd, "/run/containerd/containerd.sock"},
+		runtimeSocket{RuntimeCRIO, "/var/run/crio/crio.sock"},
+	)
+}
+
+// DetectContainerRuntime подключается к заданной среде выполнения или находит ее по сокетам.
+// Пустой endpoint означает сокет по умолчанию; для Docker также учитывается DOCKER_HOST.
+func DetectContainerRuntime(kind, endpoint string) (ContainerRuntime, error) {
+	if kind == "" {
+		kind = RuntimeAuto
+	}
+
+	if kind == RuntimeAuto {
+		if endpoint != "" {
+			return nil, fmt.Errorf("container runtime endpoint %s requires explicit runtime", endpoint)
+		}
+		if os.Getenv("DOCKER_HOST") != "" {
+			return newDockerRuntime(RuntimeDocker, "")
+		}
+		for _, s := range defaultRuntimeSockets() {
+			if isSocket(s.path) {
+				return newRuntime(s.runtime, "unix://"+s.path)
+			}
+		}
+		return nil, fmt.Errorf("no container runtime socket found")
+	}
+
+	if endpoint == "" {
+		for _, s := range defaultRuntimeSockets() {
+			if s.runtime == kind && isSocket(s.path) {
+				endpoint = "unix://" + s.path
+				break
+			}
+		}
+	}
+	return newRuntime(kind, endpoint)
+}
+
+func newRuntime(kind, endpoint string) (ContainerRuntime, error) {
+	switch kind {
+	case RuntimeDocker, RuntimePodman:
+		// Podman предоставляет Docker-совместимый API
+		return newDockerRuntime(kind, endpoint)
+	case RuntimeContainerd, RuntimeCRIO:
+		if endpoint == "" {
+			return nil, fmt.Errorf("%s socket not found", kind)
+		}
+		return newCRIRuntime(kind, endpoint)
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", kind)
+	}
+}
+
+func isSocket(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
@@ -0,0 +1,180 @@
+package collectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema - подмножество JSON Schema, которым описываются настройки коллекторов:
+// type, properties, required, additionalProperties, items, enum, minimum/maximum,
+// minLength, minItems, pattern и format "regex"
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"` // По умолчанию false: опечатка в имени поля - ошибка
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Format               string             `json:"format,omitempty"` // regex - строка должна быть регулярным выражением
+}
+
+// mustSchema разбирает схему из JSON; ошибка в схеме - ошибка программы
+func mustSchema(src string) *Schema {
+	var s Schema
+	dec := json.NewDecoder(bytes.NewReader([]byte(src)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		panic(fmt.Sprintf("collectors: invalid config schema: %v", err))
+	}
+	return &s
+}
+
+// Validate проверяет документ, разобранный encoding/json в interface{}
+func (s *Schema) Validate(doc interface{}) error {
+	return s.validate(doc, "config")
+}
+
+func (s *Schema) validate(v interface{}, path string) error {
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value %v is not one of %v", path, v, s.Enum)
+		}
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required field %q", path, name)
+			}
+		}
+		// Поля проверяются в порядке имен, чтобы ошибка не зависела от порядка обхода map
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && *s.AdditionalProperties {
+					continue
+				}
+				return fmt.Errorf("%s: unknown field %q", path, name)
+			}
+			if err := prop.validate(obj[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			return fmt.Errorf("%s: expected at least %d items", path, *s.MinItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+		if s.MinLength != nil && len([]rune(str)) < *s.MinLength {
+			return fmt.Errorf("%s: expected at least %d characters", path, *s.MinLength)
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid schema pattern: %w", path, err)
+			}
+			if !re.MatchString(str) {
+				return fmt.Errorf("%s: %q does not match %s", path, str, s.Pattern)
+			}
+		}
+		if s.Format == "regex" {
+			if _, err := regexp.Compile(str); err != nil {
+				return fmt.Errorf("%s: invalid regular expression: %w", path, err)
+			}
+		}
+	case "integer", "number":
+		num, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s: expected %s", path, s.Type)
+		}
+		if s.Type == "integer" && num != math.Trunc(num) {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			return fmt.Errorf("%s: %v is less than %v", path, num, *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			return fmt.Errorf("%s: %v is greater than %v", path, num, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+	return nil
+}
+
+// decodeConfig проверяет настройки по схеме и разбирает их поверх текущих значений dst
+// (указатель на структуру): поля, отсутствующие в raw, не меняются, указанные заменяются целиком
+func decodeConfig(schema *Schema, raw []byte, dst interface{}) error {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		return err
+	}
+
+	// encoding/json дописывает элементы срезов поверх старых, не обнуляя их
+	if obj, ok := doc.(map[string]interface{}); ok {
+		v := reflect.ValueOf(dst).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			if _, ok := obj[name]; ok {
+				v.Field(i).Set(reflect.Zero(v.Field(i).Type()))
+			}
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(dst)
+}
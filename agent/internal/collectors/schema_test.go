@@ -0,0 +1,133 @@
+package collectors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testConfigSchema - схема, в которой есть все проверяемые ключевые слова
+var testConfigSchema = mustSchema(`{
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+		"mode": {"type": "string", "enum": ["fast", "slow"]},
+		"workers": {"type": "integer", "minimum": 1, "maximum": 8},
+		"ratio": {"type": "number", "maximum": 1},
+		"regex": {"type": "string", "format": "regex"},
+		"enabled": {"type": "boolean"},
+		"paths": {"type": "array", "minItems": 1, "items": {"type": "string"}},
+		"labels": {"type": "object", "additionalProperties": true}
+	}
+}`)
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		err  string // Пусто - документ корректен
+	}{
+		{name: "valid", doc: `{"name": "web", "mode": "fast", "workers": 4, "ratio": 0.5, "regex": "^a+$",
+			"enabled": true, "paths": ["/"], "labels": {"any": 1}}`},
+		{name: "only required", doc: `{"name": "web"}`},
+		{name: "not object", doc: `[]`, err: "config: expected object"},
+		{name: "unknown field", doc: `{"name": "web", "workerz": 4}`, err: `config: unknown field "workerz"`},
+		{name: "required", doc: `{"mode": "fast"}`, err: `config: missing required field "name"`},
+		{name: "min length", doc: `{"name": ""}`, err: "config.name: expected at least 1 characters"},
+		{name: "pattern", doc: `{"name": "Web"}`, err: `config.name: "Web" does not match ^[a-z]+$`},
+		{name: "enum", doc: `{"name": "web", "mode": "turbo"}`, err: "config.mode: value turbo is not one of [fast slow]"},
+		{name: "integer", doc: `{"name": "web", "workers": 1.5}`, err: "config.workers: expected integer"},
+		{name: "integer type", doc: `{"name": "web", "workers": "4"}`, err: "config.workers: expected integer"},
+		{name: "minimum", doc: `{"name": "web", "workers": 0}`, err: "config.workers: 0 is less than 1"},
+		{name: "maximum", doc: `{"name": "web", "workers": 9}`, err: "config.workers: 9 is greater than 8"},
+		{name: "number maximum", doc: `{"name": "web", "ratio": 1.5}`, err: "config.ratio: 1.5 is greater than 1"},
+		{name: "format regex", doc: `{"name": "web", "regex": "("}`, err: "config.regex: invalid regular expression"},
+		{name: "boolean", doc: `{"name": "web", "enabled": "yes"}`, err: "config.enabled: expected boolean"},
+		{name: "min items", doc: `{"name": "web", "paths": []}`, err: "config.paths: expected at least 1 items"},
+		{name: "items", doc: `{"name": "web", "paths": ["/", 1]}`, err: "config.paths[1]: expected string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg testConfig
+			err := decodeConfig(testConfigSchema, []byte(tt.doc), &cfg)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// testConfig - настройки, соответствующие testConfigSchema
+type testConfig struct {
+	Name    string                 `json:"name"`
+	Mode    string                 `json:"mode,omitempty"`
+	Workers int                    `json:"workers"`
+	Ratio   float64                `json:"ratio"`
+	Regex   string                 `json:"regex"`
+	Enabled bool                   `json:"enabled"`
+	Paths   []string               `json:"paths"`
+	Labels  map[string]interface{} `json:"labels"`
+}
+
+func TestDecodeConfigMerge(t *testing.T) {
+	cfg := testConfig{
+		Name:    "web",
+		Mode:    "fast",
+		Workers: 4,
+		Paths:   []string{"/var/log", "/var/lib", "/srv"},
+		Labels:  map[string]interface{}{"env": "prod", "team": "ops"},
+	}
+
+	// Указанные поля заменяются целиком, остальные не меняются
+	raw := `{"name": "api", "paths": ["/opt"], "labels": {"env": "test"}}`
+	if err := decodeConfig(testConfigSchema, []byte(raw), &cfg); err != nil {
+		t.Fatalf("decodeConfig: %v", err)
+	}
+	want := testConfig{
+		Name:    "api",
+		Mode:    "fast",
+		Workers: 4,
+		Paths:   []string{"/opt"},
+		Labels:  map[string]interface{}{"env": "test"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+
+	// Пустой объект заменяет прежний целиком, а не дополняет его
+	if err := decodeConfig(testConfigSchema, []byte(`{"name": "api", "labels": {}}`), &cfg); err != nil {
+		t.Fatalf("decodeConfig: %v", err)
+	}
+	if len(cfg.Labels) != 0 || !reflect.DeepEqual(cfg.Paths, []string{"/opt"}) {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	// Неверные настройки не меняют текущие
+	before := cfg
+	if err := decodeConfig(testConfigSchema, []byte(`{"name": "web", "workers": 100}`), &cfg); err == nil {
+		t.Fatalf("decodeConfig accepted workers above maximum")
+	}
+	if !reflect.DeepEqual(cfg, before) {
+		t.Errorf("config changed after a rejected update: %+v", cfg)
+	}
+
+	if err := decodeConfig(testConfigSchema, []byte(`{"name": `), &cfg); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON") {
+		t.Errorf("error = %v, want invalid JSON", err)
+	}
+}
+
+func TestMustSchemaPanicsOnUnknownKeyword(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("mustSchema accepted an unknown keyword")
+		}
+	}()
+	mustSchema(`{"type": "object", "propertys": {}}`)
+}
@@ -0,0 +1,229 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSysfsRoot - точка монтирования sysfs по умолчанию
+const DefaultSysfsRoot = "/sys"
+
+// Типы датчиков
+const (
+	SensorTemperature = "temperature"
+	SensorFan         = "fan"
+	SensorVoltage     = "voltage"
+)
+
+// hwmonInputs - префиксы файлов hwmon, тип датчика и делитель значения (градусы и вольты даны в тысячных)
+var hwmonInputs = []struct {
+	prefix  string
+	kind    string
+	divisor float64
+}{
+	{"temp", SensorTemperature, 1000},
+	{"fan", SensorFan, 1},
+	{"in", SensorVoltage, 1000},
+}
+
+// SensorsCollector читает температуры, обороты вентиляторов и напряжения из /sys/class/hwmon
+// и температуры зон из /sys/class/thermal. Показания передаются как sensor.<chip>.<label>.
+type SensorsCollector struct {
+	hwmonDir   string
+	thermalDir string
+}
+
+// NewSensorsCollector создает коллектор; пустой sysfsRoot означает /sys.
+// Возвращает ошибку, если в системе нет ни одного датчика (виртуальные машины, контейнеры).
+func NewSensorsCollector(sysfsRoot string) (*SensorsCollector, error) {
+	if sysfsRoot == "" {
+		sysfsRoot = DefaultSysfsRoot
+	}
+	c := &SensorsCollector{
+		hwmonDir:   filepath.Join(sysfsRoot, "class", "hwmon"),
+		thermalDir: filepath.Join(sysfsRoot, "class", "thermal"),
+	}
+	if len(c.read()) == 0 {
+		return nil, errors.New("no hardware sensors found")
+	}
+	return c, nil
+}
+
+func (c *SensorsCollector) Collect(metrics *models.AgentMetrics) error {
+	readings := c.read()
+	if len(readings) == 0 {
+		return fmt.Errorf("failed to read sensors from %s", c.hwmonDir)
+	}
+	metrics.Sensors = append(metrics.Sensors, readings...)
+	return nil
+}
+
+// read собирает показания всех датчиков. Одинаковые имена микросхем (несколько nvme)
+// и меток различаются суффиксом _1, _2 в порядке каталогов
+func (c *SensorsCollector) read() []models.SensorReading {
+	var readings []models.SensorReading
+	chips := make(map[string]int)
+
+	for _, dir := range sortedDirs(c.hwmonDir, "hwmon") {
+		chip := readSysfsString(filepath.Join(dir, "name"))
+		if chip == "" {
+			chip = filepath.Base(dir)
+		}
+		chip = uniqueName(chips, sanitizeSensorName(chip))
+		readings = append(readings, readHwmon(dir, chip)...)
+	}
+
+	labels := make(map[string]int)
+	for _, dir := range sortedDirs(c.thermalDir, "thermal_zone") {
+		temp, ok := readSysfsFloat(filepath.Join(dir, "temp"))
+		if !ok {
+			continue
+		}
+		label := readSysfsString(filepath.Join(dir, "type"))
+		if label == "" {
+			label = filepath.Base(dir)
+		}
+		readings = append(readings, models.SensorReading{
+			Chip:  "thermal",
+			Label: uniqueName(labels, sanitizeSensorName(label)),
+			Type:  SensorTemperature,
+			Value: temp / 1000,
+			Crit:  thermalCritical(dir),
+		})
+	}
+	return readings
+}
+
+// readHwmon читает датчики одного устройства hwmon. В старых ядрах файлы лежат в подкаталоге device
+func readHwmon(dir, chip string) []models.SensorReading {
+	inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+	if len(inputs) == 0 {
+		dir = filepath.Join(dir, "device")
+		inputs, _ = filepath.Glob(filepath.Join(dir, "*_input"))
+	}
+	sort.Strings(inputs)
+
+	var readings []models.SensorReading
+	labels := make(map[string]int)
+	for _, input := range inputs {
+		// temp1_input -> temp1; тип определяется по префиксу до номера
+		base := strings.TrimSuffix(filepath.Base(input), "_input")
+		prefix := strings.TrimRight(base, "0123456789")
+		for _, in := range hwmonInputs {
+			if prefix != in.prefix {
+				continue
+			}
+			value, ok := readSysfsFloat(input)
+			if !ok {
+				break
+			}
+			label := readSysfsString(filepath.Join(dir, base+"_label"))
+			if label == "" {
+				label = base
+			}
+			r := models.SensorReading{
+				Chip:  chip,
+				Label: uniqueName(labels, sanitizeSensorName(label)),
+				Type:  in.kind,
+				Value: value / in.divisor,
+			}
+			if v, ok := readSysfsFloat(filepath.Join(dir, base+"_max")); ok {
+				r.Max = v / in.divisor
+			}
+			if v, ok := readSysfsFloat(filepath.Join(dir, base+"_crit")); ok {
+				r.Crit = v / in.divisor
+			}
+			readings = append(readings, r)
+			break
+		}
+	}
+	return readings
+}
+
+// thermalCritical возвращает температуру точки срабатывания типа critical или 0
+func thermalCritical(dir string) float64 {
+	types, _ := filepath.Glob(filepath.Join(dir, "trip_point_*_type"))
+	for _, t := range types {
+		if readSysfsString(t) != "critical" {
+			continue
+		}
+		if v, ok := readSysfsFloat(strings.TrimSuffix(t, "_type") + "_temp"); ok {
+			return v / 1000
+		}
+	}
+	return 0
+}
+
+// sortedDirs возвращает каталоги с заданным префиксом в порядке номеров (hwmon2 раньше hwmon10)
+func sortedDirs(dir, prefix string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) {
+			dirs = append(dirs, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		ni, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(dirs[i]), prefix))
+		nj, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(dirs[j]), prefix))
+		return ni < nj
+	})
+	return dirs
+}
+
+// sanitizeSensorName приводит имя к виду, пригодному для имени метрики:
+// "Package id 0" -> package_id_0. Точки заменяются, так как разделяют части имени метрики
+func sanitizeSensorName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// uniqueName добавляет к повторяющемуся имени порядковый суффикс
+func uniqueName(seen map[string]int, name string) string {
+	n := seen[name]
+	seen[name] = n + 1
+	if n == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", name, n)
+}
+
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysfsFloat читает числовой атрибут; датчики, которые не отвечают, возвращают ошибку чтения
+func readSysfsFloat(path string) (float64, bool) {
+	s := readSysfsString(path)
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
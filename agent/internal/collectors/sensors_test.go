@@ -0,0 +1,161 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"testing"
+)
+
+// testSysfs - дерево sysfs с датчиками нескольких типов
+var testSysfs = map[string]string{
+	// hwmon10 должен идти после hwmon2, хотя по строкам раньше
+	"class/hwmon/hwmon10/name":        "nvme\n",
+	"class/hwmon/hwmon10/temp1_input": "41850\n",
+	"class/hwmon/hwmon10/temp1_label": "Composite\n",
+
+	"class/hwmon/hwmon1/name":        "coretemp\n",
+	"class/hwmon/hwmon1/temp1_input": "52000\n",
+	"class/hwmon/hwmon1/temp1_label": "Package id 0\n",
+	"class/hwmon/hwmon1/temp1_max":   "80000\n",
+	"class/hwmon/hwmon1/temp1_crit":  "100000\n",
+	"class/hwmon/hwmon1/temp2_input": "50000\n",
+	"class/hwmon/hwmon1/temp2_label": "Core 0\n",
+	// Одинаковые метки внутри микросхемы различаются суффиксом
+	"class/hwmon/hwmon1/temp3_input": "49000\n",
+	"class/hwmon/hwmon1/temp3_label": "Core 0\n",
+	"class/hwmon/hwmon2/name":        "nvme\n",
+	"class/hwmon/hwmon2/temp1_input": "38850\n",
+	"class/hwmon/hwmon2/temp1_label": "Composite\n",
+	"class/hwmon/hwmon2/temp2_input": "40850\n",
+	"class/hwmon/hwmon2/temp2_label": "Sensor 1\n",
+
+	"class/hwmon/hwmon3/name":         "nct6775\n",
+	"class/hwmon/hwmon3/fan1_input":   "1200\n",
+	"class/hwmon/hwmon3/in0_input":    "1104\n",
+	"class/hwmon/hwmon3/in0_label":    "Vcore\n",
+	"class/hwmon/hwmon3/in1_input":    "3312\n",
+	"class/hwmon/hwmon3/temp7_input":  "\n", // Датчик не отвечает
+	"class/hwmon/hwmon3/curr1_input":  "500\n",
+	"class/hwmon/hwmon3/power1_input": "15000000\n",
+
+	// Старые ядра: файлы в подкаталоге device, имени нет
+	"class/hwmon/hwmon4/device/temp1_input": "45000\n",
+	// Точка в имени разделяла бы части имени метрики
+	"class/hwmon/hwmon5/name":        "acpi.tz\n",
+	"class/hwmon/hwmon5/temp1_input": "30000\n",
+
+	"class/thermal/thermal_zone0/type":              "x86_pkg_temp\n",
+	"class/thermal/thermal_zone0/temp":              "55000\n",
+	"class/thermal/thermal_zone0/trip_point_0_type": "passive\n",
+	"class/thermal/thermal_zone0/trip_point_0_temp": "90000\n",
+	"class/thermal/thermal_zone0/trip_point_1_type": "critical\n",
+	"class/thermal/thermal_zone0/trip_point_1_temp": "105000\n",
+	"class/thermal/thermal_zone1/type":              "acpitz\n",
+	"class/thermal/thermal_zone1/temp":              "27800\n",
+	"class/thermal/thermal_zone2/type":              "acpitz\n",
+	"class/thermal/thermal_zone2/temp":              "29800\n",
+	"class/thermal/thermal_zone10/type":             "pch_cannonlake\n",
+	"class/thermal/thermal_zone10/temp":             "invalid\n",
+	"class/thermal/cooling_device0/type":            "Processor\n",
+}
+
+func TestSensorsRead(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, testSysfs)
+
+	c, err := NewSensorsCollector(root)
+	if err != nil {
+		t.Fatalf("NewSensorsCollector: %v", err)
+	}
+
+	want := []models.SensorReading{
+		{Chip: "coretemp", Label: "package_id_0", Type: SensorTemperature, Value: 52, Max: 80, Crit: 100},
+		{Chip: "coretemp", Label: "core_0", Type: SensorTemperature, Value: 50},
+		{Chip: "coretemp", Label: "core_0_1", Type: SensorTemperature, Value: 49},
+		{Chip: "nvme", Label: "composite", Type: SensorTemperature, Value: 38.85},
+		{Chip: "nvme", Label: "sensor_1", Type: SensorTemperature, Value: 40.85},
+		{Chip: "nct6775", Label: "fan1", Type: SensorFan, Value: 1200},
+		{Chip: "nct6775", Label: "vcore", Type: SensorVoltage, Value: 1.104},
+		{Chip: "nct6775", Label: "in1", Type: SensorVoltage, Value: 3.312},
+		{Chip: "hwmon4", Label: "temp1", Type: SensorTemperature, Value: 45},
+		{Chip: "acpi_tz", Label: "temp1", Type: SensorTemperature, Value: 30},
+		{Chip: "nvme_1", Label: "composite", Type: SensorTemperature, Value: 41.85},
+		{Chip: "thermal", Label: "x86_pkg_temp", Type: SensorTemperature, Value: 55, Crit: 105},
+		{Chip: "thermal", Label: "acpitz", Type: SensorTemperature, Value: 27.8},
+		{Chip: "thermal", Label: "acpitz_1", Type: SensorTemperature, Value: 29.8},
+	}
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := metrics.Sensors
+	if len(got) != len(want) {
+		for _, r := range got {
+			t.Logf("%+v", r)
+		}
+		t.Fatalf("got %d readings, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("reading %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewSensorsCollectorWithoutSensors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":            "acpitz\n",
+		"class/thermal/cooling_device0/type": "Processor\n",
+	})
+	if _, err := NewSensorsCollector(root); err == nil {
+		t.Errorf("NewSensorsCollector succeeded without sensors")
+	}
+}
+
+func TestSortedDirs(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"hwmon10/name": "", "hwmon2/name": "", "hwmon1/name": "", "hwmon0/name": "", "other/name": "",
+	})
+	got := sortedDirs(root, "hwmon")
+	want := []string{"hwmon0", "hwmon1", "hwmon2", "hwmon10"}
+	if len(got) != len(want) {
+		t.Fatalf("sortedDirs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != root+"/"+want[i] {
+			t.Errorf("sortedDirs[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSanitizeSensorName(t *testing.T) {
+	tests := map[string]string{
+		"Package id 0":     "package_id_0",
+		"  Core 0  ":       "core_0",
+		"acpi.tz":          "acpi_tz",
+		"CPU Fan (RPM)":    "cpu_fan_rpm",
+		"+3.3V":            "3_3v",
+		"nct6775-isa-0290": "nct6775-isa-0290",
+		"Температура":      "",
+		"__a__b__":         "a_b",
+	}
+	for in, want := range tests {
+		if got := sanitizeSensorName(in); got != want {
+			t.Errorf("sanitizeSensorName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	seen := make(map[string]int)
+	for _, want := range []string{"nvme", "nvme_1", "nvme_2"} {
+		if got := uniqueName(seen, "nvme"); got != want {
+			t.Errorf("uniqueName = %q, want %q", got, want)
+		}
+	}
+	if got := uniqueName(seen, "coretemp"); got != "coretemp" {
+		t.Errorf("uniqueName = %q, want coretemp", got)
+	}
+}
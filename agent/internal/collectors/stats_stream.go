@@ -0,0 +1,55 @@
+package collectors
+
+import (
+	"context"
+	"sync"
+)
+
+// statsStream читает поток статистики одного контейнера
+// и хранит последний полученный снимок
+type statsStream struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu   sync.Mutex
+	last *RuntimeStats
+}
+
+func startStatsStream(streamer statsStreamer, id string) *statsStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &statsStream{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		_ = streamer.StreamStats(ctx, id, func(sample RuntimeStats) {
+			s.mu.Lock()
+			s.last = &sample
+			s.mu.Unlock()
+		})
+	}()
+	return s
+}
+
+// latest возвращает последний снимок или nil, если данных еще нет
+func (s *statsStream) latest() *RuntimeStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// finished сообщает, что поток закрыт и его нужно открыть заново
+func (s *statsStream) finished() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop закрывает поток; тело ответа закрывается при отмене контекста
+func (s *statsStream) stop() {
+	s.cancel()
+}
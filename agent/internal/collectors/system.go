@@ -2,60 +2,169 @@ package collectors
 
 import (
 	"agent/internal/models"
+	"fmt"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/mem"
+	"sync"
 	"time"
 )
 
+// systemConfigSchema - схема настроек коллектора system
+var systemConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"cpu": {"type": "boolean", "description": "Собирать загрузку CPU"},
+		"ram": {"type": "boolean", "description": "Собирать использование памяти"},
+		"disks": {"type": "boolean", "description": "Собирать заполненность дисков"},
+		"mountpoints": {
+			"type": "array",
+			"description": "Точки монтирования; первая попадает в system.disk",
+			"minItems": 1,
+			"items": {"type": "string", "pattern": "^/"}
+		}
+	}
+}`)
+
+// SystemConfig - настройки коллектора system
+type SystemConfig struct {
+	CPU         bool     `json:"cpu"`
+	RAM         bool     `json:"ram"`
+	Disks       bool     `json:"disks"`
+	Mountpoints []string `json:"mountpoints"`
+}
+
 // SystemCollector собирает метрики CPU, RAM и дисков
-type SystemCollector struct{}
+type SystemCollector struct {
+	mu          sync.Mutex
+	options     map[string]bool // cpu, ram, disks
+	mountpoints []string
+}
 
 func NewSystemCollector() *SystemCollector {
-	return &SystemCollector{}
+	return &SystemCollector{
+		options:     map[string]bool{"cpu": true, "ram": true, "disks": true},
+		mountpoints: []string{"/"},
+	}
 }
 
-func (c *SystemCollector) ChangeConfig(collType CollectorType, newconfig []string) {
-	if collType == System {
-	}
+func (c *SystemCollector) ConfigSchema() *Schema {
+	return systemConfigSchema
 }
 
-func (c *SystemCollector) Collect(metrics *models.AgentMetrics) error {
-	// Сбор CPU метрик
-	cpuPercent, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		return err
+func (c *SystemCollector) Config() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return SystemConfig{
+		CPU:         c.options["cpu"],
+		RAM:         c.options["ram"],
+		Disks:       c.options["disks"],
+		Mountpoints: append([]string{}, c.mountpoints...),
 	}
+}
 
-	// Сбор метрик памяти
-	memory, err := mem.VirtualMemory()
-	if err != nil {
+func (c *SystemCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(SystemConfig)
+	if err := decodeConfig(systemConfigSchema, raw, &cfg); err != nil {
 		return err
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.options = map[string]bool{"cpu": cfg.CPU, "ram": cfg.RAM, "disks": cfg.Disks}
+	c.mountpoints = cfg.Mountpoints
+	return nil
+}
 
-	// Сбор метрик диска
-	diskUsage, err := disk.Usage("/")
-	if err != nil {
-		return err
-	}
+// Options возвращает включенные части сбора
+func (c *SystemCollector) Options() map[string]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return copyOptions(c.options)
+}
 
-	metrics.System = models.SystemMetrics{
-		CPU: models.CPUMetrics{
+// SetOptions включает и отключает сбор CPU, RAM и дисков; неуказанные части не меняются
+func (c *SystemCollector) SetOptions(options map[string]bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return setOptions(c.options, options)
+}
+
+func (c *SystemCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	options := copyOptions(c.options)
+	mountpoints := c.mountpoints
+	c.mu.Unlock()
+
+	// Поля заполняются по отдельности: остальную часть System (PSI) дополняют другие коллекторы
+	if options["cpu"] {
+		cpuPercent, err := cpu.Percent(time.Second, false)
+		if err != nil {
+			return err
+		}
+		metrics.System.CPU = models.CPUMetrics{
 			UsagePercent: cpuPercent[0],
-		},
-		RAM: models.RAMMetrics{
+		}
+	}
+
+	if options["ram"] {
+		memory, err := mem.VirtualMemory()
+		if err != nil {
+			return err
+		}
+		metrics.System.RAM = models.RAMMetrics{
 			Total:        memory.Total,
 			Used:         memory.Used,
 			Free:         memory.Free,
 			UsagePercent: memory.UsedPercent,
-		},
-		Disk: models.DiskMetrics{
-			Total:        diskUsage.Total,
-			Used:         diskUsage.Used,
-			Free:         diskUsage.Free,
-			UsagePercent: diskUsage.UsedPercent,
-		},
+		}
+	}
+
+	if options["disks"] {
+		var usages []models.MountpointMetrics
+		for _, path := range mountpoints {
+			diskUsage, err := disk.Usage(path)
+			if err != nil {
+				return fmt.Errorf("disk usage of %s: %w", path, err)
+			}
+			usages = append(usages, models.MountpointMetrics{
+				Path:         path,
+				Total:        diskUsage.Total,
+				Used:         diskUsage.Used,
+				Free:         diskUsage.Free,
+				UsagePercent: diskUsage.UsedPercent,
+			})
+		}
+		metrics.System.Disk = models.DiskMetrics{
+			Total:        usages[0].Total,
+			Used:         usages[0].Used,
+			Free:         usages[0].Free,
+			UsagePercent: usages[0].UsagePercent,
+		}
+		if len(usages) > 1 {
+			metrics.System.Disk.Mountpoints = usages
+		}
 	}
 
 	return nil
 }
+
+func copyOptions(options map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(options))
+	for k, v := range options {
+		result[k] = v
+	}
+	return result
+}
+
+// setOptions переносит значения известных параметров; неизвестный параметр - ошибка
+func setOptions(dst, src map[string]bool) error {
+	for name := range src {
+		if _, ok := dst[name]; !ok {
+			return fmt.Errorf("unknown option %q", name)
+		}
+	}
+	for name, v := range src {
+		dst[name] = v
+	}
+	return nil
+}
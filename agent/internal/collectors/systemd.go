@@ -0,0 +1,236 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// systemctlTimeout ограничивает время одного вызова systemctl show
+const systemctlTimeout = 10 * time.Second
+
+// systemdProperties - свойства юнитов, запрашиваемые у systemctl show
+var systemdProperties = []string{
+	"Id",
+	"Description",
+	"LoadState",
+	"ActiveState",
+	"SubState",
+	"Result",
+	"NRestarts",
+	"MainPID",
+	"MemoryCurrent",
+	"CPUUsageNSec",
+	"TasksCurrent",
+	"ActiveEnterTimestamp",
+}
+
+// SystemdCollector собирает состояние systemd-юнитов разбором вывода systemctl show.
+// Юниты задаются именами (nginx.service) или шаблонами (php*-fpm.service).
+type SystemdCollector struct {
+	mu      sync.Mutex
+	units   []string
+	prevCPU map[string]systemdCPUSample // учет CPU с предыдущего сбора по имени юнита
+}
+
+// systemdCPUSample - накопленное время CPU юнита на момент сбора
+type systemdCPUSample struct {
+	usageNSec uint64
+	at        time.Time
+}
+
+func NewSystemdCollector(units []string) *SystemdCollector {
+	return &SystemdCollector{
+		units:   units,
+		prevCPU: make(map[string]systemdCPUSample),
+	}
+}
+
+// systemdConfigSchema - схема настроек коллектора systemd
+var systemdConfigSchema = mustSchema(`{
+	"type": "object",
+	"properties": {
+		"units": {
+			"type": "array",
+			"description": "Имена юнитов (nginx.service) или шаблоны (php*-fpm.service)",
+			"items": {"type": "string", "minLength": 1}
+		}
+	}
+}`)
+
+// SystemdConfig - настройки коллектора systemd
+type SystemdConfig struct {
+	Units []string `json:"units"`
+}
+
+func (c *SystemdCollector) ConfigSchema() *Schema {
+	return systemdConfigSchema
+}
+
+func (c *SystemdCollector) Config() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return SystemdConfig{Units: append([]string{}, c.units...)}
+}
+
+func (c *SystemdCollector) SetConfig(raw []byte) error {
+	cfg := c.Config().(SystemdConfig)
+	if err := decodeConfig(systemdConfigSchema, raw, &cfg); err != nil {
+		return err
+	}
+	if err := ValidateSystemdUnits(cfg.Units); err != nil {
+		return err
+	}
+	c.SetUnits(cfg.Units)
+	return nil
+}
+
+// SetUnits заменяет список отслеживаемых юнитов
+func (c *SystemdCollector) SetUnits(units []string) {
+	c.mu.Lock()
+	c.units = units
+	c.mu.Unlock()
+}
+
+func (c *SystemdCollector) Collect(metrics *models.AgentMetrics) error {
+	c.mu.Lock()
+	units := c.units
+	c.mu.Unlock()
+	if len(units) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemctlTimeout)
+	defer cancel()
+
+	args := []string{"show", "--no-pager", "--property=" + strings.Join(systemdProperties, ",")}
+	args = append(args, "--")
+	args = append(args, units...)
+	cmd := exec.CommandContext(ctx, "systemctl", args...)
+	// Время активации разбираем в UTC, чтобы не зависеть от часового пояса хоста
+	cmd.Env = append(os.Environ(), "TZ=UTC", "LC_ALL=C")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("systemctl show failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	now := time.Now()
+	var infos []models.SystemdUnitInfo
+	seen := make(map[string]bool)
+	for _, props := range parseSystemctlShow(out) {
+		id := props["Id"]
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		infos = append(infos, c.unitInfo(props, now))
+	}
+
+	// Забываем учет CPU юнитов, которые больше не попадают под шаблоны
+	c.mu.Lock()
+	for id := range c.prevCPU {
+		if !seen[id] {
+			delete(c.prevCPU, id)
+		}
+	}
+	c.mu.Unlock()
+
+	metrics.SystemdUnits = infos
+	return nil
+}
+
+// unitInfo переводит свойства юнита в метрики и считает загрузку CPU за интервал
+func (c *SystemdCollector) unitInfo(props map[string]string, now time.Time) models.SystemdUnitInfo {
+	info := models.SystemdUnitInfo{
+		Name:         props["Id"],
+		Description:  props["Description"],
+		LoadState:    props["LoadState"],
+		ActiveState:  props["ActiveState"],
+		SubState:     props["SubState"],
+		Result:       props["Result"],
+		NRestarts:    systemdUint(props["NRestarts"]),
+		MainPID:      int(systemdUint(props["MainPID"])),
+		MemoryBytes:  systemdUint(props["MemoryCurrent"]),
+		TasksCurrent: systemdUint(props["TasksCurrent"]),
+	}
+
+	usage := systemdUint(props["CPUUsageNSec"])
+	info.CPUUsageSeconds = float64(usage) / 1e9
+
+	if info.ActiveState == "active" {
+		if ts, err := time.Parse("Mon 2006-01-02 15:04:05 MST", props["ActiveEnterTimestamp"]); err == nil {
+			info.ActiveSince = ts
+			info.UptimeSeconds = now.Sub(ts).Seconds()
+		}
+	}
+
+	c.mu.Lock()
+	prev, ok := c.prevCPU[info.Name]
+	c.prevCPU[info.Name] = systemdCPUSample{usageNSec: usage, at: now}
+	c.mu.Unlock()
+
+	if ok && usage >= prev.usageNSec {
+		if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+			info.CPUPercent = float64(usage-prev.usageNSec) / 1e9 / elapsed * 100
+		}
+	}
+	return info
+}
+
+// ValidateSystemdUnits проверяет имена и шаблоны юнитов перед передачей в systemctl
+func ValidateSystemdUnits(units []string) error {
+	for _, unit := range units {
+		if unit == "" || strings.HasPrefix(unit, "-") || strings.ContainsAny(unit, "/ \t\n") {
+			return fmt.Errorf("invalid unit name %q", unit)
+		}
+		if _, err := path.Match(unit, ""); err != nil {
+			return fmt.Errorf("invalid unit pattern %q: %w", unit, err)
+		}
+	}
+	return nil
+}
+
+// parseSystemctlShow разбирает вывод systemctl show: блоки key=value, разделенные пустой строкой
+func parseSystemctlShow(out []byte) []map[string]string {
+	var units []map[string]string
+	current := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			if len(current) > 0 {
+				units = append(units, current)
+				current = make(map[string]string)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		current[key] = value
+	}
+	if len(current) > 0 {
+		units = append(units, current)
+	}
+	return units
+}
+
+// systemdUint разбирает числовое свойство. Неустановленные значения systemd выводит
+// как "[not set]" или максимальное uint64 - в обоих случаях возвращается 0.
+func systemdUint(value string) uint64 {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n == ^uint64(0) {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,218 @@
+package collectors
+
+import (
+	"agent/internal/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// systemctlShowOutput - вывод systemctl show --property=... для nginx.service, php*-fpm.service, redis.service и
+// отсутствующего юнита. Шаблон php*-fpm.service развернут systemd в два юнита; php8.2-fpm.service указан
+// и отдельно, поэтому выводится дважды
+const systemctlShowOutput = `Id=nginx.service
+Description=A high performance web server and a reverse proxy server
+LoadState=loaded
+ActiveState=active
+SubState=running
+Result=success
+NRestarts=2
+MainPID=1123
+MemoryCurrent=8531968
+CPUUsageNSec=1520000000
+TasksCurrent=5
+ActiveEnterTimestamp=Fri 2024-03-01 08:00:00 UTC
+
+Id=php8.1-fpm.service
+Description=The PHP 8.1 FastCGI Process Manager
+LoadState=loaded
+ActiveState=failed
+SubState=failed
+Result=exit-code
+NRestarts=0
+MainPID=0
+MemoryCurrent=[not set]
+CPUUsageNSec=[not set]
+TasksCurrent=18446744073709551615
+ActiveEnterTimestamp=Thu 2024-02-29 22:10:03 UTC
+
+Id=php8.2-fpm.service
+Description=The PHP 8.2 FastCGI Process Manager
+LoadState=loaded
+ActiveState=active
+SubState=running
+Result=success
+NRestarts=0
+MainPID=2201
+MemoryCurrent=31457280
+CPUUsageNSec=500000000
+TasksCurrent=3
+ActiveEnterTimestamp=Fri 2024-03-01 09:30:00 UTC
+
+Id=php8.2-fpm.service
+Description=The PHP 8.2 FastCGI Process Manager
+LoadState=loaded
+ActiveState=active
+SubState=running
+Result=success
+NRestarts=0
+MainPID=2201
+MemoryCurrent=31457280
+CPUUsageNSec=500000000
+TasksCurrent=3
+ActiveEnterTimestamp=Fri 2024-03-01 09:30:00 UTC
+
+Id=redis.service
+Description=redis.service
+LoadState=not-found
+ActiveState=inactive
+SubState=dead
+Result=success
+NRestarts=0
+MainPID=0
+MemoryCurrent=[not set]
+CPUUsageNSec=[not set]
+TasksCurrent=[not set]
+ActiveEnterTimestamp=
+`
+
+func TestParseSystemctlShow(t *testing.T) {
+	units := parseSystemctlShow([]byte(strings.ReplaceAll(systemctlShowOutput, "\n", "\r\n")))
+	if len(units) != 5 {
+		t.Fatalf("parsed %d units, want 5", len(units))
+	}
+	if units[0]["Id"] != "nginx.service" || units[0]["ActiveEnterTimestamp"] != "Fri 2024-03-01 08:00:00 UTC" {
+		t.Errorf("first unit = %v", units[0])
+	}
+	if v, ok := units[4]["ActiveEnterTimestamp"]; !ok || v != "" {
+		t.Errorf("empty property = %q, %v", v, ok)
+	}
+
+	// Несколько пустых строк подряд и строки без "=" не дают лишних юнитов
+	units = parseSystemctlShow([]byte("\n\nId=a.service\nnoise\n\n\n\nId=b.service"))
+	if len(units) != 2 || units[0]["Id"] != "a.service" || units[1]["Id"] != "b.service" || len(units[0]) != 1 {
+		t.Errorf("units = %v", units)
+	}
+}
+
+func TestSystemdUnitInfo(t *testing.T) {
+	c := NewSystemdCollector(nil)
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	units := parseSystemctlShow([]byte(systemctlShowOutput))
+
+	got := c.unitInfo(units[0], now)
+	want := models.SystemdUnitInfo{
+		Name:            "nginx.service",
+		Description:     "A high performance web server and a reverse proxy server",
+		LoadState:       "loaded",
+		ActiveState:     "active",
+		SubState:        "running",
+		Result:          "success",
+		NRestarts:       2,
+		MainPID:         1123,
+		MemoryBytes:     8531968,
+		CPUUsageSeconds: 1.52,
+		TasksCurrent:    5,
+		ActiveSince:     time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		UptimeSeconds:   7200,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unit info:\n got %+v\nwant %+v", got, want)
+	}
+
+	// Неустановленные значения дают нули, время активации неактивного юнита не учитывается
+	failed := c.unitInfo(units[1], now)
+	if failed.MemoryBytes != 0 || failed.TasksCurrent != 0 || failed.CPUUsageSeconds != 0 ||
+		!failed.ActiveSince.IsZero() || failed.UptimeSeconds != 0 || failed.Result != "exit-code" {
+		t.Errorf("failed unit info = %+v", failed)
+	}
+
+	// Загрузка CPU считается по приросту CPUUsageNSec между сборами
+	units[0]["CPUUsageNSec"] = "4520000000"
+	if got := c.unitInfo(units[0], now.Add(10*time.Second)); got.CPUPercent != 30 {
+		t.Errorf("cpu percent = %v, want 30", got.CPUPercent)
+	}
+	// После перезапуска счетчик уменьшается, загрузка за интервал не считается
+	units[0]["CPUUsageNSec"] = "100000000"
+	if got := c.unitInfo(units[0], now.Add(20*time.Second)); got.CPUPercent != 0 {
+		t.Errorf("cpu percent after restart = %v, want 0", got.CPUPercent)
+	}
+}
+
+func TestValidateSystemdUnits(t *testing.T) {
+	if err := ValidateSystemdUnits([]string{"nginx.service", "php*-fpm.service", "getty@tty1.service", "docker-[0-9]*.scope"}); err != nil {
+		t.Errorf("valid units rejected: %v", err)
+	}
+	for _, unit := range []string{"", "--all", "../etc/passwd", "nginx service", "php[-fpm.service"} {
+		if err := ValidateSystemdUnits([]string{unit}); err == nil {
+			t.Errorf("unit %q accepted", unit)
+		}
+	}
+}
+
+// fakeSystemctl подменяет systemctl в PATH скриптом, который записывает аргументы в файл и выводит output
+func fakeSystemctl(t *testing.T, output string) (argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	writeTestFiles(t, dir, map[string]string{"output": output})
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done > " + argsFile + "\ncat " + filepath.Join(dir, "output") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "systemctl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestSystemdCollectorCollect(t *testing.T) {
+	argsFile := fakeSystemctl(t, systemctlShowOutput)
+	c := NewSystemdCollector([]string{"nginx.service", "php*-fpm.service", "php8.2-fpm.service", "redis.service"})
+
+	var metrics models.AgentMetrics
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	// Шаблоны передаются systemctl без изменений: их разворачивает systemd
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Split(strings.TrimSpace(string(data)), "\n")
+	wantArgs := []string{"show", "--no-pager", "--property=" + strings.Join(systemdProperties, ","), "--",
+		"nginx.service", "php*-fpm.service", "php8.2-fpm.service", "redis.service"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("systemctl args = %q, want %q", args, wantArgs)
+	}
+
+	// Юнит, попавший под шаблон и указанный по имени, учитывается один раз
+	var names []string
+	for _, u := range metrics.SystemdUnits {
+		names = append(names, u.Name+":"+u.ActiveState)
+	}
+	wantNames := []string{"nginx.service:active", "php8.1-fpm.service:failed", "php8.2-fpm.service:active", "redis.service:inactive"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("units = %v, want %v", names, wantNames)
+	}
+
+	// Учет CPU юнитов, которые больше не попадают под шаблоны, забывается
+	fakeSystemctl(t, "Id=nginx.service\nActiveState=active\nCPUUsageNSec=1\n")
+	if err := c.Collect(&metrics); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(c.prevCPU) != 1 {
+		t.Errorf("cpu samples = %v, want only nginx.service", c.prevCPU)
+	}
+}
+
+func TestSystemdCollectorWithoutUnits(t *testing.T) {
+	// Без юнитов systemctl не вызывается
+	t.Setenv("PATH", t.TempDir())
+	var metrics models.AgentMetrics
+	if err := NewSystemdCollector(nil).Collect(&metrics); err != nil || metrics.SystemdUnits != nil {
+		t.Errorf("Collect without units = %v, %+v", err, metrics.SystemdUnits)
+	}
+}
@@ -0,0 +1,106 @@
+package collectors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// utmpUserProcess - тип записи о сеансе пользователя (USER_PROCESS)
+	utmpUserProcess = 7
+	// utmpRecordSize - размер записи struct utmp в glibc (x86_64, arm64)
+	utmpRecordSize = 384
+)
+
+// utmpRecord повторяет struct utmp из glibc; пустые поля при чтении пропускаются
+type utmpRecord struct {
+	Type    int16
+	_       [2]byte
+	PID     int32
+	Line    [32]byte
+	ID      [4]byte
+	User    [32]byte
+	Host    [256]byte
+	_       [2]int16 // ut_exit
+	Session int32
+	Sec     int32
+	Usec    int32
+	Addr    [4]int32
+	_       [20]byte
+}
+
+// utmpEntry - запись о сеансе пользователя
+type utmpEntry struct {
+	User  string
+	TTY   string
+	Host  string
+	PID   int
+	Time  time.Time
+	Login bool // Запись USER_PROCESS; остальные (выход, загрузка) только сдвигают позицию
+}
+
+// readUtmp читает записи utmp/wtmp начиная с offset и возвращает их вместе с позицией
+// после последней целой записи; незаписанный хвост дочитывается в следующий раз
+func readUtmp(path string, offset int64) ([]utmpEntry, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxLogBytesPerCollect))
+	if err != nil {
+		return nil, offset, err
+	}
+
+	var entries []utmpEntry
+	for len(data) >= utmpRecordSize {
+		var rec utmpRecord
+		if err := binary.Read(bytes.NewReader(data[:utmpRecordSize]), binary.LittleEndian, &rec); err != nil {
+			return entries, offset, err
+		}
+		data = data[utmpRecordSize:]
+		offset += utmpRecordSize
+
+		e := utmpEntry{
+			User:  cString(rec.User[:]),
+			TTY:   cString(rec.Line[:]),
+			Host:  cString(rec.Host[:]),
+			PID:   int(rec.PID),
+			Time:  time.Unix(int64(rec.Sec), int64(rec.Usec)*1000),
+			Login: rec.Type == utmpUserProcess,
+		}
+		if e.Host == "" && rec.Addr != [4]int32{} {
+			e.Host = utmpAddr(rec.Addr)
+		}
+		entries = append(entries, e)
+	}
+	return entries, offset, nil
+}
+
+// utmpAddr преобразует ut_addr_v6: IPv4 занимает первое слово, IPv6 - все четыре
+func utmpAddr(addr [4]int32) string {
+	buf := make([]byte, 16)
+	for i, w := range addr {
+		binary.LittleEndian.PutUint32(buf[i*4:], uint32(w))
+	}
+	if addr[1] == 0 && addr[2] == 0 && addr[3] == 0 {
+		return net.IP(buf[:4]).String()
+	}
+	return net.IP(buf).String()
+}
+
+// cString возвращает строку до первого нулевого байта
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
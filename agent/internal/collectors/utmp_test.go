@@ -0,0 +1,91 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testUtmpRecord - поля записи utmp для utmpBytes
+type testUtmpRecord struct {
+	typ              int16
+	pid              int32
+	line, user, host string
+	sec, usec        int32
+	addr             [4]uint32
+}
+
+// utmpBytes собирает запись struct utmp по смещениям полей glibc, не используя utmpRecord
+func utmpBytes(r testUtmpRecord) []byte {
+	b := make([]byte, utmpRecordSize)
+	binary.LittleEndian.PutUint16(b[0:], uint16(r.typ))
+	binary.LittleEndian.PutUint32(b[4:], uint32(r.pid))
+	copy(b[8:40], r.line)
+	copy(b[40:44], "ts/0")
+	copy(b[44:76], r.user)
+	copy(b[76:332], r.host)
+	binary.LittleEndian.PutUint32(b[340:], uint32(r.sec))
+	binary.LittleEndian.PutUint32(b[344:], uint32(r.usec))
+	for i, w := range r.addr {
+		binary.LittleEndian.PutUint32(b[348+i*4:], w)
+	}
+	return b
+}
+
+func TestReadUtmp(t *testing.T) {
+	var data []byte
+	for _, r := range []testUtmpRecord{
+		// BOOT_TIME
+		{typ: 2, line: "~", user: "reboot", host: "6.8.0-45-generic", sec: 1709280000},
+		{typ: utmpUserProcess, pid: 4102, line: "pts/0", user: "deploy", host: "10.0.0.15", sec: 1709283600, usec: 250000,
+			addr: [4]uint32{0x0f00000a}},
+		// Хост не записан, адрес IPv4 берется из ut_addr_v6
+		{typ: utmpUserProcess, pid: 4230, line: "pts/1", user: "admin", sec: 1709283700, addr: [4]uint32{0x0501a8c0}},
+		{typ: utmpUserProcess, pid: 4301, line: "pts/2", user: "ops", sec: 1709283800,
+			addr: [4]uint32{0xb80d0120, 0, 0, 0x01000000}},
+		// DEAD_PROCESS - выход пользователя
+		{typ: 8, pid: 4102, line: "pts/0", sec: 1709287200},
+	} {
+		data = append(data, utmpBytes(r)...)
+	}
+	// Недописанная запись в конце файла
+	data = append(data, make([]byte, 100)...)
+
+	path := filepath.Join(t.TempDir(), "wtmp")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, offset, err := readUtmp(path, 0)
+	if err != nil {
+		t.Fatalf("readUtmp: %v", err)
+	}
+	want := []utmpEntry{
+		{User: "reboot", TTY: "~", Host: "6.8.0-45-generic", Time: time.Unix(1709280000, 0)},
+		{User: "deploy", TTY: "pts/0", Host: "10.0.0.15", PID: 4102, Time: time.Unix(1709283600, 250000000), Login: true},
+		{User: "admin", TTY: "pts/1", Host: "192.168.1.5", PID: 4230, Time: time.Unix(1709283700, 0), Login: true},
+		{User: "ops", TTY: "pts/2", Host: "2001:db8::1", PID: 4301, Time: time.Unix(1709283800, 0), Login: true},
+		{TTY: "pts/0", PID: 4102, Time: time.Unix(1709287200, 0)},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries:\n got %+v\nwant %+v", entries, want)
+	}
+	if offset != 5*utmpRecordSize {
+		t.Errorf("offset = %d, want %d", offset, 5*utmpRecordSize)
+	}
+
+	// Чтение продолжается с сохраненной позиции
+	entries, next, err := readUtmp(path, 3*utmpRecordSize)
+	if err != nil || len(entries) != 2 || entries[0].User != "ops" || next != offset {
+		t.Errorf("readUtmp from offset = %+v, %d, %v", entries, next, err)
+	}
+}
+
+func TestUtmpRecordSize(t *testing.T) {
+	if size := binary.Size(utmpRecord{}); size != utmpRecordSize {
+		t.Errorf("utmpRecord size = %d, want %d", size, utmpRecordSize)
+	}
+}
@@ -0,0 +1,31 @@
+package collectors
+
+import "time"
+
+// eventWindow считает события в скользящем временном окне
+type eventWindow struct {
+	window time.Duration
+	times  []time.Time
+}
+
+func newEventWindow(window time.Duration) *eventWindow {
+	return &eventWindow{window: window}
+}
+
+// add регистрирует событие
+func (w *eventWindow) add(t time.Time) {
+	w.times = append(w.times, t)
+}
+
+// count возвращает количество событий за окно, забывая устаревшие
+func (w *eventWindow) count(now time.Time) int {
+	threshold := now.Add(-w.window)
+	start := 0
+	for start < len(w.times) && !w.times[start].After(threshold) {
+		start++
+	}
+	if start > 0 {
+		w.times = append([]time.Time(nil), w.times[start:]...)
+	}
+	return len(w.times)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"agent/internal/models"
 	"os"
 	"strconv"
 	"time"
@@ -14,7 +15,91 @@ type AgentConfig struct {
 	PollInterval  time.Duration `yaml:"poll_interval"`
 	Port          string        `yaml:"port"`
 	Processes     []string      `yaml:"processes"`
-	Containers    []string      `yaml:"containers"`
+	// ProcessMatchers дополняет Processes правилами отбора по regex, командной строке, пользователю и cgroup
+	ProcessMatchers []models.ProcessMatcher `yaml:"process_matchers"`
+	Containers      []string                `yaml:"containers"`
+	// ContainerMatchers дополняет Containers правилами отбора по меткам, образу и regex имени
+	ContainerMatchers []models.ContainerMatcher `yaml:"container_matchers"`
+	ContainerRuntime  ContainerRuntimeConfig    `yaml:"container_runtime"`
+	Cgroups           CgroupConfig              `yaml:"cgroups"`
+	Sensors           SensorsConfig             `yaml:"sensors"`
+	KernelLog         KernelLogConfig           `yaml:"kernel_log"`
+	Auth              AuthConfig                `yaml:"auth"`
+	// SystemdUnits - отслеживаемые systemd-юниты: имена или шаблоны (php*-fpm.service)
+	SystemdUnits []string `yaml:"systemd_units"`
+	// StateDir - каталог для состояния агента между перезапусками (позиции чтения журналов, хеши файлов,
+	// конфигурация, полученная через API)
+	StateDir      string              `yaml:"state_dir"`
+	Logs          []models.LogSource  `yaml:"logs"`
+	Plugins       PluginsConfig       `yaml:"plugins"`
+	FileIntegrity FileIntegrityConfig `yaml:"file_integrity"`
+	// Probes - проверки доступности локальных сервисов (HTTP, TCP, Unix-сокет)
+	Probes []models.Probe `yaml:"probes"`
+	// Collectors - включение, интервал и параметры коллекторов по имени (system, inventory, ...)
+	Collectors map[string]CollectorConfig `yaml:"collectors"`
+}
+
+// CollectorConfig задает включение, интервал и параметры коллектора
+type CollectorConfig struct {
+	Enabled  *bool           `yaml:"enabled"`  // По умолчанию включен
+	Interval time.Duration   `yaml:"interval"` // По умолчанию poll_interval
+	Timeout  time.Duration   `yaml:"timeout"`  // Срок одного запуска; по умолчанию 10s
+	Options  map[string]bool `yaml:"options"`  // cpu, ram, disks у system; tcp, udp у network
+
+	// Config - типизированные настройки коллектора, как в PUT /config/collectors/:name
+	Config map[string]interface{} `yaml:"config"`
+}
+
+// CgroupConfig задает отслеживаемые cgroup v2 (systemd-слайсы, сервисы, контейнеры)
+type CgroupConfig struct {
+	Root    string                `yaml:"root"`    // Точка монтирования cgroup v2, по умолчанию /sys/fs/cgroup
+	Targets []models.CgroupTarget `yaml:"targets"` // Пути относительно root, допускаются шаблоны
+}
+
+// SensorsConfig задает источник показаний аппаратных датчиков
+type SensorsConfig struct {
+	Root string `yaml:"root"` // Точка монтирования sysfs, по умолчанию /sys
+}
+
+// KernelLogConfig задает чтение журнала ядра (OOM killer, segfault, ошибки файловых систем)
+type KernelLogConfig struct {
+	Disabled bool   `yaml:"disabled"`
+	Path     string `yaml:"path"` // По умолчанию /dev/kmsg
+}
+
+// AuthConfig задает источники сведений о сеансах пользователей и входах по SSH
+type AuthConfig struct {
+	Disabled bool   `yaml:"disabled"`
+	Log      string `yaml:"log"`  // Журнал аутентификации; по умолчанию /var/log/auth.log или /var/log/secure, без них - journald
+	Utmp     string `yaml:"utmp"` // По умолчанию /var/run/utmp
+	Wtmp     string `yaml:"wtmp"` // По умолчанию /var/log/wtmp
+}
+
+// PluginsConfig задает внешние команды и ограничения для них
+type PluginsConfig struct {
+	// User - пользователь для запуска плагинов; если агент работает от root, по умолчанию nobody
+	User           string              `yaml:"user"`
+	MaxOutputBytes int                 `yaml:"max_output_bytes"` // Ограничение вывода одного запуска, по умолчанию 64 КБ
+	Commands       []models.ExecPlugin `yaml:"commands"`
+}
+
+// FileIntegrityConfig задает файлы, изменения которых отслеживаются по хешам
+type FileIntegrityConfig struct {
+	Interval    time.Duration `yaml:"interval"`      // Период проверки, по умолчанию 5m
+	Inotify     bool          `yaml:"inotify"`       // Дополнительно проверять сразу после изменения (Linux)
+	MaxFileSize int64         `yaml:"max_file_size"` // Файлы больше не хешируются, по умолчанию 64 МБ
+	Paths       []string      `yaml:"paths"`         // Пути, шаблоны (/etc/sudoers.d/*) или каталоги
+}
+
+// ContainerRuntimeConfig задает среду выполнения контейнеров и параметры сбора статистики
+type ContainerRuntimeConfig struct {
+	// Runtime - docker, podman, containerd или cri-o; auto (по умолчанию) определяет среду по сокетам
+	Runtime        string        `yaml:"runtime"`
+	Endpoint       string        `yaml:"endpoint"`        // Адрес сокета, например unix:///run/podman/podman.sock
+	MaxConcurrency int           `yaml:"max_concurrency"` // Число одновременных запросов к среде выполнения
+	StatsTimeout   time.Duration `yaml:"stats_timeout"`   // Таймаут одного запроса статистики
+	// Streaming держит по одному потоку статистики на контейнер и отдает последний снимок
+	Streaming bool `yaml:"streaming"`
 }
 
 func LoadAgentConfig(path string) (*AgentConfig, error) {
@@ -55,6 +140,21 @@ func LoadAgentConfig(path string) (*AgentConfig, error) {
 	if cfg.Port == "" {
 		cfg.Port = "8081"
 	}
+	if cfg.ContainerRuntime.Runtime == "" {
+		cfg.ContainerRuntime.Runtime = "auto"
+	}
+	if cfg.ContainerRuntime.MaxConcurrency <= 0 {
+		cfg.ContainerRuntime.MaxConcurrency = 8
+	}
+	if cfg.ContainerRuntime.StatsTimeout == 0 {
+		cfg.ContainerRuntime.StatsTimeout = 5 * time.Second
+	}
+	if cfg.StateDir == "" {
+		cfg.StateDir = "/var/lib/agent"
+	}
+	if cfg.Cgroups.Root == "" {
+		cfg.Cgroups.Root = "/sys/fs/cgroup"
+	}
 
 	return &cfg, nil
 }
@@ -4,12 +4,44 @@ import "time"
 
 // AgentMetrics - корневая структура всех метрик, собираемых агентом
 type AgentMetrics struct {
-	HostID     int             `json:"host_id"`
-	Timestamp  time.Time       `json:"timestamp"`
-	System     SystemMetrics   `json:"system,omitempty"`
-	Processes  []ProcessInfo   `json:"processes,omitempty"`
-	Ports      []PortInfo      `json:"ports,omitempty"`
-	Containers []ContainerInfo `json:"containers,omitempty"`
+	HostID        int                `json:"host_id"`
+	Timestamp     time.Time          `json:"timestamp"`
+	System        SystemMetrics      `json:"system,omitempty"`
+	Processes     []ProcessInfo      `json:"processes,omitempty"`
+	ProcessGroups []ProcessGroupInfo `json:"process_groups,omitempty"` // Суммы по имени процесса (например, все воркеры nginx)
+	Ports         []PortInfo         `json:"ports,omitempty"`
+	Containers    []ContainerInfo    `json:"containers,omitempty"`
+	Cgroups       []CgroupInfo       `json:"cgroups,omitempty"`
+	SystemdUnits  []SystemdUnitInfo  `json:"systemd_units,omitempty"`
+	Logs          []LogFileInfo      `json:"logs,omitempty"`
+	Custom        []CustomMetric     `json:"custom,omitempty"`    // Показатели внешних плагинов
+	Probes        []ProbeResult      `json:"probes,omitempty"`    // Результаты проверок доступности локальных сервисов
+	Inventory     *HostInventory     `json:"inventory,omitempty"` // Сведения о хосте: ОС, ядро, оборудование
+	Packages      *PackageInventory  `json:"packages,omitempty"`  // Установленные пакеты: полный список в первом отчете, затем изменения
+	Sensors       []SensorReading    `json:"sensors,omitempty"`   // Температуры, обороты вентиляторов и напряжения
+	Auth          *AuthMetrics       `json:"auth,omitempty"`      // Сеансы пользователей и входы по SSH за интервал
+	Events        []Event            `json:"events,omitempty"`    // События за последнее время (перезапуски процессов и т.п.)
+
+	// Результат последнего запуска каждого включенного коллектора
+	CollectorStatus map[string]CollectorRunStatus `json:"collector_status,omitempty"`
+	// Хеш конфигурации, полученной через API (GET /config/version); по нему ЦМ обнаруживает устаревшую конфигурацию
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// Результаты запуска коллектора
+const (
+	CollectorOK      = "ok"
+	CollectorTimeout = "timeout"
+	CollectorError   = "error"
+)
+
+// CollectorRunStatus - результат запуска коллектора: ok, timeout (секция метрик отсутствует)
+// или error (секция может быть заполнена частично)
+type CollectorRunStatus struct {
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	Timestamp  time.Time `json:"timestamp"` // Время запуска; у редких коллекторов отстает от времени снимка
 }
 
 // NewAgentMetrics создает новую структуру метрик с заполненным ID хоста и временной меткой
@@ -20,11 +52,79 @@ func NewAgentMetrics(hostID int) AgentMetrics {
 	}
 }
 
+// Event описывает событие, зафиксированное агентом между сборами метрик
+type Event struct {
+	ID         string            `json:"id"`                   // Уникальный идентификатор события в пределах агента
+	Timestamp  time.Time         `json:"timestamp"`            // Время события
+	Source     string            `json:"source"`               // Источник (process, container, ...)
+	Type       string            `json:"type"`                 // Тип события (restarted, ...)
+	Object     string            `json:"object"`               // Объект события (псевдоним процесса, имя контейнера, ...)
+	Message    string            `json:"message,omitempty"`    // Описание события
+	Attributes map[string]string `json:"attributes,omitempty"` // Дополнительные сведения (PID, код выхода, ...)
+}
+
+// CollectorSettings - включение, интервал и параметры коллектора (POST /config/collectors)
+type CollectorSettings struct {
+	Name     string          `json:"name"`
+	Enabled  *bool           `json:"enabled,omitempty"`          // Не задано - не меняется
+	Interval int64           `json:"interval_seconds,omitempty"` // 0 - не меняется
+	Timeout  int64           `json:"timeout_seconds,omitempty"`  // Срок одного запуска; 0 - не меняется
+	Options  map[string]bool `json:"options,omitempty"`          // Части сбора: cpu, ram, disks у system; tcp, udp у network
+}
+
+// ConfigVersion - версия конфигурации, полученной через API (GET /config/version).
+// Version растет при каждом изменении, Hash зависит только от содержимого
+type ConfigVersion struct {
+	Version   int64      `json:"version"`
+	Hash      string     `json:"hash"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // Не задано - конфигурация через API не менялась
+}
+
+// CollectorStatus - состояние коллектора (GET /collectors)
+type CollectorStatus struct {
+	Name            string          `json:"name"`
+	Available       bool            `json:"available"` // Коллектор создан; иначе причина в last_error
+	Enabled         bool            `json:"enabled"`
+	IntervalSeconds float64         `json:"interval_seconds"`
+	TimeoutSeconds  float64         `json:"timeout_seconds"`
+	Watching        bool            `json:"watching,omitempty"` // Отслеживает события между запусками
+	Options         map[string]bool `json:"options,omitempty"`
+	LastRun         *time.Time      `json:"last_run,omitempty"`
+	LastDurationMs  float64         `json:"last_duration_ms"`
+	LastStatus      string          `json:"last_status,omitempty"` // ok, timeout или error
+	LastError       string          `json:"last_error,omitempty"`
+	Running         bool            `json:"running,omitempty"` // Запуск не завершился к сроку и еще выполняется
+	Runs            uint64          `json:"runs"`
+}
+
 // SystemMetrics содержит информацию о системных ресурсах
 type SystemMetrics struct {
 	CPU  CPUMetrics  `json:"cpu"`
 	RAM  RAMMetrics  `json:"ram"`
 	Disk DiskMetrics `json:"disk"`
+	PSI  *PSIMetrics `json:"psi,omitempty"` // Отсутствует, если ядро не поддерживает PSI
+}
+
+// PSIMetrics содержит информацию о задержках из-за нехватки ресурсов (pressure stall information)
+type PSIMetrics struct {
+	CPU    PSIResource `json:"cpu"`
+	Memory PSIResource `json:"memory"`
+	IO     PSIResource `json:"io"`
+}
+
+// PSIResource содержит строки some и full файла pressure.
+// some - доля времени, когда хотя бы одна задача ждала ресурс, full - когда ждали все задачи.
+type PSIResource struct {
+	Some PSIStats `json:"some"`
+	Full PSIStats `json:"full"`
+}
+
+// PSIStats содержит скользящие средние и суммарное время простоя
+type PSIStats struct {
+	Avg10  float64 `json:"avg10"`  // Процент времени простоя за 10 секунд
+	Avg60  float64 `json:"avg60"`  // Процент времени простоя за 60 секунд
+	Avg300 float64 `json:"avg300"` // Процент времени простоя за 300 секунд
+	Total  uint64  `json:"total"`  // Суммарное время простоя в микросекундах
 }
 
 // CPUMetrics содержит информацию о загрузке процессора
@@ -46,14 +146,289 @@ type DiskMetrics struct {
 	Used         uint64  `json:"used"`          // Используемый объем в байтах
 	Free         uint64  `json:"free"`          // Свободный объем в байтах
 	UsagePercent float64 `json:"usage_percent"` // Процент использования
+
+	// Все отслеживаемые точки монтирования; поля выше относятся к первой из них
+	Mountpoints []MountpointMetrics `json:"mountpoints,omitempty"`
+}
+
+// MountpointMetrics содержит заполненность одной точки монтирования
+type MountpointMetrics struct {
+	Path         string  `json:"path"`
+	Total        uint64  `json:"total"`
+	Used         uint64  `json:"used"`
+	Free         uint64  `json:"free"`
+	UsagePercent float64 `json:"usage_percent"`
 }
 
 // ProcessInfo содержит информацию о процессе
 type ProcessInfo struct {
-	PID        int32   `json:"pid"`         // ID процесса
-	Name       string  `json:"name"`        // Имя процесса
-	CPUPercent float64 `json:"cpu_percent"` // Процент использования CPU
-	MemPercent float64 `json:"mem_percent"` // Процент использования памяти
+	PID              int32     `json:"pid"`                 // ID процесса
+	Alias            string    `json:"alias,omitempty"`     // Отображаемое имя из спецификации отбора
+	PPID             int32     `json:"ppid"`                // ID родительского процесса
+	Name             string    `json:"name"`                // Имя процесса
+	Username         string    `json:"username"`            // Владелец процесса
+	State            string    `json:"state"`               // Состояние (running, sleeping, zombie, ...)
+	CPUPercent       float64   `json:"cpu_percent"`         // Процент использования CPU
+	MemPercent       float64   `json:"mem_percent"`         // Процент использования памяти
+	MemoryMB         float64   `json:"memory_mb"`           // Резидентная память (RSS) в мегабайтах
+	RSSBytes         uint64    `json:"rss_bytes"`           // Резидентная память в байтах
+	VMSBytes         uint64    `json:"vms_bytes"`           // Виртуальная память в байтах
+	NumThreads       int32     `json:"num_threads"`         // Количество потоков
+	NumFDs           int32     `json:"num_fds"`             // Количество открытых файловых дескрипторов
+	FDLimit          uint64    `json:"fd_limit"`            // Мягкий лимит RLIMIT_NOFILE (0 - неизвестен или не ограничен)
+	ReadBytesPerSec  float64   `json:"read_bytes_per_sec"`  // Скорость чтения с диска, байт/с
+	WriteBytesPerSec float64   `json:"write_bytes_per_sec"` // Скорость записи на диск, байт/с
+	StartTime        time.Time `json:"start_time"`          // Время запуска процесса
+	UptimeSeconds    float64   `json:"uptime_seconds"`      // Время работы процесса в секундах
+}
+
+// ProcessGroupInfo содержит суммарные показатели всех экземпляров процесса с одним именем (или псевдонимом)
+type ProcessGroupInfo struct {
+	Name             string  `json:"name"`                // Псевдоним или имя процесса
+	Count            int     `json:"count"`               // Количество экземпляров
+	Restarts10m      int     `json:"restarts_10m"`        // Количество перезапусков за последние 10 минут
+	CPUPercent       float64 `json:"cpu_percent"`         // Суммарный процент использования CPU
+	MemPercent       float64 `json:"mem_percent"`         // Суммарный процент использования памяти
+	MemoryMB         float64 `json:"memory_mb"`           // Суммарная резидентная память в мегабайтах
+	RSSBytes         uint64  `json:"rss_bytes"`           // Суммарная резидентная память в байтах
+	VMSBytes         uint64  `json:"vms_bytes"`           // Суммарная виртуальная память в байтах
+	NumThreads       int32   `json:"num_threads"`         // Суммарное количество потоков
+	NumFDs           int32   `json:"num_fds"`             // Суммарное количество открытых дескрипторов
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`  // Суммарная скорость чтения, байт/с
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"` // Суммарная скорость записи, байт/с
+}
+
+// ProcessMatcher описывает правило отбора отслеживаемых процессов.
+// Все заданные условия должны выполняться одновременно; пустые условия не проверяются.
+type ProcessMatcher struct {
+	Alias        string `json:"alias" yaml:"alias"`                                     // Отображаемое имя (process.<alias>.<field> в алертах)
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`                   // Точное имя процесса
+	NameRegex    string `json:"name_regex,omitempty" yaml:"name_regex,omitempty"`       // Регулярное выражение для имени
+	Cmdline      string `json:"cmdline,omitempty" yaml:"cmdline,omitempty"`             // Подстрока командной строки
+	CmdlineRegex string `json:"cmdline_regex,omitempty" yaml:"cmdline_regex,omitempty"` // Регулярное выражение для командной строки
+	User         string `json:"user,omitempty" yaml:"user,omitempty"`                   // Владелец процесса
+	Exe          string `json:"exe,omitempty" yaml:"exe,omitempty"`                     // Путь к исполняемому файлу
+	Cgroup       string `json:"cgroup,omitempty" yaml:"cgroup,omitempty"`               // Подстрока пути cgroup
+	SystemdUnit  string `json:"systemd_unit,omitempty" yaml:"systemd_unit,omitempty"`   // Имя systemd-юнита (nginx.service)
+}
+
+// ContainerMatcher описывает правило отбора отслеживаемых контейнеров.
+// Позволяет следить за логическим сервисом независимо от имен реплик.
+type ContainerMatcher struct {
+	Alias     string `json:"alias" yaml:"alias"`                               // Отображаемое имя (container.<alias>.<field> в алертах)
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`             // Точное имя контейнера
+	NameRegex string `json:"name_regex,omitempty" yaml:"name_regex,omitempty"` // Регулярное выражение для имени
+	Image     string `json:"image,omitempty" yaml:"image,omitempty"`           // Шаблон образа (nginx:*, registry/billing/*)
+	Label     string `json:"label,omitempty" yaml:"label,omitempty"`           // Метки через запятую: key=value или key
+}
+
+// CgroupTarget задает отслеживаемую cgroup v2: путь относительно корня cgroupfs.
+// Путь может быть шаблоном (system.slice/*.service), тогда каждая найденная cgroup отчитывается отдельно.
+type CgroupTarget struct {
+	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"` // Отображаемое имя (только для пути без шаблона)
+	Path  string `json:"path" yaml:"path"`                       // Путь, например system.slice/nginx.service
+}
+
+// CgroupInfo содержит показатели cgroup v2
+type CgroupInfo struct {
+	Name                   string      `json:"name"`                      // Псевдоним или путь относительно корня
+	Path                   string      `json:"path"`                      // Путь относительно корня cgroupfs
+	CPUUsageSeconds        float64     `json:"cpu_usage_seconds"`         // Накопленное время CPU (cpu.stat usage_usec)
+	CPUPercent             float64     `json:"cpu_percent"`               // Загрузка CPU за интервал (100 = одно ядро)
+	NrPeriods              uint64      `json:"nr_periods"`                // Число периодов квоты CPU
+	NrThrottled            uint64      `json:"nr_throttled"`              // Число периодов с троттлингом
+	ThrottledUsec          uint64      `json:"throttled_usec"`            // Суммарное время троттлинга
+	ThrottledPercent       float64     `json:"throttled_percent"`         // Доля периодов с троттлингом за интервал
+	ThrottledSecondsPerSec float64     `json:"throttled_seconds_per_sec"` // Время троттлинга за секунду интервала
+	MemoryCurrent          uint64      `json:"memory_current"`            // memory.current
+	MemoryMax              uint64      `json:"memory_max"`                // memory.max (0 - без ограничения)
+	MemoryUsagePercent     float64     `json:"memory_usage_percent"`      // memory.current / memory.max
+	OOM                    uint64      `json:"oom"`                       // memory.events oom
+	OOMKill                uint64      `json:"oom_kill"`                  // memory.events oom_kill
+	OOMKillDelta           uint64      `json:"oom_kill_delta"`            // Новые oom_kill за интервал
+	IOReadBytes            uint64      `json:"io_read_bytes"`             // io.stat rbytes по всем устройствам
+	IOWriteBytes           uint64      `json:"io_write_bytes"`            // io.stat wbytes по всем устройствам
+	IOReadBytesPerSec      float64     `json:"io_read_bytes_per_sec"`     // Скорость чтения за интервал
+	IOWriteBytesPerSec     float64     `json:"io_write_bytes_per_sec"`    // Скорость записи за интервал
+	PIDsCurrent            uint64      `json:"pids_current"`              // pids.current
+	PIDsMax                uint64      `json:"pids_max"`                  // pids.max (0 - без ограничения)
+	PSI                    *PSIMetrics `json:"psi,omitempty"`             // cpu.pressure, memory.pressure, io.pressure
+}
+
+// SystemdUnitInfo содержит состояние systemd-юнита
+type SystemdUnitInfo struct {
+	Name            string    `json:"name"`                   // Имя юнита (nginx.service)
+	Description     string    `json:"description,omitempty"`  // Описание юнита
+	LoadState       string    `json:"load_state"`             // loaded, not-found, masked, ...
+	ActiveState     string    `json:"active_state"`           // active, inactive, failed, activating, ...
+	SubState        string    `json:"sub_state"`              // running, exited, dead, auto-restart, ...
+	Result          string    `json:"result,omitempty"`       // Результат последнего запуска (success, exit-code, ...)
+	NRestarts       uint64    `json:"n_restarts"`             // Число автоматических перезапусков
+	MainPID         int       `json:"main_pid"`               // PID основного процесса (0 - не запущен)
+	MemoryBytes     uint64    `json:"memory_bytes"`           // MemoryCurrent (при включенном MemoryAccounting)
+	CPUUsageSeconds float64   `json:"cpu_usage_seconds"`      // CPUUsageNSec (при включенном CPUAccounting)
+	CPUPercent      float64   `json:"cpu_percent"`            // Загрузка CPU за интервал (100 = одно ядро)
+	TasksCurrent    uint64    `json:"tasks_current"`          // Число задач в cgroup юнита
+	ActiveSince     time.Time `json:"active_since,omitempty"` // Время перехода в active
+	UptimeSeconds   float64   `json:"uptime_seconds"`         // Время в состоянии active
+}
+
+// LogSource описывает отслеживаемый журнал и шаблоны, совпадения с которыми считаются
+type LogSource struct {
+	Name        string       `json:"name" yaml:"name"`                                     // Имя журнала (log.<name>.<pattern> в алертах)
+	Path        string       `json:"path" yaml:"path"`                                     // Путь к файлу
+	Patterns    []LogPattern `json:"patterns" yaml:"patterns"`                             // Именованные регулярные выражения
+	SampleLines int          `json:"sample_lines,omitempty" yaml:"sample_lines,omitempty"` // Сколько совпавших строк сохранять как примеры
+}
+
+// LogPattern - именованное регулярное выражение для подсчета строк журнала
+type LogPattern struct {
+	Name  string `json:"name" yaml:"name"`   // Имя шаблона (error, oom, http_5xx)
+	Regex string `json:"regex" yaml:"regex"` // Регулярное выражение
+}
+
+// LogFileInfo содержит результаты чтения журнала. Lines, BytesRead и Counts относятся к последнему чтению;
+// для алертов агент считает совпадения за скользящие окна (Windows), которые не зависят от периода опроса
+type LogFileInfo struct {
+	Name      string               `json:"name"`              // Имя журнала
+	Path      string               `json:"path"`              // Путь к файлу
+	Lines     int                  `json:"lines"`             // Прочитано строк за интервал
+	BytesRead int64                `json:"bytes_read"`        // Прочитано байт за интервал
+	Offset    int64                `json:"offset"`            // Позиция чтения в текущем файле
+	Rotated   bool                 `json:"rotated,omitempty"` // За интервал файл был ротирован
+	Counts    map[string]int       `json:"counts"`            // Число совпадений по имени шаблона
+	Windows   map[string]LogWindow `json:"windows,omitempty"` // Строки и совпадения за последние 1m, 5m, 15m и 1h
+	Samples   map[string][]string  `json:"samples,omitempty"` // Примеры совпавших строк по имени шаблона
+	Error     string               `json:"error,omitempty"`   // Ошибка чтения
+}
+
+// LogWindow - прочитанные строки и совпадения шаблонов журнала за скользящее окно
+type LogWindow struct {
+	Lines  int            `json:"lines"`
+	Counts map[string]int `json:"counts"`
+}
+
+// ExecPlugin описывает внешнюю команду, вывод которой превращается в показатели раздела custom
+type ExecPlugin struct {
+	Name     string        `json:"name" yaml:"name"`                             // Имя плагина
+	Command  []string      `json:"command" yaml:"command"`                       // Исполняемый файл и аргументы (без shell)
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"` // Период запуска, по умолчанию 60s
+	Timeout  time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`   // Таймаут, по умолчанию 10s
+	Format   string        `json:"format,omitempty" yaml:"format,omitempty"`     // json (по умолчанию), prometheus или nagios
+}
+
+// CustomMetric содержит показатель, полученный от плагина
+type CustomMetric struct {
+	Name      string            `json:"name"`              // Имя показателя (custom.<name>.value в алертах)
+	Plugin    string            `json:"plugin"`            // Имя плагина
+	Value     float64           `json:"value"`             // Значение
+	Labels    map[string]string `json:"labels,omitempty"`  // Метки (Prometheus, JSON)
+	Status    int               `json:"status"`            // 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN (Nagios, ошибка запуска)
+	Message   string            `json:"message,omitempty"` // Текст Nagios-плагина или ошибка запуска
+	Timestamp time.Time         `json:"timestamp"`         // Время получения значения
+}
+
+// Probe описывает проверку доступности локального сервиса
+type Probe struct {
+	Name           string        `json:"name" yaml:"name"`                                           // Имя проверки (probe.<name>.up в алертах)
+	Type           string        `json:"type" yaml:"type"`                                           // http, tcp или unix
+	Target         string        `json:"target" yaml:"target"`                                       // URL, host:port или путь к сокету
+	ExpectedStatus int           `json:"expected_status,omitempty" yaml:"expected_status,omitempty"` // Ожидаемый HTTP-код, по умолчанию любой 2xx/3xx
+	BodyRegex      string        `json:"body_regex,omitempty" yaml:"body_regex,omitempty"`           // Регулярное выражение для тела ответа
+	Timeout        time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`                 // Таймаут, по умолчанию 5s
+	TLSSkipVerify  bool          `json:"tls_skip_verify,omitempty" yaml:"tls_skip_verify,omitempty"` // Не проверять сертификат (самоподписанные)
+}
+
+// ProbeResult содержит результат проверки доступности
+type ProbeResult struct {
+	Name         string     `json:"name"`                     // Имя проверки
+	Type         string     `json:"type"`                     // http, tcp или unix
+	Target       string     `json:"target"`                   // Проверяемый адрес
+	Up           bool       `json:"up"`                       // Сервис доступен и ответ соответствует ожиданиям
+	LatencyMs    float64    `json:"latency_ms"`               // Время проверки в миллисекундах
+	StatusCode   int        `json:"status_code,omitempty"`    // HTTP-код ответа
+	CertExpiry   *time.Time `json:"cert_expiry,omitempty"`    // Окончание действия сертификата (HTTPS)
+	CertDaysLeft float64    `json:"cert_days_left,omitempty"` // Дней до окончания действия сертификата
+	Error        string     `json:"error,omitempty"`          // Причина недоступности
+}
+
+// SensorReading - показание аппаратного датчика из hwmon или зоны thermal
+type SensorReading struct {
+	Chip  string  `json:"chip"`           // Микросхема hwmon (coretemp, nct6775, nvme) или thermal для зон thermal
+	Label string  `json:"label"`          // Метка датчика (package_id_0, fan1, vcore)
+	Type  string  `json:"type"`           // temperature, fan или voltage
+	Value float64 `json:"value"`          // °C, об/мин или В
+	Max   float64 `json:"max,omitempty"`  // Верхний порог, если его сообщает датчик
+	Crit  float64 `json:"crit,omitempty"` // Критический порог, если его сообщает датчик
+}
+
+// AuthMetrics содержит текущие сеансы пользователей и результаты входа по SSH за интервал сбора
+type AuthMetrics struct {
+	Sessions        []UserSession  `json:"sessions"`
+	SSHAccepted     int            `json:"ssh_accepted"`             // Успешные входы
+	SSHFailed       int            `json:"ssh_failed"`               // Неудачные попытки: неверный пароль или ключ, несуществующий пользователь
+	SSHInvalidUsers int            `json:"ssh_invalid_users"`        // Попытки входа несуществующих пользователей
+	FailedSources   map[string]int `json:"failed_sources,omitempty"` // Адреса с наибольшим числом неудачных попыток
+}
+
+// UserSession - сеанс пользователя из utmp
+type UserSession struct {
+	User      string    `json:"user"`
+	TTY       string    `json:"tty"`
+	Host      string    `json:"host,omitempty"` // Адрес или имя удаленного хоста
+	PID       int       `json:"pid"`
+	LoginTime time.Time `json:"login_time"`
+}
+
+// HostInventory содержит сведения о хосте, которые меняются редко
+type HostInventory struct {
+	Hostname           string    `json:"hostname"`
+	OS                 string    `json:"os"`                            // linux, freebsd, ...
+	Platform           string    `json:"platform"`                      // Дистрибутив (ubuntu, centos, ...)
+	PlatformFamily     string    `json:"platform_family"`               // Семейство дистрибутива (debian, rhel, ...)
+	PlatformVersion    string    `json:"platform_version"`              // Версия дистрибутива
+	KernelVersion      string    `json:"kernel_version"`                // Версия ядра
+	Arch               string    `json:"arch"`                          // Архитектура (x86_64, aarch64, ...)
+	CPUModel           string    `json:"cpu_model"`                     // Модель процессора
+	CPUCores           int       `json:"cpu_cores"`                     // Физические ядра
+	CPUThreads         int       `json:"cpu_threads"`                   // Логические процессоры
+	MemoryTotal        uint64    `json:"memory_total"`                  // Объем RAM в байтах
+	BootTime           time.Time `json:"boot_time"`                     // Время загрузки
+	UptimeSeconds      uint64    `json:"uptime_seconds"`                // Время работы с момента загрузки
+	Virtualization     string    `json:"virtualization,omitempty"`      // kvm, vmware, docker, lxc, ...
+	VirtualizationRole string    `json:"virtualization_role,omitempty"` // guest или host
+	MachineID          string    `json:"machine_id,omitempty"`          // Идентификатор машины
+	IPAddresses        []string  `json:"ip_addresses"`                  // Адреса интерфейсов, кроме loopback и link-local
+	CollectedAt        time.Time `json:"collected_at"`                  // Время сбора сведений
+}
+
+// Package описывает установленный пакет
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version"` // Версия в формате менеджера пакетов ([epoch:]version-release)
+	Arch    string `json:"arch,omitempty"`
+}
+
+// PackageChange описывает изменение пакета: установку (OldVersion пуст), удаление (NewVersion пуст) или смену версии
+type PackageChange struct {
+	Name       string `json:"name"`
+	Arch       string `json:"arch,omitempty"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+}
+
+// PackageInventory содержит список установленных пакетов или изменения относительно предыдущего списка.
+// Изменения Changes переводят список с хешем BaseHash в список с хешем Hash; если у получателя
+// другой BaseHash, полный список запрашивается через GET /packages
+type PackageInventory struct {
+	Manager     string          `json:"manager"`             // dpkg или rpm
+	Hash        string          `json:"hash"`                // Хеш текущего списка пакетов
+	BaseHash    string          `json:"base_hash,omitempty"` // Хеш списка, к которому применяются Changes
+	Full        bool            `json:"full,omitempty"`      // Packages содержит полный список
+	Count       int             `json:"count"`               // Число установленных пакетов
+	Packages    []Package       `json:"packages,omitempty"`
+	Changes     []PackageChange `json:"changes,omitempty"`
+	CollectedAt time.Time       `json:"collected_at"` // Время последнего чтения базы пакетов
 }
 
 // PortInfo содержит информацию об открытом сетевом порте
@@ -65,10 +440,31 @@ type PortInfo struct {
 
 // ContainerInfo содержит информацию о Docker-контейнере
 type ContainerInfo struct {
-	ID         string  `json:"id"`          // Короткий ID контейнера
-	Name       string  `json:"name"`        // Имя контейнера
-	Image      string  `json:"image"`       // Образ контейнера
-	Status     string  `json:"status"`      // Статус (running, stopped, etc.)
-	CPUPercent float64 `json:"cpu_percent"` // Процент использования CPU
-	MemPercent float64 `json:"mem_percent"` // Процент использования памяти
+	ID                    string            `json:"id"`                        // Полный ID контейнера
+	Name                  string            `json:"name"`                      // Имя контейнера
+	Alias                 string            `json:"alias,omitempty"`           // Имя правила отбора, которому соответствует контейнер
+	Image                 string            `json:"image"`                     // Образ контейнера
+	Status                string            `json:"status"`                    // Статус (running, stopped, etc.)
+	Health                string            `json:"health,omitempty"`          // Статус healthcheck (healthy, unhealthy, starting); пусто, если проверка не задана
+	CPUPercent            float64           `json:"cpu_percent"`               // Процент использования CPU
+	MemPercent            float64           `json:"mem_percent"`               // Процент использования памяти
+	MemoryUsageBytes      uint64            `json:"memory_usage_bytes"`        // Используемая память без учета страничного кэша
+	MemoryLimitBytes      uint64            `json:"memory_limit_bytes"`        // Лимит памяти
+	MemoryCacheBytes      uint64            `json:"memory_cache_bytes"`        // Страничный кэш
+	MemoryRSSBytes        uint64            `json:"memory_rss_bytes"`          // Анонимная память (RSS)
+	NetRxBytes            uint64            `json:"net_rx_bytes"`              // Принято байт по всем интерфейсам
+	NetTxBytes            uint64            `json:"net_tx_bytes"`              // Отправлено байт по всем интерфейсам
+	NetRxBytesPerSec      float64           `json:"net_rx_bytes_per_sec"`      // Скорость приема за интервал
+	NetTxBytesPerSec      float64           `json:"net_tx_bytes_per_sec"`      // Скорость отправки за интервал
+	BlockReadBytes        uint64            `json:"block_read_bytes"`          // Прочитано с блочных устройств
+	BlockWriteBytes       uint64            `json:"block_write_bytes"`         // Записано на блочные устройства
+	BlockReadBytesPerSec  float64           `json:"block_read_bytes_per_sec"`  // Скорость чтения за интервал
+	BlockWriteBytesPerSec float64           `json:"block_write_bytes_per_sec"` // Скорость записи за интервал
+	PIDs                  uint64            `json:"pids"`                      // Число процессов в контейнере
+	RestartCount          int               `json:"restart_count"`             // Число перезапусков Docker-ом
+	ExitCode              int               `json:"exit_code"`                 // Код выхода последнего завершения
+	OOMKilled             bool              `json:"oom_killed"`                // Контейнер завершен OOM killer
+	StartedAt             time.Time         `json:"started_at"`                // Время запуска
+	UptimeSeconds         float64           `json:"uptime_seconds"`            // Время работы (0 для остановленных)
+	Labels                map[string]string `json:"labels,omitempty"`          // Метки контейнера
 }
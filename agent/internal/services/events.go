@@ -0,0 +1,80 @@
+package service
+
+import (
+	"agent/internal/models"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// eventRetention - сколько событий хранится для отдачи ЦМ; ЦМ опрашивает реже, чем собираются метрики,
+	// поэтому каждое событие попадает в несколько ответов и дедуплицируется по ID
+	eventRetention = time.Hour
+	// maxBufferedEvents ограничивает память при лавине событий
+	maxBufferedEvents = 1000
+)
+
+// EventBuffer хранит недавние события агента
+type EventBuffer struct {
+	mu      sync.Mutex
+	events  []models.Event
+	prefix  string
+	counter uint64
+}
+
+// NewEventBuffer создает буфер событий; префикс ID уникален для каждого запуска агента
+func NewEventBuffer() *EventBuffer {
+	return &EventBuffer{
+		prefix: fmt.Sprintf("%x", time.Now().UnixNano()),
+	}
+}
+
+// Add сохраняет события, присваивая им идентификаторы
+func (b *EventBuffer) Add(events ...models.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, e := range events {
+		b.counter++
+		e.ID = fmt.Sprintf("%s-%d", b.prefix, b.counter)
+		if e.Timestamp.IsZero() {
+			e.Timestamp = time.Now()
+		}
+		b.events = append(b.events, e)
+	}
+	b.trim(time.Now())
+}
+
+// Recent возвращает копию событий за период хранения
+func (b *EventBuffer) Recent() []models.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trim(time.Now())
+	if len(b.events) == 0 {
+		return nil
+	}
+	result := make([]models.Event, len(b.events))
+	copy(result, b.events)
+	return result
+}
+
+// trim удаляет устаревшие события и ограничивает размер буфера
+func (b *EventBuffer) trim(now time.Time) {
+	threshold := now.Add(-eventRetention)
+	start := 0
+	for start < len(b.events) && b.events[start].Timestamp.Before(threshold) {
+		start++
+	}
+	if over := len(b.events) - start - maxBufferedEvents; over > 0 {
+		start += over
+	}
+	if start > 0 {
+		b.events = append([]models.Event(nil), b.events[start:]...)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	coll "agent/internal/collectors"
 	"agent/internal/config"
 	"agent/internal/models"
-	//"log"
+	"encoding/json"
+	"errors"
+	"log"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 )
@@ -12,45 +16,251 @@ import (
 // MetricsServiceInterface определяет методы для работы с метриками
 type MetricsServiceInterface interface {
 	UpdateProcessConfig(processes []string) error
+	UpdateProcessMatchers(matchers []models.ProcessMatcher) error
 	GetProcessConfig() []string
+	GetProcessMatchers() []models.ProcessMatcher
 	IsProcessConfigSet() bool
 	UpdateContainerConfig(containers []string) error
+	UpdateContainerMatchers(matchers []models.ContainerMatcher) error
 	GetContainerConfig() []string
+	GetContainerMatchers() []models.ContainerMatcher
 	IsContainerConfigSet() bool
-	ProcessMetrics(metrics models.AgentMetrics)
+	UpdateSystemdUnits(units []string) error
+	GetSystemdUnits() []string
+	UpdateProbes(probes []models.Probe) error
+	GetProbes() []models.Probe
+	ProcessMetrics(metrics *models.AgentMetrics)
+	GetPackages() (*models.PackageInventory, error)
+	RecordEvents(events ...models.Event)
+	RecentEvents() []models.Event
+	ConfigureCollectors(settings []models.CollectorSettings) error
+	GetCollectors() []models.CollectorStatus
+	GetCollectorConfig(name string) (*coll.Schema, interface{}, error)
+	SetCollectorConfig(name string, raw []byte) error
+	ConfigVersion() models.ConfigVersion
 }
 
+// errNotConfigured - причина, по которой коллектор без настроек не создается
+var errNotConfigured = errors.New("not configured")
+
+// errDisabledInConfig - причина, по которой коллектор, отключенный в config.yml, не создается
+var errDisabledInConfig = errors.New("disabled in config")
+
 // MetricsService предоставляет методы для работы с метриками
 type MetricsService struct {
 	processConfig      []string
+	processMatchers    []models.ProcessMatcher
 	containerConfig    []string
-	Collectors         []coll.Collector
+	containerMatchers  []models.ContainerMatcher
+	systemdUnits       []string
+	probes             []models.Probe
+	Registry           *coll.Registry
+	events             *EventBuffer
 	collectionInterval time.Duration
 	mu                 sync.RWMutex
 	processConfigSet   bool
 	containerConfigSet bool
+
+	// Конфигурация, полученная через API, и ее хеш; stateFile пустой - не сохраняется
+	stateFile  string
+	runtime    RuntimeConfig
+	configHash string
+	runtimeMu  sync.Mutex
 }
 
 // NewMetricsService создает новый сервис метрик
 func NewMetricsService(cfg *config.AgentConfig) *MetricsService {
 	// Инициализация коллекторов
-	Collectors := []coll.Collector{
-		coll.NewSystemCollector(),
-		coll.NewProcessCollector(cfg.Processes),
-		coll.NewNetworkCollector(),
+	processCollector := coll.NewProcessCollector(cfg.Processes)
+	if len(cfg.ProcessMatchers) > 0 {
+		// Простые имена из processes превращаются в правила точного совпадения
+		matchers := coll.ProcessMatchersWithNames(cfg.Processes, cfg.ProcessMatchers)
+		if err := processCollector.SetMatchers(matchers); err != nil {
+			log.Printf("Invalid process matchers in config: %v", err)
+		}
+	}
+
+	registry := coll.NewRegistry(cfg.PollInterval)
+	registry.Register("system", coll.NewSystemCollector(), 0)
+	registry.Register("process", processCollector, 0)
+	registry.Register("network", coll.NewNetworkCollector(), 0)
+	// Сведения о хосте меняются редко
+	registry.Register("inventory", coll.NewInventoryCollector(), time.Hour)
+
+	// Коллектор контейнеров добавляем, если доступна среда выполнения (Docker, Podman, containerd, CRI-O)
+	containerCollector, err := coll.NewContainerCollector(cfg.Containers, coll.ContainerOptions{
+		Runtime:        cfg.ContainerRuntime.Runtime,
+		Endpoint:       cfg.ContainerRuntime.Endpoint,
+		MaxConcurrency: cfg.ContainerRuntime.MaxConcurrency,
+		StatsTimeout:   cfg.ContainerRuntime.StatsTimeout,
+		Streaming:      cfg.ContainerRuntime.Streaming,
+	})
+	if err != nil {
+		log.Printf("Container metrics disabled: %v", err)
+		registry.RegisterUnavailable("container", err)
+	} else {
+		log.Printf("Container runtime: %s", containerCollector.Runtime())
+		if len(cfg.ContainerMatchers) > 0 {
+			// Простые имена из containers превращаются в правила точного совпадения
+			matchers := coll.ContainerMatchersWithNames(cfg.Containers, cfg.ContainerMatchers)
+			if err := containerCollector.SetMatchers(matchers); err != nil {
+				log.Printf("Invalid container matchers in config: %v", err)
+			}
+		}
+		registry.Register("container", containerCollector, 0)
+	}
+
+	// PSI есть не во всех ядрах: без него коллектор не добавляем
+	if psiCollector, err := coll.NewPSICollector(""); err != nil {
+		log.Printf("PSI metrics disabled: %v", err)
+		registry.RegisterUnavailable("psi", err)
+	} else {
+		registry.Register("psi", psiCollector, 0)
+	}
+
+	// Датчиков нет на виртуальных машинах и в контейнерах
+	if sensorsCollector, err := coll.NewSensorsCollector(cfg.Sensors.Root); err != nil {
+		log.Printf("Sensor metrics disabled: %v", err)
+		registry.RegisterUnavailable("sensors", err)
+	} else {
+		registry.Register("sensors", sensorsCollector, 0)
+	}
+
+	// /dev/kmsg доступен только root и обычно недоступен в контейнерах
+	if cfg.KernelLog.Disabled {
+		registry.RegisterUnavailable("kernel", errDisabledInConfig)
+	} else if kernelCollector, err := coll.NewKernelLogCollector(cfg.KernelLog.Path); err != nil {
+		log.Printf("Kernel log events disabled: %v", err)
+		registry.RegisterUnavailable("kernel", err)
+	} else {
+		registry.Register("kernel", kernelCollector, 0)
+	}
+
+	if cfg.Auth.Disabled {
+		registry.RegisterUnavailable("auth", errDisabledInConfig)
+	} else {
+		authCollector, err := coll.NewAuthCollector(coll.AuthOptions{
+			StateDir: cfg.StateDir,
+			LogPath:  cfg.Auth.Log,
+			UtmpPath: cfg.Auth.Utmp,
+			WtmpPath: cfg.Auth.Wtmp,
+		})
+		if err != nil {
+			log.Printf("Login monitoring disabled: %v", err)
+			registry.RegisterUnavailable("auth", err)
+		} else {
+			registry.Register("auth", authCollector, 0)
+		}
+	}
+
+	if err := coll.ValidateSystemdUnits(cfg.SystemdUnits); err != nil {
+		log.Printf("Invalid systemd units in config: %v", err)
+		cfg.SystemdUnits = nil
+	}
+	registry.Register("systemd", coll.NewSystemdCollector(cfg.SystemdUnits), 0)
+
+	if packageCollector, err := coll.NewPackageCollector(); err != nil {
+		log.Printf("Package inventory disabled: %v", err)
+		registry.RegisterUnavailable("packages", err)
+	} else {
+		registry.Register("packages", packageCollector, 0)
+	}
+
+	probeCollector, err := coll.NewProbeCollector(cfg.Probes)
+	if err != nil {
+		log.Printf("Invalid probes in config: %v", err)
+		cfg.Probes = nil
+		probeCollector, _ = coll.NewProbeCollector(nil)
+	}
+	registry.Register("probes", probeCollector, 0)
+
+	// Коллектор журналов регистрируется и без журналов, чтобы их можно было задать через PUT /config/collectors/logs
+	if logCollector, err := coll.NewLogCollector(cfg.StateDir, cfg.Logs); err != nil {
+		log.Printf("Invalid log sources in config: %v", err)
+		registry.RegisterUnavailable("logs", err)
+	} else {
+		registry.Register("logs", logCollector, 0)
 	}
 
-	// Docker коллектор добавляем, если он доступен
-	if dockerCollector, err := coll.NewDockerCollector(cfg.Containers); err == nil {
-		Collectors = append(Collectors, dockerCollector)
+	if len(cfg.FileIntegrity.Paths) > 0 {
+		fimCollector, err := coll.NewFileIntegrityCollector(cfg.FileIntegrity.Paths, coll.FileIntegrityOptions{
+			StateDir:    cfg.StateDir,
+			Interval:    cfg.FileIntegrity.Interval,
+			Inotify:     cfg.FileIntegrity.Inotify,
+			MaxFileSize: cfg.FileIntegrity.MaxFileSize,
+		})
+		if err != nil {
+			log.Printf("File integrity monitoring disabled: %v", err)
+			registry.RegisterUnavailable("file_integrity", err)
+		} else {
+			registry.Register("file_integrity", fimCollector, 0)
+		}
+	} else {
+		registry.RegisterUnavailable("file_integrity", errNotConfigured)
 	}
-	return &MetricsService{
+
+	if len(cfg.Plugins.Commands) > 0 {
+		execCollector, err := coll.NewExecCollector(cfg.Plugins.Commands, coll.ExecOptions{
+			User:           cfg.Plugins.User,
+			MaxOutputBytes: cfg.Plugins.MaxOutputBytes,
+		})
+		if err != nil {
+			log.Printf("Plugins disabled: %v", err)
+			registry.RegisterUnavailable("plugins", err)
+		} else {
+			registry.Register("plugins", execCollector, 0)
+		}
+	} else {
+		registry.RegisterUnavailable("plugins", errNotConfigured)
+	}
+
+	// Коллектор cgroup регистрируется и без целей, чтобы их можно было задать через PUT /config/collectors/cgroups
+	if cgroupCollector, err := coll.NewCgroupCollector(cfg.Cgroups.Root, cfg.Cgroups.Targets); err != nil {
+		log.Printf("Invalid cgroup targets in config: %v", err)
+		registry.RegisterUnavailable("cgroups", err)
+	} else {
+		registry.Register("cgroups", cgroupCollector, 0)
+	}
+
+	for _, st := range collectorSettings(cfg.Collectors) {
+		// По одному, чтобы ошибка в настройках одного коллектора не отменила остальные
+		if err := registry.Configure([]models.CollectorSettings{st}); err != nil {
+			log.Printf("Invalid settings of collector %s in config: %v", st.Name, err)
+		}
+	}
+
+	s := &MetricsService{
 		processConfig:      []string{},
 		containerConfig:    []string{},
-		Collectors:         Collectors,
+		systemdUnits:       cfg.SystemdUnits,
+		probes:             cfg.Probes,
+		Registry:           registry,
+		events:             NewEventBuffer(),
 		processConfigSet:   false,
 		containerConfigSet: false,
 	}
+
+	// Типизированные настройки из collectors.<name>.config проверяются той же схемой, что и PUT /config/collectors/:name
+	for name, c := range cfg.Collectors {
+		if c.Config == nil {
+			continue
+		}
+		raw, err := json.Marshal(c.Config)
+		if err == nil {
+			err = s.applyCollectorConfig(name, raw)
+		}
+		if err != nil {
+			log.Printf("Invalid %s collector config: %v", name, err)
+		}
+	}
+
+	// Конфигурация, полученная через API до перезапуска, важнее config.yml
+	if cfg.StateDir != "" {
+		s.stateFile = filepath.Join(cfg.StateDir, runtimeConfigFile)
+	}
+	s.loadRuntimeConfig()
+	s.configHash = s.runtime.hash()
+	return s
 }
 
 // UpdateProcessConfig обновляет список отслеживаемых процессов
@@ -62,13 +272,51 @@ func (s *MetricsService) UpdateProcessConfig(processes []string) error {
 	//}
 	//s.Collectors = append(s.Collectors, coll.NewProcessCollector(processes))
 	s.processConfig = processes
-	for _, c := range s.Collectors {
-		c.ChangeConfig(coll.Process, processes)
+	s.processMatchers = nil
+	for _, c := range s.Registry.Collectors() {
+		if pc, ok := c.(*coll.ProcessCollector); ok {
+			pc.SetNames(processes)
+		}
 	}
 	s.processConfigSet = true
+	s.saveCollectorConfig("process")
 	return nil
 }
 
+// UpdateProcessMatchers обновляет правила отбора отслеживаемых процессов
+func (s *MetricsService) UpdateProcessMatchers(matchers []models.ProcessMatcher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.Registry.Collectors() {
+		if pc, ok := c.(*coll.ProcessCollector); ok {
+			if err := pc.SetMatchers(matchers); err != nil {
+				return err
+			}
+		}
+	}
+
+	processes := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Alias != "" {
+			processes = append(processes, m.Alias)
+		} else {
+			processes = append(processes, m.Name)
+		}
+	}
+	s.processConfig = processes
+	s.processMatchers = matchers
+	s.processConfigSet = true
+	s.saveCollectorConfig("process")
+	return nil
+}
+
+// GetProcessMatchers возвращает текущие правила отбора процессов
+func (s *MetricsService) GetProcessMatchers() []models.ProcessMatcher {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.processMatchers
+}
+
 // GetProcessConfig возвращает текущий список отслеживаемых процессов
 func (s *MetricsService) GetProcessConfig() []string {
 	s.mu.RLock()
@@ -96,13 +344,51 @@ func (s *MetricsService) UpdateContainerConfig(containers []string) error {
 	//}
 	//s.Collectors = append(s.Collectors, dcoll)
 	s.containerConfig = containers
-	for _, c := range s.Collectors {
-		c.ChangeConfig(coll.Docker, containers)
+	s.containerMatchers = nil
+	for _, c := range s.Registry.Collectors() {
+		if cc, ok := c.(*coll.ContainerCollector); ok {
+			cc.SetNames(containers)
+		}
 	}
 	s.containerConfigSet = true
+	s.saveCollectorConfig("container")
 	return nil
 }
 
+// UpdateContainerMatchers обновляет правила отбора отслеживаемых контейнеров
+func (s *MetricsService) UpdateContainerMatchers(matchers []models.ContainerMatcher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.Registry.Collectors() {
+		if cc, ok := c.(*coll.ContainerCollector); ok {
+			if err := cc.SetMatchers(matchers); err != nil {
+				return err
+			}
+		}
+	}
+
+	containers := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Alias != "" {
+			containers = append(containers, m.Alias)
+		} else {
+			containers = append(containers, m.Name)
+		}
+	}
+	s.containerConfig = containers
+	s.containerMatchers = matchers
+	s.containerConfigSet = true
+	s.saveCollectorConfig("container")
+	return nil
+}
+
+// GetContainerMatchers возвращает текущие правила отбора контейнеров
+func (s *MetricsService) GetContainerMatchers() []models.ContainerMatcher {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.containerMatchers
+}
+
 // GetContainerConfig возвращает текущий список отслеживаемых контейнеров
 func (s *MetricsService) GetContainerConfig() []string {
 	s.mu.RLock()
@@ -117,10 +403,88 @@ func (s *MetricsService) IsContainerConfigSet() bool {
 	return s.containerConfigSet
 }
 
-// ProcessMetrics обрабатывает собранные метрики
-// TODO
-func (s *MetricsService) ProcessMetrics(metrics models.AgentMetrics) {
-	// Здесь может быть логика анализа или фильтрации метрик
+// UpdateSystemdUnits обновляет список отслеживаемых systemd-юнитов (имена или шаблоны)
+func (s *MetricsService) UpdateSystemdUnits(units []string) error {
+	if err := coll.ValidateSystemdUnits(units); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.systemdUnits = units
+	for _, c := range s.Registry.Collectors() {
+		if sc, ok := c.(*coll.SystemdCollector); ok {
+			sc.SetUnits(units)
+		}
+	}
+	s.saveCollectorConfig("systemd")
+	return nil
+}
+
+// GetSystemdUnits возвращает текущий список отслеживаемых systemd-юнитов
+func (s *MetricsService) GetSystemdUnits() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.systemdUnits
+}
+
+// UpdateProbes обновляет список проверок доступности локальных сервисов
+func (s *MetricsService) UpdateProbes(probes []models.Probe) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.applyProbes(probes); err != nil {
+		return err
+	}
+	s.saveRuntimeConfig(func(rc *RuntimeConfig) { rc.Probes = &probes })
+	return nil
+}
+
+// applyProbes передает проверки коллектору; вызывается под s.mu или до запуска сервиса
+func (s *MetricsService) applyProbes(probes []models.Probe) error {
+	for _, c := range s.Registry.Collectors() {
+		if pc, ok := c.(*coll.ProbeCollector); ok {
+			if err := pc.SetProbes(probes); err != nil {
+				return err
+			}
+		}
+	}
+	s.probes = probes
+	return nil
+}
+
+// GetProbes возвращает текущий список проверок
+func (s *MetricsService) GetProbes() []models.Probe {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.probes
+}
+
+// GetPackages возвращает полный список установленных пакетов
+func (s *MetricsService) GetPackages() (*models.PackageInventory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, c := range s.Registry.Collectors() {
+		if pc, ok := c.(*coll.PackageCollector); ok {
+			return pc.Snapshot()
+		}
+	}
+	return nil, errors.New("package inventory is not available")
+}
+
+// ProcessMetrics обрабатывает собранные метрики: новые события коллекторов попадают в буфер,
+// а в метрики подставляются все события за период хранения, чтобы ЦМ не пропустил их между опросами
+func (s *MetricsService) ProcessMetrics(metrics *models.AgentMetrics) {
+	s.events.Add(metrics.Events...)
+	metrics.Events = s.events.Recent()
+}
+
+// RecordEvents сохраняет события, зафиксированные между циклами сбора
+func (s *MetricsService) RecordEvents(events ...models.Event) {
+	s.events.Add(events...)
+}
+
+// RecentEvents возвращает события за период хранения
+func (s *MetricsService) RecentEvents() []models.Event {
+	return s.events.Recent()
 }
 
 // UpdateCollectionInterval обновляет интервал сбора метрик
@@ -128,5 +492,87 @@ func (s *MetricsService) UpdateCollectionInterval(interval time.Duration) error
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.collectionInterval = interval
+	s.Registry.SetDefaultInterval(interval)
+	s.saveRuntimeConfig(func(rc *RuntimeConfig) { rc.CollectionInterval = interval })
 	return nil
 }
+
+// ConfigureCollectors включает и отключает коллекторы, меняет их интервалы и параметры
+func (s *MetricsService) ConfigureCollectors(settings []models.CollectorSettings) error {
+	if err := s.Registry.Configure(settings); err != nil {
+		return err
+	}
+	s.saveRuntimeConfig(func(rc *RuntimeConfig) {
+		rc.CollectorSettings = mergeCollectorSettings(rc.CollectorSettings, settings)
+	})
+	return nil
+}
+
+// GetCollectors возвращает состояние всех коллекторов
+func (s *MetricsService) GetCollectors() []models.CollectorStatus {
+	return s.Registry.Status()
+}
+
+// GetCollectorConfig возвращает схему и текущие настройки коллектора
+func (s *MetricsService) GetCollectorConfig(name string) (*coll.Schema, interface{}, error) {
+	return s.Registry.Config(name)
+}
+
+// SetCollectorConfig проверяет и применяет типизированные настройки коллектора. Правила отбора
+// процессов и контейнеров и список юнитов видны и через прежние эндпоинты /config/processes и т.п.
+func (s *MetricsService) SetCollectorConfig(name string, raw []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.applyCollectorConfig(name, raw); err != nil {
+		return err
+	}
+	s.saveCollectorConfig(name)
+	return nil
+}
+
+// applyCollectorConfig применяет настройки коллектора без сохранения; вызывается под s.mu или до запуска сервиса
+func (s *MetricsService) applyCollectorConfig(name string, raw []byte) error {
+	if err := s.Registry.SetConfig(name, raw); err != nil {
+		return err
+	}
+
+	_, cfg, err := s.Registry.Config(name)
+	if err != nil {
+		return err
+	}
+	switch cfg := cfg.(type) {
+	case coll.ProcessConfig:
+		s.processMatchers = cfg.Matchers
+		s.processConfig = make([]string, 0, len(cfg.Matchers))
+		for _, m := range cfg.Matchers {
+			s.processConfig = append(s.processConfig, m.Alias)
+		}
+		s.processConfigSet = true
+	case coll.ContainerConfig:
+		s.containerMatchers = cfg.Matchers
+		s.containerConfig = make([]string, 0, len(cfg.Matchers))
+		for _, m := range cfg.Matchers {
+			s.containerConfig = append(s.containerConfig, m.Alias)
+		}
+		s.containerConfigSet = true
+	case coll.SystemdConfig:
+		s.systemdUnits = cfg.Units
+	}
+	return nil
+}
+
+// collectorSettings преобразует настройки коллекторов из файла конфигурации
+func collectorSettings(collectors map[string]config.CollectorConfig) []models.CollectorSettings {
+	settings := make([]models.CollectorSettings, 0, len(collectors))
+	for name, c := range collectors {
+		settings = append(settings, models.CollectorSettings{
+			Name:     name,
+			Enabled:  c.Enabled,
+			Interval: int64(c.Interval.Round(time.Second) / time.Second),
+			Timeout:  int64(c.Timeout.Round(time.Second) / time.Second),
+			Options:  c.Options,
+		})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+	return settings
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"agent/internal/config"
+	"testing"
+	"time"
+)
+
+func TestNewMetricsServiceCollectorSettings(t *testing.T) {
+	disabled := false
+	cfg := &config.AgentConfig{
+		PollInterval: 10 * time.Second,
+		StateDir:     t.TempDir(),
+		KernelLog:    config.KernelLogConfig{Disabled: true},
+		Auth:         config.AuthConfig{Disabled: true},
+		Collectors: map[string]config.CollectorConfig{
+			// Отключенные в config.yml коллекторы и опечатка не мешают применить остальные настройки
+			"auth":    {Interval: time.Minute},
+			"kernel":  {Interval: time.Minute},
+			"netwrok": {Interval: time.Minute},
+			"network": {Interval: 30 * time.Second},
+			"system":  {Enabled: &disabled},
+		},
+	}
+	s := NewMetricsService(cfg)
+
+	statuses := make(map[string]bool)
+	for _, st := range s.Registry.Status() {
+		statuses[st.Name] = true
+		switch st.Name {
+		case "kernel", "auth":
+			if st.Available || st.LastError != errDisabledInConfig.Error() {
+				t.Errorf("%s: available %v, error %q", st.Name, st.Available, st.LastError)
+			}
+		case "network":
+			if st.IntervalSeconds != 30 {
+				t.Errorf("network interval = %v, want 30", st.IntervalSeconds)
+			}
+		case "system":
+			if st.Enabled {
+				t.Errorf("system collector is enabled")
+			}
+		}
+	}
+	for _, name := range []string{"kernel", "auth", "network", "system"} {
+		if !statuses[name] {
+			t.Errorf("collector %s is not registered", name)
+		}
+	}
+}
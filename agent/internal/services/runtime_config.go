@@ -0,0 +1,215 @@
+package service
+
+import (
+	coll "agent/internal/collectors"
+	"agent/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runtimeConfigFile - файл в каталоге состояния агента с конфигурацией, полученной через API
+const runtimeConfigFile = "runtime_config.json"
+
+// RuntimeConfig - конфигурация, полученная через API (обычно от ЦМ). Сохраняется в каталоге состояния
+// и при запуске применяется поверх config.yml: секция, хотя бы раз полученная через API, заменяет
+// соответствующую секцию файла конфигурации, остальные берутся из config.yml.
+// Чтобы вернуться к config.yml, достаточно удалить файл и перезапустить агент.
+type RuntimeConfig struct {
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// CollectionInterval - интервал сбора по умолчанию (POST /config/interval)
+	CollectionInterval time.Duration `json:"collection_interval,omitempty"`
+	// CollectorSettings - включение, интервалы и параметры коллекторов (POST /config/collectors), по записи на коллектор
+	CollectorSettings []models.CollectorSettings `json:"collector_settings,omitempty"`
+	// Collectors - итоговые типизированные настройки коллекторов (PUT /config/collectors/:name);
+	// процессы, контейнеры и юниты из /config/processes, /config/containers и /config/units хранятся здесь же
+	Collectors map[string]json.RawMessage `json:"collectors,omitempty"`
+	// Probes - проверки доступности (POST /config/probes); nil - не менялись
+	Probes *[]models.Probe `json:"probes,omitempty"`
+}
+
+// hash возвращает хеш содержимого без версии и времени изменения: повторная отправка
+// той же конфигурации его не меняет
+func (rc RuntimeConfig) hash() string {
+	rc.Version = 0
+	rc.UpdatedAt = time.Time{}
+	data, _ := json.Marshal(rc)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadRuntimeConfig читает сохраненную конфигурацию и применяет ее поверх config.yml.
+// Секция, которую не удалось применить, пропускается, остальные применяются
+func (s *MetricsService) loadRuntimeConfig() {
+	if s.stateFile == "" {
+		return
+	}
+	data, err := os.ReadFile(s.stateFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to read runtime config: %v", err)
+		}
+		return
+	}
+	var rc RuntimeConfig
+	if err := json.Unmarshal(data, &rc); err != nil {
+		log.Printf("Ignoring corrupted %s: %v", s.stateFile, err)
+		return
+	}
+
+	var sections []string
+	if rc.CollectionInterval > 0 {
+		s.collectionInterval = rc.CollectionInterval
+		s.Registry.SetDefaultInterval(rc.CollectionInterval)
+		sections = append(sections, "interval")
+	}
+	for _, st := range rc.CollectorSettings {
+		// По одному, чтобы ошибка в настройках одного коллектора не отменила остальные
+		if err := s.Registry.Configure([]models.CollectorSettings{st}); err != nil {
+			log.Printf("Invalid saved settings of collector %s: %v", st.Name, err)
+			continue
+		}
+		sections = append(sections, "collectors."+st.Name)
+	}
+
+	names := make([]string, 0, len(rc.Collectors))
+	for name := range rc.Collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := s.applyCollectorConfig(name, rc.Collectors[name]); err != nil {
+			log.Printf("Invalid saved %s collector config: %v", name, err)
+			continue
+		}
+		sections = append(sections, "collectors."+name+".config")
+	}
+
+	if rc.Probes != nil {
+		if err := s.applyProbes(*rc.Probes); err != nil {
+			log.Printf("Invalid saved probes: %v", err)
+		} else {
+			sections = append(sections, "probes")
+		}
+	}
+
+	s.runtime = rc
+	if len(sections) > 0 {
+		log.Printf("Runtime config version %d loaded from %s, overrides config.yml: %s",
+			rc.Version, s.stateFile, strings.Join(sections, ", "))
+	}
+}
+
+// saveRuntimeConfig изменяет сохраненную конфигурацию и атомарно записывает файл. Версия растет,
+// только если изменилось содержимое. Ошибка записи не отменяет уже примененные настройки
+func (s *MetricsService) saveRuntimeConfig(update func(rc *RuntimeConfig)) {
+	s.runtimeMu.Lock()
+	defer s.runtimeMu.Unlock()
+
+	rc := s.runtime
+	update(&rc)
+	hash := rc.hash()
+	if hash == s.configHash {
+		return
+	}
+	rc.Version++
+	rc.UpdatedAt = time.Now()
+	s.runtime = rc
+	s.configHash = hash
+
+	if s.stateFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(rc, "", "  ")
+	if err == nil {
+		err = coll.WriteFileAtomic(s.stateFile, data, 0o600)
+	}
+	if err != nil {
+		log.Printf("Failed to save runtime config: %v", err)
+	}
+}
+
+// saveCollectorConfig сохраняет текущие типизированные настройки коллекторов
+func (s *MetricsService) saveCollectorConfig(names ...string) {
+	s.saveRuntimeConfig(func(rc *RuntimeConfig) {
+		collectors := make(map[string]json.RawMessage, len(rc.Collectors)+len(names))
+		for name, raw := range rc.Collectors {
+			collectors[name] = raw
+		}
+		for _, name := range names {
+			_, cfg, err := s.Registry.Config(name)
+			if err != nil {
+				continue
+			}
+			raw, err := json.Marshal(cfg)
+			if err != nil {
+				continue
+			}
+			collectors[name] = raw
+		}
+		rc.Collectors = collectors
+	})
+}
+
+// ConfigVersion возвращает версию и хеш конфигурации, полученной через API
+func (s *MetricsService) ConfigVersion() models.ConfigVersion {
+	s.runtimeMu.Lock()
+	defer s.runtimeMu.Unlock()
+
+	v := models.ConfigVersion{Version: s.runtime.Version, Hash: s.configHash}
+	if !s.runtime.UpdatedAt.IsZero() {
+		updatedAt := s.runtime.UpdatedAt
+		v.UpdatedAt = &updatedAt
+	}
+	return v
+}
+
+// mergeCollectorSettings дополняет сохраненные настройки коллекторов новыми так же, как Registry.Configure:
+// незаданные поля не меняются, параметры объединяются
+func mergeCollectorSettings(saved, updates []models.CollectorSettings) []models.CollectorSettings {
+	byName := make(map[string]models.CollectorSettings, len(saved)+len(updates))
+	for _, st := range saved {
+		byName[st.Name] = st
+	}
+	for _, u := range updates {
+		st := byName[u.Name]
+		st.Name = u.Name
+		if u.Enabled != nil {
+			enabled := *u.Enabled
+			st.Enabled = &enabled
+		}
+		if u.Interval > 0 {
+			st.Interval = u.Interval
+		}
+		if u.Timeout > 0 {
+			st.Timeout = u.Timeout
+		}
+		if len(u.Options) > 0 {
+			options := make(map[string]bool, len(st.Options)+len(u.Options))
+			for k, v := range st.Options {
+				options[k] = v
+			}
+			for k, v := range u.Options {
+				options[k] = v
+			}
+			st.Options = options
+		}
+		byName[u.Name] = st
+	}
+
+	merged := make([]models.CollectorSettings, 0, len(byName))
+	for _, st := range byName {
+		merged = append(merged, st)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"agent/internal/config"
+	"agent/internal/models"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newRuntimeTestService создает сервис с юнитами и проверкой из config.yml и каталогом состояния dir
+func newRuntimeTestService(t *testing.T, dir string) *MetricsService {
+	t.Helper()
+	return NewMetricsService(&config.AgentConfig{
+		PollInterval: 10 * time.Second,
+		StateDir:     dir,
+		KernelLog:    config.KernelLogConfig{Disabled: true},
+		Auth:         config.AuthConfig{Disabled: true},
+		SystemdUnits: []string{"nginx.service"},
+		Probes:       []models.Probe{{Name: "yml", Type: "tcp", Target: "127.0.0.1:80"}},
+	})
+}
+
+// writeRuntimeConfig записывает файл конфигурации, полученной через API
+func writeRuntimeConfig(t *testing.T, dir, data string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, runtimeConfigFile), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRuntimeConfigOverridesConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	writeRuntimeConfig(t, dir, `{
+		"version": 3,
+		"collection_interval": 20000000000,
+		"collectors": {"systemd": {"units": ["redis.service"]}}
+	}`)
+	s := newRuntimeTestService(t, dir)
+
+	// Секция, полученная через API, заменяет секцию config.yml, остальные берутся из config.yml
+	if got := s.GetSystemdUnits(); !reflect.DeepEqual(got, []string{"redis.service"}) {
+		t.Errorf("systemd units = %v, want the saved ones", got)
+	}
+	if got := s.GetProbes(); len(got) != 1 || got[0].Name != "yml" {
+		t.Errorf("probes = %+v, want the ones from config.yml", got)
+	}
+	if s.collectionInterval != 20*time.Second {
+		t.Errorf("collection interval = %v, want 20s", s.collectionInterval)
+	}
+	if v := s.ConfigVersion(); v.Version != 3 || v.Hash == "" {
+		t.Errorf("config version = %+v, want version 3", v)
+	}
+}
+
+func TestRuntimeConfigCorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	writeRuntimeConfig(t, dir, `{"version": 3, "collectors": {`)
+	s := newRuntimeTestService(t, dir)
+
+	if got := s.GetSystemdUnits(); !reflect.DeepEqual(got, []string{"nginx.service"}) {
+		t.Errorf("systemd units = %v, want the ones from config.yml", got)
+	}
+	if v := s.ConfigVersion(); v.Version != 0 || v.UpdatedAt != nil {
+		t.Errorf("config version = %+v, want none", v)
+	}
+}
+
+func TestRuntimeConfigSkipsInvalidSections(t *testing.T) {
+	dir := t.TempDir()
+	writeRuntimeConfig(t, dir, `{
+		"version": 1,
+		"collector_settings": [
+			{"name": "netwrok", "interval_seconds": 30},
+			{"name": "network", "interval_seconds": 30}
+		],
+		"collectors": {"systemd": {"units": [""]}},
+		"probes": [{"name": "api", "type": "http", "target": "http://127.0.0.1:8080/health"}]
+	}`)
+	s := newRuntimeTestService(t, dir)
+
+	// Неверные настройки systemd и неизвестный коллектор пропускаются, остальное применяется
+	if got := s.GetSystemdUnits(); !reflect.DeepEqual(got, []string{"nginx.service"}) {
+		t.Errorf("systemd units = %v, want the ones from config.yml", got)
+	}
+	if got := s.GetProbes(); len(got) != 1 || got[0].Name != "api" {
+		t.Errorf("probes = %+v, want the saved ones", got)
+	}
+	for _, st := range s.GetCollectors() {
+		if st.Name == "network" && st.IntervalSeconds != 30 {
+			t.Errorf("network interval = %v, want 30", st.IntervalSeconds)
+		}
+	}
+}
+
+func TestRuntimeConfigVersion(t *testing.T) {
+	dir := t.TempDir()
+	s := newRuntimeTestService(t, dir)
+	if v := s.ConfigVersion(); v.Version != 0 || v.UpdatedAt != nil {
+		t.Fatalf("initial config version = %+v", v)
+	}
+
+	if err := s.UpdateSystemdUnits([]string{"redis.service"}); err != nil {
+		t.Fatal(err)
+	}
+	first := s.ConfigVersion()
+	if first.Version != 1 || first.UpdatedAt == nil {
+		t.Fatalf("config version = %+v, want version 1", first)
+	}
+
+	// Повторная отправка той же конфигурации не меняет ни версию, ни хеш
+	if err := s.UpdateSystemdUnits([]string{"redis.service"}); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.ConfigVersion(); v.Version != first.Version || v.Hash != first.Hash {
+		t.Errorf("config version after the same payload = %+v, want %+v", v, first)
+	}
+
+	if err := s.UpdateSystemdUnits([]string{"redis.service", "nginx.service"}); err != nil {
+		t.Fatal(err)
+	}
+	second := s.ConfigVersion()
+	if second.Version != 2 || second.Hash == first.Hash {
+		t.Errorf("config version after a change = %+v, want version 2 with a new hash", second)
+	}
+
+	// Файл записан атомарно: временных файлов не остается, права только у владельца
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if !reflect.DeepEqual(names, []string{runtimeConfigFile}) {
+		t.Errorf("state dir = %v, want only %s", names, runtimeConfigFile)
+	}
+	path := filepath.Join(dir, runtimeConfigFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("runtime config permissions = %v, want 0600", perm)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rc RuntimeConfig
+	if err := json.Unmarshal(data, &rc); err != nil {
+		t.Fatalf("saved runtime config: %v", err)
+	}
+	if rc.Version != 2 {
+		t.Errorf("saved version = %d, want 2", rc.Version)
+	}
+
+	// После перезапуска версия и хеш сохраняются
+	restarted := newRuntimeTestService(t, dir)
+	if v := restarted.ConfigVersion(); v.Version != second.Version || v.Hash != second.Hash {
+		t.Errorf("config version after restart = %+v, want %+v", v, second)
+	}
+	if got := restarted.GetSystemdUnits(); !reflect.DeepEqual(got, []string{"redis.service", "nginx.service"}) {
+		t.Errorf("systemd units after restart = %v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package transport
 
 import (
+	coll "agent/internal/collectors"
+	"agent/internal/models"
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"time"
@@ -35,7 +39,10 @@ func (s *Server) healthCheck(c *gin.Context) {
 // @Router /api/metrics [get]
 func (s *Server) getMetrics(c *gin.Context) {
 	//log.Println(s.lastMetrics)
-	c.JSON(http.StatusOK, s.lastMetrics)
+	// Хеш берется на момент запроса: метрики могли быть собраны до последнего изменения конфигурации
+	metrics := s.lastMetrics
+	metrics.ConfigHash = s.metricsService.ConfigVersion().Hash
+	c.JSON(http.StatusOK, metrics)
 }
 
 // getSystemMetrics возвращает только системные метрики
@@ -116,20 +123,129 @@ func (s *Server) getContainerMetrics(c *gin.Context) {
 	})
 }
 
+// getSystemdUnitMetrics возвращает только состояние systemd-юнитов
+// @Summary Получение состояния systemd-юнитов
+// @Description Возвращает ActiveState/SubState, число перезапусков, основной PID, память и CPU отслеживаемых юнитов
+// @Tags metrics
+// @Produce json
+// @Success 200 {object} object{host_id=string,timestamp=string,units=[]object} "Состояние юнитов"
+// @Router /api/metrics/units [get]
+func (s *Server) getSystemdUnitMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"host_id":   s.lastMetrics.HostID,
+		"timestamp": s.lastMetrics.Timestamp,
+		"units":     s.lastMetrics.SystemdUnits,
+	})
+}
+
+// getProbeMetrics возвращает только результаты проверок доступности
+// @Summary Получение результатов проверок доступности
+// @Description Возвращает доступность, время ответа, HTTP-код и срок действия сертификата локальных сервисов
+// @Tags metrics
+// @Produce json
+// @Success 200 {object} object{host_id=string,timestamp=string,probes=[]object} "Результаты проверок"
+// @Router /api/metrics/probes [get]
+func (s *Server) getProbeMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"host_id":   s.lastMetrics.HostID,
+		"timestamp": s.lastMetrics.Timestamp,
+		"probes":    s.lastMetrics.Probes,
+	})
+}
+
+// getPackages возвращает полный список установленных пакетов
+// @Summary Получение списка установленных пакетов
+// @Description Возвращает полный список пакетов dpkg/rpm с хешем списка. ЦМ запрашивает его, если не может применить изменения из /metrics к своей копии
+// @Tags metrics
+// @Produce json
+// @Success 200 {object} object{manager=string,hash=string,count=int,packages=[]object} "Список пакетов"
+// @Failure 503 {object} object{status=string,message=string} "Менеджер пакетов не поддерживается"
+// @Router /api/packages [get]
+func (s *Server) getPackages(c *gin.Context) {
+	packages, err := s.metricsService.GetPackages()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, packages)
+}
+
+// getCgroupMetrics возвращает только метрики cgroup
+// @Summary Получение метрик cgroup
+// @Description Возвращает показатели CPU, троттлинга, памяти, IO и pids отслеживаемых cgroup v2
+// @Tags metrics
+// @Produce json
+// @Success 200 {object} object{host_id=string,timestamp=string,cgroups=[]object} "Метрики cgroup"
+// @Router /api/metrics/cgroups [get]
+func (s *Server) getCgroupMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"host_id":   s.lastMetrics.HostID,
+		"timestamp": s.lastMetrics.Timestamp,
+		"cgroups":   s.lastMetrics.Cgroups,
+	})
+}
+
+// getEvents возвращает недавние события агента
+// @Summary Получение событий
+// @Description Возвращает события за последний час (перезапуски процессов, события контейнеров). Можно отфильтровать по источнику и времени
+// @Tags metrics
+// @Produce json
+// @Param source query string false "Источник событий (process, container)"
+// @Param since query string false "Вернуть события после указанного времени (RFC3339)"
+// @Success 200 {object} object{host_id=string,events=[]models.Event} "События"
+// @Failure 400 {object} object{status=string,message=string} "Некорректный параметр since"
+// @Router /api/events [get]
+func (s *Server) getEvents(c *gin.Context) {
+	var since time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Некорректный формат since, ожидается RFC3339",
+			})
+			return
+		}
+		since = parsed
+	}
+	source := c.Query("source")
+
+	events := []models.Event{}
+	for _, e := range s.metricsService.RecentEvents() {
+		if source != "" && e.Source != source {
+			continue
+		}
+		if !since.IsZero() && !e.Timestamp.After(since) {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"host_id": s.lastMetrics.HostID,
+		"events":  events,
+	})
+}
+
 // updateProcessConfig обновляет список отслеживаемых процессов
 // @Summary Обновление списка отслеживаемых процессов
-// @Description Устанавливает список процессов, метрики которых будут собираться
+// @Description Устанавливает список процессов, метрики которых будут собираться. Вместо имен можно передать правила отбора (matchers) по regex имени, командной строке, пользователю, пути к исполняемому файлу и cgroup/systemd-юниту. Имена из processes, не совпадающие с псевдонимом ни одного правила, добавляются к правилам как точные совпадения имени
 // @Tags configuration
 // @Accept json
 // @Produce json
-// @Param request body object true "Массив имён процессов для отслеживания" example{ "processes": ["nginx", "redis"] }
+// @Param request body object true "Массив имён процессов и/или правил отбора" example{ "processes": ["nginx", "redis"], "matchers": [{"alias": "billing-api", "name": "java", "cmdline_regex": "billing-.*\.jar"}] }
 // @Success 200 {object} object{status=string,message=string} "Конфигурация успешно обновлена"
 // @Failure 400 {object} object{status=string,message=string} "Некорректный формат данных или пустой список"
 // @Failure 500 {object} object{status=string,message=string} "Внутренняя ошибка сервера"
 // @Router /api/config/processes [post]
 func (s *Server) updateProcessConfig(c *gin.Context) {
 	var config struct {
-		Processes []string `json:"processes"`
+		Processes []string                `json:"processes"`
+		Matchers  []models.ProcessMatcher `json:"matchers"`
 	}
 
 	if err := c.BindJSON(&config); err != nil {
@@ -140,7 +256,7 @@ func (s *Server) updateProcessConfig(c *gin.Context) {
 		return
 	}
 
-	if len(config.Processes) == 0 {
+	if len(config.Processes) == 0 && len(config.Matchers) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": "Список процессов не может быть пустым",
@@ -148,8 +264,17 @@ func (s *Server) updateProcessConfig(c *gin.Context) {
 		return
 	}
 
-	// Обновляем конфигурацию через сервис
-	if err := s.metricsService.UpdateProcessConfig(config.Processes); err != nil {
+	// Обновляем конфигурацию через сервис; имена без правил отбора добавляются к правилам как точные совпадения
+	if len(config.Matchers) > 0 {
+		matchers := coll.ProcessMatchersWithNames(config.Processes, config.Matchers)
+		if err := s.metricsService.UpdateProcessMatchers(matchers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Некорректные правила отбора процессов: " + err.Error(),
+			})
+			return
+		}
+	} else if err := s.metricsService.UpdateProcessConfig(config.Processes); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
 			"message": "Не удалось обновить конфигурацию",
@@ -163,20 +288,95 @@ func (s *Server) updateProcessConfig(c *gin.Context) {
 	})
 }
 
+// updateSystemdUnitConfig обновляет список отслеживаемых systemd-юнитов
+// @Summary Обновление списка отслеживаемых systemd-юнитов
+// @Description Устанавливает юниты, состояние которых будет собираться. Допускаются шаблоны (php*-fpm.service); пустой список отключает сбор
+// @Tags configuration
+// @Accept json
+// @Produce json
+// @Param request body object true "Массив имён или шаблонов юнитов" example{ "units": ["nginx.service", "php*-fpm.service"] }
+// @Success 200 {object} object{status=string,message=string} "Конфигурация успешно обновлена"
+// @Failure 400 {object} object{status=string,message=string} "Некорректный формат данных или имя юнита"
+// @Router /api/config/units [post]
+func (s *Server) updateSystemdUnitConfig(c *gin.Context) {
+	var config struct {
+		Units []string `json:"units"`
+	}
+
+	if err := c.BindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный формат данных",
+		})
+		return
+	}
+
+	if err := s.metricsService.UpdateSystemdUnits(config.Units); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный список юнитов: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Конфигурация systemd-юнитов обновлена",
+	})
+}
+
+// updateProbeConfig обновляет список проверок доступности
+// @Summary Обновление списка проверок доступности
+// @Description Устанавливает проверки локальных сервисов: HTTP (ожидаемый код и regex тела), TCP-подключение или Unix-сокет. Пустой список отключает проверки
+// @Tags configuration
+// @Accept json
+// @Produce json
+// @Param request body object true "Массив проверок" example{ "probes": [{"name": "nginx", "type": "http", "target": "http://127.0.0.1/health", "expected_status": 200}] }
+// @Success 200 {object} object{status=string,message=string} "Конфигурация успешно обновлена"
+// @Failure 400 {object} object{status=string,message=string} "Некорректный формат данных или описание проверки"
+// @Router /api/config/probes [post]
+func (s *Server) updateProbeConfig(c *gin.Context) {
+	var config struct {
+		Probes []models.Probe `json:"probes"`
+	}
+
+	if err := c.BindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный формат данных",
+		})
+		return
+	}
+
+	if err := s.metricsService.UpdateProbes(config.Probes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный список проверок: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Конфигурация проверок обновлена",
+	})
+}
+
 // updateContainerConfig обновляет список отслеживаемых контейнеров
 // @Summary Обновление списка отслеживаемых контейнеров
-// @Description Устанавливает список Docker контейнеров, метрики которых будут собираться
+// @Description Устанавливает список Docker контейнеров, метрики которых будут собираться. Вместо имен можно передать правила отбора (matchers) по меткам, шаблону образа и regex имени. Имена из containers, не совпадающие с псевдонимом ни одного правила, добавляются к правилам как точные совпадения имени
 // @Tags configuration
 // @Accept json
 // @Produce json
-// @Param request body object true "Массив имён контейнеров для отслеживания"
+// @Param request body object true "Массив имён контейнеров и/или правил отбора" example{ "containers": ["build-mongodb-1"], "matchers": [{"alias": "billing", "label": "com.docker.compose.project=billing"}] }
 // @Success 200 {object} object{status=string,message=string} "Конфигурация успешно обновлена"
 // @Failure 400 {object} object{status=string,message=string} "Некорректный формат данных или пустой список"
 // @Failure 500 {object} object{status=string,message=string} "Внутренняя ошибка сервера"
 // @Router /api/config/containers [post]
 func (s *Server) updateContainerConfig(c *gin.Context) {
 	var config struct {
-		Containers []string `json:"containers"`
+		Containers []string                  `json:"containers"`
+		Matchers   []models.ContainerMatcher `json:"matchers"`
 	}
 
 	if err := c.BindJSON(&config); err != nil {
@@ -187,7 +387,7 @@ func (s *Server) updateContainerConfig(c *gin.Context) {
 		return
 	}
 
-	if len(config.Containers) == 0 {
+	if len(config.Containers) == 0 && len(config.Matchers) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status":  "error",
 			"message": "Список контейнеров не может быть пустым",
@@ -195,7 +395,17 @@ func (s *Server) updateContainerConfig(c *gin.Context) {
 		return
 	}
 
-	if err := s.metricsService.UpdateContainerConfig(config.Containers); err != nil {
+	// Имена без правил отбора добавляются к правилам как точные совпадения
+	if len(config.Matchers) > 0 {
+		matchers := coll.ContainerMatchersWithNames(config.Containers, config.Matchers)
+		if err := s.metricsService.UpdateContainerMatchers(matchers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Некорректные правила отбора контейнеров: " + err.Error(),
+			})
+			return
+		}
+	} else if err := s.metricsService.UpdateContainerConfig(config.Containers); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
 			"message": "Не удалось обновить конфигурацию",
@@ -261,3 +471,144 @@ func (s *Server) updateCollectionInterval(c *gin.Context) {
 		"interval_seconds": config.Interval,
 	})
 }
+
+// getCollectors возвращает состояние коллекторов
+// @Summary Получение состояния коллекторов
+// @Description Возвращает коллекторы агента: доступность, включение, интервал, время, длительность и ошибку последнего запуска
+// @Tags configuration
+// @Produce json
+// @Success 200 {object} object{collectors=[]models.CollectorStatus} "Состояние коллекторов"
+// @Router /api/collectors [get]
+func (s *Server) getCollectors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"collectors": s.metricsService.GetCollectors(),
+	})
+}
+
+// updateCollectorConfig включает и отключает коллекторы, меняет их интервалы и параметры
+// @Summary Обновление настроек коллекторов
+// @Description Включает и отключает коллекторы по имени, задает интервал запуска и параметры (cpu, ram, disks у system; tcp, udp у network). Не указанные поля не меняются
+// @Tags configuration
+// @Accept json
+// @Produce json
+// @Param request body object true "Настройки коллекторов" example{ "collectors": [{"name": "inventory", "interval_seconds": 3600}, {"name": "network", "options": {"udp": false}}, {"name": "sensors", "enabled": false}] }
+// @Success 200 {object} object{status=string,message=string} "Настройки обновлены"
+// @Failure 400 {object} object{status=string,message=string} "Некорректный формат данных, неизвестный коллектор или параметр"
+// @Router /api/config/collectors [post]
+func (s *Server) updateCollectorConfig(c *gin.Context) {
+	var config struct {
+		Collectors []models.CollectorSettings `json:"collectors"`
+	}
+
+	if err := c.BindJSON(&config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный формат данных",
+		})
+		return
+	}
+
+	if err := s.metricsService.ConfigureCollectors(config.Collectors); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректные настройки коллекторов: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Настройки коллекторов обновлены",
+	})
+}
+
+// getCollectorSettings возвращает типизированные настройки коллектора и их схему
+// @Summary Получение настроек коллектора
+// @Description Возвращает текущие настройки коллектора (system, network, process, container, systemd) и JSON-схему, по которой проверяется PUT
+// @Tags configuration
+// @Produce json
+// @Param name path string true "Имя коллектора"
+// @Success 200 {object} object{name=string,config=object,schema=object} "Настройки коллектора"
+// @Failure 404 {object} object{status=string,message=string} "Коллектор не найден или не имеет настроек"
+// @Failure 409 {object} object{status=string,message=string} "Коллектор недоступен на этом хосте"
+// @Router /api/config/collectors/{name} [get]
+func (s *Server) getCollectorSettings(c *gin.Context) {
+	name := c.Param("name")
+	schema, config, err := s.metricsService.GetCollectorConfig(name)
+	if err != nil {
+		c.JSON(collectorConfigStatus(err), gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":   name,
+		"config": config,
+		"schema": schema,
+	})
+}
+
+// updateCollectorSettings проверяет настройки коллектора по схеме и применяет их
+// @Summary Обновление настроек коллектора
+// @Description Проверяет настройки по JSON-схеме коллектора и применяет их. Не указанные поля не меняются; при ошибке настройки остаются прежними
+// @Tags configuration
+// @Accept json
+// @Produce json
+// @Param name path string true "Имя коллектора"
+// @Param request body object true "Настройки коллектора" example{ "mountpoints": ["/", "/var/lib/docker"] }
+// @Success 200 {object} object{status=string,message=string,config=object} "Настройки обновлены"
+// @Failure 400 {object} object{status=string,message=string} "Настройки не соответствуют схеме"
+// @Failure 404 {object} object{status=string,message=string} "Коллектор не найден или не имеет настроек"
+// @Failure 409 {object} object{status=string,message=string} "Коллектор недоступен на этом хосте"
+// @Router /api/config/collectors/{name} [put]
+func (s *Server) updateCollectorSettings(c *gin.Context) {
+	name := c.Param("name")
+	raw, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Некорректный формат данных",
+		})
+		return
+	}
+
+	if err := s.metricsService.SetCollectorConfig(name, raw); err != nil {
+		c.JSON(collectorConfigStatus(err), gin.H{
+			"status":  "error",
+			"message": "Некорректные настройки коллектора: " + err.Error(),
+		})
+		return
+	}
+
+	_, config, _ := s.metricsService.GetCollectorConfig(name)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Настройки коллектора обновлены",
+		"config":  config,
+	})
+}
+
+// getConfigVersion возвращает версию конфигурации, полученной через API
+// @Summary Получение версии конфигурации
+// @Description Возвращает версию и хеш конфигурации, полученной через API и сохраненной в каталоге состояния. Хеш совпадает с config_hash в метриках; версия растет при каждом изменении
+// @Tags configuration
+// @Produce json
+// @Success 200 {object} models.ConfigVersion "Версия конфигурации"
+// @Router /api/config/version [get]
+func (s *Server) getConfigVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, s.metricsService.ConfigVersion())
+}
+
+// collectorConfigStatus подбирает HTTP-код для ошибки настроек коллектора
+func collectorConfigStatus(err error) int {
+	switch {
+	case errors.Is(err, coll.ErrUnknownCollector), errors.Is(err, coll.ErrNotConfigurable):
+		return http.StatusNotFound
+	case errors.Is(err, coll.ErrCollectorUnavailable):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -42,11 +42,23 @@ func (s *Server) setupRoutes() {
 	s.router.GET("/metrics/processes", s.getProcessMetrics)
 	s.router.GET("/metrics/network", s.getNetworkMetrics)
 	s.router.GET("/metrics/containers", s.getContainerMetrics)
+	s.router.GET("/metrics/cgroups", s.getCgroupMetrics)
+	s.router.GET("/metrics/units", s.getSystemdUnitMetrics)
+	s.router.GET("/metrics/probes", s.getProbeMetrics)
+	s.router.GET("/events", s.getEvents)
+	s.router.GET("/packages", s.getPackages)
+	s.router.GET("/collectors", s.getCollectors)
 
 	// API для обновления конфигурации
 	s.router.POST("/config/processes", s.updateProcessConfig)
 	s.router.POST("/config/containers", s.updateContainerConfig)
+	s.router.POST("/config/units", s.updateSystemdUnitConfig)
+	s.router.POST("/config/probes", s.updateProbeConfig)
 	s.router.POST("/config/interval", s.updateCollectionInterval)
+	s.router.POST("/config/collectors", s.updateCollectorConfig)
+	s.router.GET("/config/collectors/:name", s.getCollectorSettings)
+	s.router.PUT("/config/collectors/:name", s.updateCollectorSettings)
+	s.router.GET("/config/version", s.getConfigVersion)
 }
 
 // Start запускает HTTP-сервер и слушает обновления метрик
@@ -12,17 +12,62 @@ CREATE TABLE hosts (
 );
 
 -- Создание таблицы для хранения списка отслеживаемых процессов
+-- process_name - отображаемое имя; остальные столбцы - необязательные условия отбора
 CREATE TABLE host_processes (
     id SERIAL PRIMARY KEY,
     host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
-    process_name VARCHAR(255) NOT NULL
+    process_name VARCHAR(255) NOT NULL,
+    match_name VARCHAR(255) NOT NULL DEFAULT '',
+    name_regex VARCHAR(255) NOT NULL DEFAULT '',
+    cmdline VARCHAR(1024) NOT NULL DEFAULT '',
+    cmdline_regex VARCHAR(1024) NOT NULL DEFAULT '',
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    exe_path VARCHAR(1024) NOT NULL DEFAULT '',
+    cgroup VARCHAR(1024) NOT NULL DEFAULT '',
+    systemd_unit VARCHAR(255) NOT NULL DEFAULT ''
 );
 
 -- Создание таблицы для хранения списка отслеживаемых контейнеров
 CREATE TABLE host_containers (
     id SERIAL PRIMARY KEY,
     host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
-    container_name VARCHAR(255) NOT NULL
+    container_name VARCHAR(255) NOT NULL,
+    match_name VARCHAR(255) NOT NULL DEFAULT '',
+    name_regex VARCHAR(255) NOT NULL DEFAULT '',
+    image_pattern VARCHAR(255) NOT NULL DEFAULT '',
+    label_selector VARCHAR(1024) NOT NULL DEFAULT ''
+);
+
+-- Создание таблицы для хранения списка отслеживаемых systemd-юнитов
+-- unit_name - имя юнита (nginx.service) или шаблон (php*-fpm.service)
+CREATE TABLE host_systemd_units (
+    id SERIAL PRIMARY KEY,
+    host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    unit_name VARCHAR(255) NOT NULL
+);
+
+-- Создание таблицы для хранения проверок доступности локальных сервисов
+-- type - http, tcp или unix; target - URL, host:port или путь к сокету
+CREATE TABLE host_probes (
+    id SERIAL PRIMARY KEY,
+    host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    target VARCHAR(1024) NOT NULL,
+    expected_status INTEGER NOT NULL DEFAULT 0,
+    body_regex VARCHAR(1024) NOT NULL DEFAULT '',
+    timeout_seconds INTEGER NOT NULL DEFAULT 0,
+    tls_skip_verify BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Создание таблицы для хранения типизированных настроек коллекторов агента
+-- config - JSON, проверяемый агентом по схеме коллектора
+CREATE TABLE host_collector_configs (
+    host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    collector VARCHAR(64) NOT NULL,
+    config JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (host_id, collector)
 );
 
 CREATE TABLE alert_rules (
@@ -43,7 +43,10 @@ func NewApp(ctx context.Context, cfg *config.AppConfig) *App {
 		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
 	}
 
-	// Валидация структуры БД
+	// Применение миграций и валидация структуры БД
+	if err := pgdb.MigratePostgresStructure(); err != nil {
+		log.Fatalf("Database migration failed: %v", err)
+	}
 	err = pgdb.EnsurePostgresStructure()
 	if err != nil {
 		log.Fatalf("Database structure verification failed: %v", err)
@@ -65,7 +68,10 @@ func NewApp(ctx context.Context, cfg *config.AppConfig) *App {
 	hostRepo := pg_repo.NewPostgresHostRepository(pgdb.DB)
 	processRepo := pg_repo.NewPostgresProcessRepository(pgdb.DB)
 	containerRepo := pg_repo.NewPostgresContainerRepository(pgdb.DB)
+	systemdRepo := pg_repo.NewPostgresSystemdUnitRepository(pgdb.DB)
+	probeRepo := pg_repo.NewPostgresProbeRepository(pgdb.DB)
 	alertRepo := pg_repo.NewPostgresAlertRepository(pgdb.DB)
+	collectorConfigRepo := pg_repo.NewPostgresCollectorConfigRepository(pgdb.DB)
 	metricRepo := repositories.NewMongoMetricRepository(mongoDB.Database)
 
 	// Инициализация сервисов
@@ -73,10 +79,18 @@ func NewApp(ctx context.Context, cfg *config.AppConfig) *App {
 		*hostRepo,
 		*processRepo,
 		*containerRepo,
+		*systemdRepo,
+		*probeRepo,
 		*alertRepo,
 		*metricRepo,
+		*collectorConfigRepo,
 	)
 
+	if err := hostService.SetMaintenanceWindows(cfg.MaintenanceWindows); err != nil {
+		log.Printf("Invalid maintenance windows: %v", err)
+	}
+	hostService.SetMetricsConfig(cfg.Metrics)
+
 	// Загрузка начальных данных
 	if err := hostService.LoadInitialData(ctx, cfg); err != nil {
 		log.Printf("Initial data loading error: %v", err)
@@ -102,16 +116,24 @@ func NewApp(ctx context.Context, cfg *config.AppConfig) *App {
 	hostHandler := api.NewHostHandler(hostService)
 	processHandler := api.NewProcessHandler(hostService)
 	containerHandler := api.NewContainerHandler(hostService)
+	systemdHandler := api.NewSystemdUnitHandler(hostService)
+	probeHandler := api.NewProbeHandler(hostService)
+	packageHandler := api.NewPackageHandler(hostService)
 	alertHandler := api.NewAlertHandler(hostService, alertService)
 	metricHandler := api.NewMetricHandler(hostService)
+	collectorHandler := api.NewCollectorConfigHandler(hostService)
 
 	// Создаем общий обработчик
 	handler := &api.Handler{
 		HostHandler:      hostHandler,
 		ProcessHandler:   processHandler,
 		ContainerHandler: containerHandler,
+		SystemdHandler:   systemdHandler,
+		ProbeHandler:     probeHandler,
+		PackageHandler:   packageHandler,
 		AlertHandler:     alertHandler,
 		MetricHandler:    metricHandler,
+		CollectorHandler: collectorHandler,
 	}
 
 	// Создание Gin роутера
@@ -11,6 +11,9 @@ type AppConfig struct {
 	Logging  LoggingConfig  `yaml:"logging" json:"logging"`
 	Alerts   AlertsConfig   `yaml:"alerts" json:"alerts"`
 
+	// Окна обслуживания: изменения пакетов в эти периоды считаются плановыми
+	MaintenanceWindows []MaintenanceWindowConfig `yaml:"maintenance_windows" json:"maintenance_windows"`
+
 	// Инициальные данные для БД
 	InitialData InitialDataConfig `yaml:"initial_data" json:"initial_data"`
 }
@@ -84,6 +87,15 @@ type ContainerMetricsConfig struct {
 	Enabled bool `yaml:"enabled" json:"enabled"`
 }
 
+// MaintenanceWindowConfig описывает повторяющееся окно обслуживания
+type MaintenanceWindowConfig struct {
+	Hosts    []string `yaml:"hosts" json:"hosts"`       // Имена хостов; пусто - все хосты
+	Days     []string `yaml:"days" json:"days"`         // mon, tue, ...; пусто - каждый день
+	Start    string   `yaml:"start" json:"start"`       // Начало, ЧЧ:ММ
+	End      string   `yaml:"end" json:"end"`           // Окончание, ЧЧ:ММ; раньше начала - окно через полночь
+	Timezone string   `yaml:"timezone" json:"timezone"` // Часовой пояс, по умолчанию локальный
+}
+
 // LoggingConfig содержит настройки логирования
 type LoggingConfig struct {
 	Level      string `yaml:"level" json:"level" env:"LOG_LEVEL"`
@@ -109,6 +121,48 @@ type HostConfig struct {
 	Processes  []string          `yaml:"processes" json:"processes"`
 	Containers []string          `yaml:"containers" json:"containers"`
 	Alerts     []AlertRuleConfig `yaml:"alerts" json:"alerts"`
+
+	// Процессы, отбираемые по regex, командной строке, пользователю или cgroup
+	ProcessMatchers []ProcessMatcherConfig `yaml:"process_matchers" json:"process_matchers"`
+	// Контейнеры, отбираемые по меткам, образу или regex имени
+	ContainerMatchers []ContainerMatcherConfig `yaml:"container_matchers" json:"container_matchers"`
+	// systemd-юниты: имена или шаблоны (php*-fpm.service)
+	SystemdUnits []string `yaml:"systemd_units" json:"systemd_units"`
+	// Проверки доступности локальных сервисов
+	Probes []ProbeConfig `yaml:"probes" json:"probes"`
+}
+
+// ProbeConfig представляет проверку доступности локального сервиса
+type ProbeConfig struct {
+	Name           string `yaml:"name" json:"name"`
+	Type           string `yaml:"type" json:"type"`
+	Target         string `yaml:"target" json:"target"`
+	ExpectedStatus int    `yaml:"expected_status" json:"expected_status"`
+	BodyRegex      string `yaml:"body_regex" json:"body_regex"`
+	TimeoutSeconds int    `yaml:"timeout_seconds" json:"timeout_seconds"`
+	TLSSkipVerify  bool   `yaml:"tls_skip_verify" json:"tls_skip_verify"`
+}
+
+// ProcessMatcherConfig представляет правило отбора процесса с отображаемым именем
+type ProcessMatcherConfig struct {
+	Alias        string `yaml:"alias" json:"alias"`
+	Name         string `yaml:"name" json:"name"`
+	NameRegex    string `yaml:"name_regex" json:"name_regex"`
+	Cmdline      string `yaml:"cmdline" json:"cmdline"`
+	CmdlineRegex string `yaml:"cmdline_regex" json:"cmdline_regex"`
+	User         string `yaml:"user" json:"user"`
+	Exe          string `yaml:"exe" json:"exe"`
+	Cgroup       string `yaml:"cgroup" json:"cgroup"`
+	SystemdUnit  string `yaml:"systemd_unit" json:"systemd_unit"`
+}
+
+// ContainerMatcherConfig представляет правило отбора контейнеров с отображаемым именем
+type ContainerMatcherConfig struct {
+	Alias     string `yaml:"alias" json:"alias"`
+	Name      string `yaml:"name" json:"name"`
+	NameRegex string `yaml:"name_regex" json:"name_regex"`
+	Image     string `yaml:"image" json:"image"`
+	Label     string `yaml:"label" json:"label"`
 }
 
 // AlertRuleConfig представляет конфигурацию правила оповещения
@@ -80,7 +80,17 @@ func createTTLIndexes(db *mongo.Database, ttlDays int) error {
 		"system_metrics",
 		"process_metrics",
 		"container_metrics",
+		"cgroup_metrics",
+		"systemd_metrics",
+		"log_metrics",
+		"custom_metrics",
+		"sensor_metrics",
+		"collector_status",
+		"auth_metrics",
+		"probe_metrics",
 		"network_metrics",
+		"events",
+		"security_events",
 	}
 
 	ttlSeconds := int32(ttlDays * 24 * 60 * 60)
@@ -100,5 +110,38 @@ func createTTLIndexes(db *mongo.Database, ttlDays int) error {
 		}
 	}
 
-	return nil
+	// Уникальный индекс для дедупликации событий, повторно присылаемых агентом
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, collection := range []string{"events", "security_events"} {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "host_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Сведения о хосте хранятся одним документом на хост, а их история не удаляется по TTL
+	_, err := db.Collection("host_inventory").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"host_id": 1},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("inventory_history").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "host_id", Value: 1}, {Key: "timestamp", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Списки пакетов: один документ на хост и поиск хостов по имени пакета
+	_, err = db.Collection("host_packages").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"host_id": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"packages.name": 1}},
+	})
+	return err
 }
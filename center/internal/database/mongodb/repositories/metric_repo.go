@@ -38,12 +38,259 @@ func (r *MongoMetricRepository) SaveContainerMetrics(ctx context.Context, metric
 	return err
 }
 
+func (r *MongoMetricRepository) SaveCgroupMetrics(ctx context.Context, metrics *models.CgroupMetrics) error {
+	collection := r.db.Collection("cgroup_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveSystemdUnitMetrics(ctx context.Context, metrics *models.SystemdUnitMetrics) error {
+	collection := r.db.Collection("systemd_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveLogMetrics(ctx context.Context, metrics *models.LogMetrics) error {
+	collection := r.db.Collection("log_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveCustomMetrics(ctx context.Context, metrics *models.CustomMetrics) error {
+	collection := r.db.Collection("custom_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveSensorMetrics(ctx context.Context, metrics *models.SensorMetrics) error {
+	collection := r.db.Collection("sensor_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveCollectorStatus(ctx context.Context, metrics *models.CollectorStatusMetrics) error {
+	collection := r.db.Collection("collector_status")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveAuthMetrics(ctx context.Context, metrics *models.AuthMetrics) error {
+	collection := r.db.Collection("auth_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
+func (r *MongoMetricRepository) SaveProbeMetrics(ctx context.Context, metrics *models.ProbeMetrics) error {
+	collection := r.db.Collection("probe_metrics")
+	_, err := collection.InsertOne(ctx, metrics)
+	return err
+}
+
 func (r *MongoMetricRepository) SaveNetworkMetrics(ctx context.Context, metrics *models.NetworkMetrics) error {
 	collection := r.db.Collection("network_metrics")
 	_, err := collection.InsertOne(ctx, metrics)
 	return err
 }
 
+// SaveEvents сохраняет события хоста. Агент повторяет недавние события в каждом ответе,
+// поэтому вставка идет с upsert по (host_id, event_id)
+func (r *MongoMetricRepository) SaveEvents(ctx context.Context, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("events")
+	writes := make([]mongo.WriteModel, 0, len(events))
+	for _, e := range events {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"host_id": e.HostID, "event_id": e.EventID}).
+			SetUpdate(bson.M{"$setOnInsert": e}).
+			SetUpsert(true))
+	}
+
+	_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *MongoMetricRepository) GetEventsInRange(ctx context.Context, hostID int, source string, from, to time.Time) ([]models.Event, error) {
+	collection := r.db.Collection("events")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	if source != "" {
+		filter["source"] = source
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SaveSecurityEvents сохраняет события безопасности (изменения файлов и т.п.) в отдельную коллекцию,
+// как и SaveSecurityEvents - с upsert по (host_id, event_id)
+func (r *MongoMetricRepository) SaveSecurityEvents(ctx context.Context, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("security_events")
+	writes := make([]mongo.WriteModel, 0, len(events))
+	for _, e := range events {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"host_id": e.HostID, "event_id": e.EventID}).
+			SetUpdate(bson.M{"$setOnInsert": e}).
+			SetUpsert(true))
+	}
+
+	_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *MongoMetricRepository) GetSecurityEventsInRange(ctx context.Context, hostID int, source string, from, to time.Time) ([]models.Event, error) {
+	collection := r.db.Collection("security_events")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	if source != "" {
+		filter["source"] = source
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetHostInventory возвращает сохраненные сведения о хосте или nil, если их еще нет
+func (r *MongoMetricRepository) GetHostInventory(ctx context.Context, hostID int) (*models.HostInventory, error) {
+	collection := r.db.Collection("host_inventory")
+
+	var inventory models.HostInventory
+	err := collection.FindOne(ctx, bson.M{"host_id": hostID}).Decode(&inventory)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// SaveHostInventory заменяет сведения о хосте новыми (один документ на хост)
+func (r *MongoMetricRepository) SaveHostInventory(ctx context.Context, inventory *models.HostInventory) error {
+	collection := r.db.Collection("host_inventory")
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"host_id": inventory.HostID},
+		inventory,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// SaveInventoryChanges сохраняет историю изменений сведений о хосте
+func (r *MongoMetricRepository) SaveInventoryChanges(ctx context.Context, changes []models.InventoryChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("inventory_history")
+	docs := make([]interface{}, 0, len(changes))
+	for _, c := range changes {
+		docs = append(docs, c)
+	}
+	_, err := collection.InsertMany(ctx, docs)
+	return err
+}
+
+// GetInventoryHistory возвращает изменения сведений о хосте, новые первыми
+func (r *MongoMetricRepository) GetInventoryHistory(ctx context.Context, hostID int) ([]models.InventoryChange, error) {
+	collection := r.db.Collection("inventory_history")
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	cursor, err := collection.Find(ctx, bson.M{"host_id": hostID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var changes []models.InventoryChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// GetHostPackages возвращает сохраненный список пакетов хоста или nil, если его еще нет
+func (r *MongoMetricRepository) GetHostPackages(ctx context.Context, hostID int) (*models.HostPackages, error) {
+	collection := r.db.Collection("host_packages")
+
+	var packages models.HostPackages
+	err := collection.FindOne(ctx, bson.M{"host_id": hostID}).Decode(&packages)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &packages, nil
+}
+
+// SaveHostPackages заменяет список пакетов хоста
+func (r *MongoMetricRepository) SaveHostPackages(ctx context.Context, packages *models.HostPackages) error {
+	collection := r.db.Collection("host_packages")
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"host_id": packages.HostID},
+		packages,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// FindHostPackagesByName возвращает списки пакетов хостов, на которых установлен пакет с указанным именем
+func (r *MongoMetricRepository) FindHostPackagesByName(ctx context.Context, name string) ([]models.HostPackages, error) {
+	collection := r.db.Collection("host_packages")
+
+	cursor, err := collection.Find(ctx, bson.M{"packages.name": name})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []models.HostPackages
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (r *MongoMetricRepository) GetLastSystemMetrics(ctx context.Context, hostID int) (*models.SystemMetrics, error) {
 	collection := r.db.Collection("system_metrics")
 	filter := bson.M{"host_id": hostID}
@@ -132,6 +379,206 @@ func (r *MongoMetricRepository) GetContainerMetricsInRange(ctx context.Context,
 	return metrics, nil
 }
 
+func (r *MongoMetricRepository) GetCgroupMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CgroupMetrics, error) {
+	collection := r.db.Collection("cgroup_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.CgroupMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetSystemdUnitMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.SystemdUnitMetrics, error) {
+	collection := r.db.Collection("systemd_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.SystemdUnitMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetLogMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.LogMetrics, error) {
+	collection := r.db.Collection("log_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.LogMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetCustomMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CustomMetrics, error) {
+	collection := r.db.Collection("custom_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.CustomMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetSensorMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.SensorMetrics, error) {
+	collection := r.db.Collection("sensor_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.SensorMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetCollectorStatusInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CollectorStatusMetrics, error) {
+	collection := r.db.Collection("collector_status")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.CollectorStatusMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetAuthMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.AuthMetrics, error) {
+	collection := r.db.Collection("auth_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.AuthMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (r *MongoMetricRepository) GetProbeMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.ProbeMetrics, error) {
+	collection := r.db.Collection("probe_metrics")
+	filter := bson.M{
+		"host_id": hostID,
+		"timestamp": bson.M{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var metrics []models.ProbeMetrics
+	if err := cursor.All(ctx, &metrics); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
 func (r *MongoMetricRepository) GetNetworkMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.NetworkMetrics, error) {
 	collection := r.db.Collection("network_metrics")
 	filter := bson.M{
@@ -88,6 +88,60 @@ func configureConnectionPool(cfg config.PostgresConfig) {
 	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 }
 
+// migrations - идемпотентные изменения схемы для баз, созданных старым init.sql
+var migrations = []string{
+	// Условия отбора процессов
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS match_name VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS name_regex VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS cmdline VARCHAR(1024) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS cmdline_regex VARCHAR(1024) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS username VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS exe_path VARCHAR(1024) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS cgroup VARCHAR(1024) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_processes ADD COLUMN IF NOT EXISTS systemd_unit VARCHAR(255) NOT NULL DEFAULT ''`,
+	// Условия отбора контейнеров
+	`ALTER TABLE IF EXISTS host_containers ADD COLUMN IF NOT EXISTS match_name VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_containers ADD COLUMN IF NOT EXISTS name_regex VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_containers ADD COLUMN IF NOT EXISTS image_pattern VARCHAR(255) NOT NULL DEFAULT ''`,
+	`ALTER TABLE IF EXISTS host_containers ADD COLUMN IF NOT EXISTS label_selector VARCHAR(1024) NOT NULL DEFAULT ''`,
+	// Отслеживаемые systemd-юниты
+	`CREATE TABLE IF NOT EXISTS host_systemd_units (
+		id SERIAL PRIMARY KEY,
+		host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+		unit_name VARCHAR(255) NOT NULL
+	)`,
+	// Проверки доступности локальных сервисов
+	`CREATE TABLE IF NOT EXISTS host_probes (
+		id SERIAL PRIMARY KEY,
+		host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+		name VARCHAR(255) NOT NULL,
+		type VARCHAR(16) NOT NULL,
+		target VARCHAR(1024) NOT NULL,
+		expected_status INTEGER NOT NULL DEFAULT 0,
+		body_regex VARCHAR(1024) NOT NULL DEFAULT '',
+		timeout_seconds INTEGER NOT NULL DEFAULT 0,
+		tls_skip_verify BOOLEAN NOT NULL DEFAULT FALSE
+	)`,
+	// Типизированные настройки коллекторов агента
+	`CREATE TABLE IF NOT EXISTS host_collector_configs (
+		host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+		collector VARCHAR(64) NOT NULL,
+		config JSONB NOT NULL,
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (host_id, collector)
+	)`,
+}
+
+// MigratePostgresStructure применяет недостающие изменения схемы
+func MigratePostgresStructure() error {
+	for _, stmt := range migrations {
+		if _, err := DB.Exec(stmt); err != nil {
+			return fmt.Errorf("migration failed (%s): %w", stmt, err)
+		}
+	}
+	return nil
+}
+
 // Проверяет структуру БД
 func EnsurePostgresStructure() error {
 	// Проверка существования таблиц
@@ -95,6 +149,9 @@ func EnsurePostgresStructure() error {
 		"hosts",
 		"host_processes",
 		"host_containers",
+		"host_systemd_units",
+		"host_probes",
+		"host_collector_configs",
 		"alert_rules",
 	}
 
@@ -127,6 +184,14 @@ func EnsurePostgresStructure() error {
 		{Name: "id", Type: "integer", NotNull: true, PrimaryKey: true},
 		{Name: "host_id", Type: "integer", NotNull: true},
 		{Name: "process_name", Type: "character varying", NotNull: true},
+		{Name: "match_name", Type: "character varying", NotNull: true},
+		{Name: "name_regex", Type: "character varying", NotNull: true},
+		{Name: "cmdline", Type: "character varying", NotNull: true},
+		{Name: "cmdline_regex", Type: "character varying", NotNull: true},
+		{Name: "username", Type: "character varying", NotNull: true},
+		{Name: "exe_path", Type: "character varying", NotNull: true},
+		{Name: "cgroup", Type: "character varying", NotNull: true},
+		{Name: "systemd_unit", Type: "character varying", NotNull: true},
 	}); err != nil {
 		return err
 	}
@@ -135,6 +200,41 @@ func EnsurePostgresStructure() error {
 		{Name: "id", Type: "integer", NotNull: true, PrimaryKey: true},
 		{Name: "host_id", Type: "integer", NotNull: true},
 		{Name: "container_name", Type: "character varying", NotNull: true},
+		{Name: "match_name", Type: "character varying", NotNull: true},
+		{Name: "name_regex", Type: "character varying", NotNull: true},
+		{Name: "image_pattern", Type: "character varying", NotNull: true},
+		{Name: "label_selector", Type: "character varying", NotNull: true},
+	}); err != nil {
+		return err
+	}
+
+	if err := verifyTableStructure("host_systemd_units", []ColumnDefinition{
+		{Name: "id", Type: "integer", NotNull: true, PrimaryKey: true},
+		{Name: "host_id", Type: "integer", NotNull: true},
+		{Name: "unit_name", Type: "character varying", NotNull: true},
+	}); err != nil {
+		return err
+	}
+
+	if err := verifyTableStructure("host_probes", []ColumnDefinition{
+		{Name: "id", Type: "integer", NotNull: true, PrimaryKey: true},
+		{Name: "host_id", Type: "integer", NotNull: true},
+		{Name: "name", Type: "character varying", NotNull: true},
+		{Name: "type", Type: "character varying", NotNull: true},
+		{Name: "target", Type: "character varying", NotNull: true},
+		{Name: "expected_status", Type: "integer", NotNull: true},
+		{Name: "body_regex", Type: "character varying", NotNull: true},
+		{Name: "timeout_seconds", Type: "integer", NotNull: true},
+		{Name: "tls_skip_verify", Type: "boolean", NotNull: true},
+	}); err != nil {
+		return err
+	}
+
+	if err := verifyTableStructure("host_collector_configs", []ColumnDefinition{
+		{Name: "host_id", Type: "integer", NotNull: true, PrimaryKey: true},
+		{Name: "collector", Type: "character varying", NotNull: true, PrimaryKey: true},
+		{Name: "config", Type: "jsonb", NotNull: true},
+		{Name: "updated_at", Type: "timestamp without time zone", NotNull: true, Default: "now()"},
 	}); err != nil {
 		return err
 	}
@@ -160,6 +260,9 @@ func EnsurePostgresStructure() error {
 	}{
 		{"host_processes", "host_id", "hosts", "id", "CASCADE"},
 		{"host_containers", "host_id", "hosts", "id", "CASCADE"},
+		{"host_systemd_units", "host_id", "hosts", "id", "CASCADE"},
+		{"host_probes", "host_id", "hosts", "id", "CASCADE"},
+		{"host_collector_configs", "host_id", "hosts", "id", "CASCADE"},
 		{"alert_rules", "host_id", "hosts", "id", "CASCADE"},
 	}
 
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"center/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PostgresCollectorConfigRepository реализация репозитория настроек коллекторов агента
+type PostgresCollectorConfigRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCollectorConfigRepository(db *sql.DB) *PostgresCollectorConfigRepository {
+	return &PostgresCollectorConfigRepository{db: db}
+}
+
+func (r *PostgresCollectorConfigRepository) GetByHostID(ctx context.Context, hostID int) ([]models.CollectorConfig, error) {
+	const query = `
+		SELECT host_id, collector, config, updated_at
+		FROM host_collector_configs
+		WHERE host_id = $1
+		ORDER BY collector
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collector configs: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []models.CollectorConfig
+	for rows.Next() {
+		var c models.CollectorConfig
+		var raw []byte
+		if err := rows.Scan(&c.HostID, &c.Collector, &raw, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan collector config row: %w", err)
+		}
+		c.Config = raw
+		configs = append(configs, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return configs, nil
+}
+
+// Upsert сохраняет настройки коллектора, заменяя ранее сохраненные
+func (r *PostgresCollectorConfigRepository) Upsert(ctx context.Context, cfg *models.CollectorConfig) error {
+	const query = `
+		INSERT INTO host_collector_configs (host_id, collector, config, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (host_id, collector)
+		DO UPDATE SET config = EXCLUDED.config, updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, cfg.HostID, cfg.Collector, string(cfg.Config)).Scan(&cfg.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save collector config: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresCollectorConfigRepository) Delete(ctx context.Context, hostID int, collector string) error {
+	const query = `DELETE FROM host_collector_configs WHERE host_id = $1 AND collector = $2`
+
+	result, err := r.db.ExecContext(ctx, query, hostID, collector)
+	if err != nil {
+		return fmt.Errorf("failed to delete collector config: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
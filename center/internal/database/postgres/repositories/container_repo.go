@@ -17,12 +17,24 @@ func NewPostgresContainerRepository(db *sql.DB) *PostgresContainerRepository {
 	return &PostgresContainerRepository{db: db}
 }
 
+// containerColumns - столбцы host_containers в порядке сканирования scanContainer
+const containerColumns = `id, host_id, container_name, match_name, name_regex, image_pattern, label_selector`
+
+// scanContainer читает строку host_containers в модель контейнера
+func scanContainer(row rowScanner, c *models.Container) error {
+	return row.Scan(
+		&c.ID,
+		&c.HostID,
+		&c.ContainerName,
+		&c.Name,
+		&c.NameRegex,
+		&c.Image,
+		&c.Label,
+	)
+}
+
 func (r *PostgresContainerRepository) GetByHostID(ctx context.Context, hostID int) ([]models.Container, error) {
-	const query = `
-		SELECT id, host_id, container_name 
-		FROM host_containers 
-		WHERE host_id = $1
-	`
+	const query = `SELECT ` + containerColumns + ` FROM host_containers WHERE host_id = $1`
 
 	rows, err := r.db.QueryContext(ctx, query, hostID)
 	if err != nil {
@@ -33,7 +45,7 @@ func (r *PostgresContainerRepository) GetByHostID(ctx context.Context, hostID in
 	var containers []models.Container
 	for rows.Next() {
 		var c models.Container
-		if err := rows.Scan(&c.ID, &c.HostID, &c.ContainerName); err != nil {
+		if err := scanContainer(rows, &c); err != nil {
 			return nil, fmt.Errorf("failed to scan container row: %w", err)
 		}
 		containers = append(containers, c)
@@ -47,18 +59,10 @@ func (r *PostgresContainerRepository) GetByHostID(ctx context.Context, hostID in
 }
 
 func (r *PostgresContainerRepository) GetByID(ctx context.Context, id int) (*models.Container, error) {
-	const query = `
-		SELECT id, host_id, container_name 
-		FROM host_containers 
-		WHERE id = $1
-	`
+	const query = `SELECT ` + containerColumns + ` FROM host_containers WHERE id = $1`
 
 	var container models.Container
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&container.ID,
-		&container.HostID,
-		&container.ContainerName,
-	)
+	err := scanContainer(r.db.QueryRowContext(ctx, query, id), &container)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -72,8 +76,8 @@ func (r *PostgresContainerRepository) GetByID(ctx context.Context, id int) (*mod
 
 func (r *PostgresContainerRepository) Create(ctx context.Context, container *models.Container) (int, error) {
 	const query = `
-		INSERT INTO host_containers (host_id, container_name)
-		VALUES ($1, $2)
+		INSERT INTO host_containers (host_id, container_name, match_name, name_regex, image_pattern, label_selector)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
 	`
 
@@ -83,6 +87,10 @@ func (r *PostgresContainerRepository) Create(ctx context.Context, container *mod
 		query,
 		container.HostID,
 		container.ContainerName,
+		container.Name,
+		container.NameRegex,
+		container.Image,
+		container.Label,
 	).Scan(&id)
 
 	if err != nil {
@@ -95,14 +103,18 @@ func (r *PostgresContainerRepository) Create(ctx context.Context, container *mod
 func (r *PostgresContainerRepository) Update(ctx context.Context, container *models.Container) error {
 	const query = `
 		UPDATE host_containers 
-		SET container_name = $1
-		WHERE id = $2
+		SET container_name = $1, match_name = $2, name_regex = $3, image_pattern = $4, label_selector = $5
+		WHERE id = $6
 	`
 
 	result, err := r.db.ExecContext(
 		ctx,
 		query,
 		container.ContainerName,
+		container.Name,
+		container.NameRegex,
+		container.Image,
+		container.Label,
 		container.ID,
 	)
 	if err != nil {
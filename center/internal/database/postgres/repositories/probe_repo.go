@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"center/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PostgresProbeRepository реализация репозитория проверок доступности
+type PostgresProbeRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresProbeRepository(db *sql.DB) *PostgresProbeRepository {
+	return &PostgresProbeRepository{db: db}
+}
+
+func (r *PostgresProbeRepository) GetByHostID(ctx context.Context, hostID int) ([]models.Probe, error) {
+	const query = `
+		SELECT id, host_id, name, type, target, expected_status, body_regex, timeout_seconds, tls_skip_verify
+		FROM host_probes
+		WHERE host_id = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query probes: %w", err)
+	}
+	defer rows.Close()
+
+	var probes []models.Probe
+	for rows.Next() {
+		var p models.Probe
+		if err := rows.Scan(&p.ID, &p.HostID, &p.Name, &p.Type, &p.Target, &p.ExpectedStatus, &p.BodyRegex, &p.TimeoutSeconds, &p.TLSSkipVerify); err != nil {
+			return nil, fmt.Errorf("failed to scan probe row: %w", err)
+		}
+		probes = append(probes, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return probes, nil
+}
+
+func (r *PostgresProbeRepository) GetByID(ctx context.Context, id int) (*models.Probe, error) {
+	const query = `
+		SELECT id, host_id, name, type, target, expected_status, body_regex, timeout_seconds, tls_skip_verify
+		FROM host_probes
+		WHERE id = $1
+	`
+
+	var p models.Probe
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.HostID, &p.Name, &p.Type, &p.Target, &p.ExpectedStatus, &p.BodyRegex, &p.TimeoutSeconds, &p.TLSSkipVerify)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get probe: %w", err)
+	default:
+		return &p, nil
+	}
+}
+
+func (r *PostgresProbeRepository) Create(ctx context.Context, probe *models.Probe) (int, error) {
+	const query = `
+		INSERT INTO host_probes (host_id, name, type, target, expected_status, body_regex, timeout_seconds, tls_skip_verify)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.QueryRowContext(ctx, query,
+		probe.HostID,
+		probe.Name,
+		probe.Type,
+		probe.Target,
+		probe.ExpectedStatus,
+		probe.BodyRegex,
+		probe.TimeoutSeconds,
+		probe.TLSSkipVerify,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create probe: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *PostgresProbeRepository) Delete(ctx context.Context, id int) error {
+	const query = `DELETE FROM host_probes WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete probe: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("probe with ID %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *PostgresProbeRepository) Exists(ctx context.Context, hostID int, name string) (bool, error) {
+	const query = `
+		SELECT EXISTS(
+			SELECT 1 
+			FROM host_probes 
+			WHERE host_id = $1 AND name = $2
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, hostID, name).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check probe existence: %w", err)
+	}
+
+	return exists, nil
+}
@@ -15,9 +15,34 @@ func NewPostgresProcessRepository(db *sql.DB) *PostgresProcessRepository {
 	return &PostgresProcessRepository{db: db}
 }
 
+// processColumns - столбцы host_processes в порядке сканирования scanProcess
+const processColumns = `id, host_id, process_name, match_name, name_regex, cmdline, cmdline_regex, username, exe_path, cgroup, systemd_unit`
+
+// rowScanner обобщает *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProcess читает строку host_processes в модель процесса
+func scanProcess(row rowScanner, proc *models.Process) error {
+	return row.Scan(
+		&proc.ID,
+		&proc.HostID,
+		&proc.ProcessName,
+		&proc.Name,
+		&proc.NameRegex,
+		&proc.Cmdline,
+		&proc.CmdlineRegex,
+		&proc.User,
+		&proc.Exe,
+		&proc.Cgroup,
+		&proc.SystemdUnit,
+	)
+}
+
 // GetByHostID возвращает все процессы для указанного хоста
 func (p *PostgresProcessRepository) GetByHostID(ctx context.Context, hostID int) ([]models.Process, error) {
-	const query = `SELECT id, host_id, process_name FROM host_processes WHERE host_id = $1`
+	const query = `SELECT ` + processColumns + ` FROM host_processes WHERE host_id = $1`
 
 	rows, err := p.db.QueryContext(ctx, query, hostID)
 	if err != nil {
@@ -28,7 +53,7 @@ func (p *PostgresProcessRepository) GetByHostID(ctx context.Context, hostID int)
 	var processes []models.Process
 	for rows.Next() {
 		var proc models.Process
-		if err := rows.Scan(&proc.ID, &proc.HostID, &proc.ProcessName); err != nil {
+		if err := scanProcess(rows, &proc); err != nil {
 			return nil, err
 		}
 		processes = append(processes, proc)
@@ -43,12 +68,12 @@ func (p *PostgresProcessRepository) GetByHostID(ctx context.Context, hostID int)
 
 // GetByID возвращает процесс по его идентификатору
 func (p *PostgresProcessRepository) GetByID(ctx context.Context, id int) (*models.Process, error) {
-	const query = `SELECT id, host_id, process_name FROM host_processes WHERE id = $1`
+	const query = `SELECT ` + processColumns + ` FROM host_processes WHERE id = $1`
 
 	row := p.db.QueryRowContext(ctx, query, id)
 
 	var proc models.Process
-	err := row.Scan(&proc.ID, &proc.HostID, &proc.ProcessName)
+	err := scanProcess(row, &proc)
 
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
@@ -62,8 +87,9 @@ func (p *PostgresProcessRepository) GetByID(ctx context.Context, id int) (*model
 
 // Create создает новый процесс и возвращает его ID
 func (p *PostgresProcessRepository) Create(ctx context.Context, process *models.Process) (int, error) {
-	const query = `INSERT INTO host_processes (host_id, process_name) 
-				   VALUES ($1, $2) 
+	const query = `INSERT INTO host_processes (host_id, process_name, match_name, name_regex, cmdline,
+				   cmdline_regex, username, exe_path, cgroup, systemd_unit) 
+				   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
 				   RETURNING id`
 
 	var id int
@@ -72,6 +98,14 @@ func (p *PostgresProcessRepository) Create(ctx context.Context, process *models.
 		query,
 		process.HostID,
 		process.ProcessName,
+		process.Name,
+		process.NameRegex,
+		process.Cmdline,
+		process.CmdlineRegex,
+		process.User,
+		process.Exe,
+		process.Cgroup,
+		process.SystemdUnit,
 	).Scan(&id)
 
 	if err != nil {
@@ -86,8 +120,16 @@ func (p *PostgresProcessRepository) Update(ctx context.Context, process *models.
 	const query = `
 		UPDATE host_processes 
 		SET host_id = $1, 
-			process_name = $2 
-		WHERE id = $3
+			process_name = $2,
+			match_name = $3,
+			name_regex = $4,
+			cmdline = $5,
+			cmdline_regex = $6,
+			username = $7,
+			exe_path = $8,
+			cgroup = $9,
+			systemd_unit = $10
+		WHERE id = $11
 	`
 
 	result, err := p.db.ExecContext(
@@ -95,6 +137,14 @@ func (p *PostgresProcessRepository) Update(ctx context.Context, process *models.
 		query,
 		process.HostID,
 		process.ProcessName,
+		process.Name,
+		process.NameRegex,
+		process.Cmdline,
+		process.CmdlineRegex,
+		process.User,
+		process.Exe,
+		process.Cgroup,
+		process.SystemdUnit,
 		process.ID,
 	)
 
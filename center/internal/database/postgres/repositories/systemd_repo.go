@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"center/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PostgresSystemdUnitRepository реализация репозитория systemd-юнитов
+type PostgresSystemdUnitRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSystemdUnitRepository(db *sql.DB) *PostgresSystemdUnitRepository {
+	return &PostgresSystemdUnitRepository{db: db}
+}
+
+func (r *PostgresSystemdUnitRepository) GetByHostID(ctx context.Context, hostID int) ([]models.SystemdUnit, error) {
+	const query = `SELECT id, host_id, unit_name FROM host_systemd_units WHERE host_id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query systemd units: %w", err)
+	}
+	defer rows.Close()
+
+	var units []models.SystemdUnit
+	for rows.Next() {
+		var u models.SystemdUnit
+		if err := rows.Scan(&u.ID, &u.HostID, &u.UnitName); err != nil {
+			return nil, fmt.Errorf("failed to scan systemd unit row: %w", err)
+		}
+		units = append(units, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return units, nil
+}
+
+func (r *PostgresSystemdUnitRepository) GetByID(ctx context.Context, id int) (*models.SystemdUnit, error) {
+	const query = `SELECT id, host_id, unit_name FROM host_systemd_units WHERE id = $1`
+
+	var unit models.SystemdUnit
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&unit.ID, &unit.HostID, &unit.UnitName)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get systemd unit: %w", err)
+	default:
+		return &unit, nil
+	}
+}
+
+func (r *PostgresSystemdUnitRepository) Create(ctx context.Context, unit *models.SystemdUnit) (int, error) {
+	const query = `
+		INSERT INTO host_systemd_units (host_id, unit_name)
+		VALUES ($1, $2)
+		RETURNING id
+	`
+
+	var id int
+	if err := r.db.QueryRowContext(ctx, query, unit.HostID, unit.UnitName).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to create systemd unit: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *PostgresSystemdUnitRepository) Delete(ctx context.Context, id int) error {
+	const query = `DELETE FROM host_systemd_units WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete systemd unit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("systemd unit with ID %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *PostgresSystemdUnitRepository) Exists(ctx context.Context, hostID int, unitName string) (bool, error) {
+	const query = `
+		SELECT EXISTS(
+			SELECT 1 
+			FROM host_systemd_units 
+			WHERE host_id = $1 AND unit_name = $2
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, hostID, unitName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check systemd unit existence: %w", err)
+	}
+
+	return exists, nil
+}
@@ -47,6 +47,34 @@ type ContainerRepository interface {
 	Exists(ctx context.Context, hostID int, containerName string) (bool, error)
 }
 
+// SystemdUnitRepository интерфейс для работы с systemd-юнитами в БД
+type SystemdUnitRepository interface {
+	NewSystemdUnitRepository(db *sql.DB) *SystemdUnitRepository
+	GetByHostID(ctx context.Context, hostID int) ([]models.SystemdUnit, error)
+	GetByID(ctx context.Context, id int) (*models.SystemdUnit, error)
+	Create(ctx context.Context, unit *models.SystemdUnit) (int, error)
+	Delete(ctx context.Context, id int) error
+	Exists(ctx context.Context, hostID int, unitName string) (bool, error)
+}
+
+// ProbeRepository интерфейс для работы с проверками доступности в БД
+type ProbeRepository interface {
+	NewProbeRepository(db *sql.DB) *ProbeRepository
+	GetByHostID(ctx context.Context, hostID int) ([]models.Probe, error)
+	GetByID(ctx context.Context, id int) (*models.Probe, error)
+	Create(ctx context.Context, probe *models.Probe) (int, error)
+	Delete(ctx context.Context, id int) error
+	Exists(ctx context.Context, hostID int, name string) (bool, error)
+}
+
+// CollectorConfigRepository интерфейс для работы с настройками коллекторов агента в БД
+type CollectorConfigRepository interface {
+	NewCollectorConfigRepository(db *sql.DB) *CollectorConfigRepository
+	GetByHostID(ctx context.Context, hostID int) ([]models.CollectorConfig, error)
+	Upsert(ctx context.Context, cfg *models.CollectorConfig) error
+	Delete(ctx context.Context, hostID int, collector string) error
+}
+
 // AlertRepository интерфейс для работы с правилами оповещений в БД
 type AlertRepository interface {
 	NewAlertRepository(db *sql.DB) *AlertRepository
@@ -65,12 +93,39 @@ type MetricRepository interface {
 	SaveSystemMetrics(ctx context.Context, metrics *models.SystemMetrics) error
 	SaveProcessMetrics(ctx context.Context, metrics *models.ProcessMetrics) error
 	SaveContainerMetrics(ctx context.Context, metrics *models.ContainerMetrics) error
+	SaveCgroupMetrics(ctx context.Context, metrics *models.CgroupMetrics) error
+	SaveSystemdUnitMetrics(ctx context.Context, metrics *models.SystemdUnitMetrics) error
+	SaveLogMetrics(ctx context.Context, metrics *models.LogMetrics) error
+	SaveCustomMetrics(ctx context.Context, metrics *models.CustomMetrics) error
+	SaveSensorMetrics(ctx context.Context, metrics *models.SensorMetrics) error
+	SaveCollectorStatus(ctx context.Context, metrics *models.CollectorStatusMetrics) error
+	SaveAuthMetrics(ctx context.Context, metrics *models.AuthMetrics) error
+	SaveProbeMetrics(ctx context.Context, metrics *models.ProbeMetrics) error
 	SaveNetworkMetrics(ctx context.Context, metrics *models.NetworkMetrics) error
+	SaveEvents(ctx context.Context, events []models.Event) error
+	SaveSecurityEvents(ctx context.Context, events []models.Event) error
 	GetLastSystemMetrics(ctx context.Context, hostID int) (*models.SystemMetrics, error)
+	GetHostInventory(ctx context.Context, hostID int) (*models.HostInventory, error)
+	SaveHostInventory(ctx context.Context, inventory *models.HostInventory) error
+	SaveInventoryChanges(ctx context.Context, changes []models.InventoryChange) error
+	GetInventoryHistory(ctx context.Context, hostID int) ([]models.InventoryChange, error)
+	GetHostPackages(ctx context.Context, hostID int) (*models.HostPackages, error)
+	SaveHostPackages(ctx context.Context, packages *models.HostPackages) error
+	FindHostPackagesByName(ctx context.Context, name string) ([]models.HostPackages, error)
 	GetSystemMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.SystemMetrics, error)
 	GetProcessMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.ProcessMetrics, error)
 	GetContainerMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.ContainerMetrics, error)
+	GetSystemdUnitMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.SystemdUnitMetrics, error)
+	GetLogMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.LogMetrics, error)
+	GetProbeMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.ProbeMetrics, error)
+	GetCustomMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CustomMetrics, error)
+	GetSensorMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.SensorMetrics, error)
+	GetCollectorStatusInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CollectorStatusMetrics, error)
+	GetAuthMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.AuthMetrics, error)
+	GetCgroupMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.CgroupMetrics, error)
 	GetNetworkMetricsInRange(ctx context.Context, hostID int, from, to time.Time) ([]models.NetworkMetrics, error)
+	GetEventsInRange(ctx context.Context, hostID int, source string, from, to time.Time) ([]models.Event, error)
+	GetSecurityEventsInRange(ctx context.Context, hostID int, source string, from, to time.Time) ([]models.Event, error)
 	SetupTTLIndex(ctx context.Context, collectionName string, ttlSeconds int32) error
 	CleanupOldMetrics(ctx context.Context, collectionName string, threshold time.Time) error
 	Ping(ctx context.Context) error
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AuthMetrics представляет сеансы пользователей и входы по SSH за интервал сбора
+type AuthMetrics struct {
+	HostID    int       `json:"host_id" bson:"host_id"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	Auth      AuthInfo  `json:"auth" bson:"auth"`
+}
+
+// AuthInfo - раздел auth ответа агента
+type AuthInfo struct {
+	Sessions        []UserSession  `json:"sessions" bson:"sessions"`
+	SSHAccepted     int            `json:"ssh_accepted" bson:"ssh_accepted"`
+	SSHFailed       int            `json:"ssh_failed" bson:"ssh_failed"`
+	SSHInvalidUsers int            `json:"ssh_invalid_users" bson:"ssh_invalid_users"`
+	FailedSources   map[string]int `json:"failed_sources,omitempty" bson:"failed_sources,omitempty"`
+}
+
+// UserSession представляет сеанс пользователя на хосте
+type UserSession struct {
+	User      string    `json:"user" bson:"user"`
+	TTY       string    `json:"tty" bson:"tty"`
+	Host      string    `json:"host,omitempty" bson:"host,omitempty"`
+	PID       int       `json:"pid" bson:"pid"`
+	LoginTime time.Time `json:"login_time" bson:"login_time"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// CgroupMetrics представляет метрики cgroup v2 хоста
+type CgroupMetrics struct {
+	HostID    int          `json:"host_id" bson:"host_id"`
+	Timestamp time.Time    `json:"timestamp" bson:"timestamp"`
+	Cgroups   []CgroupInfo `json:"cgroups" bson:"cgroups"`
+}
+
+// CgroupInfo представляет показатели одной cgroup (слайса, сервиса или контейнера)
+type CgroupInfo struct {
+	Name                   string   `json:"name" bson:"name"`
+	Path                   string   `json:"path" bson:"path"`
+	CPUUsageSeconds        float64  `json:"cpu_usage_seconds" bson:"cpu_usage_seconds"`
+	CPUPercent             float64  `json:"cpu_percent" bson:"cpu_percent"`
+	NrPeriods              uint64   `json:"nr_periods" bson:"nr_periods"`
+	NrThrottled            uint64   `json:"nr_throttled" bson:"nr_throttled"`
+	ThrottledUsec          uint64   `json:"throttled_usec" bson:"throttled_usec"`
+	ThrottledPercent       float64  `json:"throttled_percent" bson:"throttled_percent"`
+	ThrottledSecondsPerSec float64  `json:"throttled_seconds_per_sec" bson:"throttled_seconds_per_sec"`
+	MemoryCurrent          uint64   `json:"memory_current" bson:"memory_current"`
+	MemoryMax              uint64   `json:"memory_max" bson:"memory_max"`
+	MemoryUsagePercent     float64  `json:"memory_usage_percent" bson:"memory_usage_percent"`
+	OOM                    uint64   `json:"oom" bson:"oom"`
+	OOMKill                uint64   `json:"oom_kill" bson:"oom_kill"`
+	OOMKillDelta           uint64   `json:"oom_kill_delta" bson:"oom_kill_delta"`
+	IOReadBytes            uint64   `json:"io_read_bytes" bson:"io_read_bytes"`
+	IOWriteBytes           uint64   `json:"io_write_bytes" bson:"io_write_bytes"`
+	IOReadBytesPerSec      float64  `json:"io_read_bytes_per_sec" bson:"io_read_bytes_per_sec"`
+	IOWriteBytesPerSec     float64  `json:"io_write_bytes_per_sec" bson:"io_write_bytes_per_sec"`
+	PIDsCurrent            uint64   `json:"pids_current" bson:"pids_current"`
+	PIDsMax                uint64   `json:"pids_max" bson:"pids_max"`
+	PSI                    *PSIInfo `json:"psi,omitempty" bson:"psi,omitempty"`
+}
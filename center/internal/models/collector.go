@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AgentCollectorSettings представляет настройки коллектора в формате конфигурации агента
+type AgentCollectorSettings struct {
+	Name            string          `json:"name"`
+	Enabled         *bool           `json:"enabled,omitempty"`
+	IntervalSeconds int64           `json:"interval_seconds,omitempty"`
+	Options         map[string]bool `json:"options,omitempty"`
+}
+
+// CollectorConfig представляет типизированные настройки коллектора агента для хоста.
+// Config проверяется агентом по схеме коллектора (GET /config/collectors/:name)
+type CollectorConfig struct {
+	HostID    int             `json:"host_id" db:"host_id"`
+	Collector string          `json:"collector" db:"collector"`
+	Config    json.RawMessage `json:"config" db:"config" swaggertype:"object"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// AgentConfigVersion представляет версию конфигурации агента (GET /config/version)
+type AgentConfigVersion struct {
+	Version   int64      `json:"version"`
+	Hash      string     `json:"hash"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Результаты запуска коллектора агента
+const (
+	CollectorOK      = "ok"
+	CollectorTimeout = "timeout"
+	CollectorError   = "error"
+)
+
+// CollectorRunStatus представляет результат последнего запуска коллектора агента
+type CollectorRunStatus struct {
+	Status     string    `json:"status" bson:"status"` // ok, timeout или error
+	Message    string    `json:"message,omitempty" bson:"message,omitempty"`
+	DurationMs float64   `json:"duration_ms" bson:"duration_ms"`
+	Timestamp  time.Time `json:"timestamp" bson:"timestamp"`
+}
+
+// CollectorStatusMetrics представляет состояние коллекторов агента в момент опроса
+type CollectorStatusMetrics struct {
+	HostID     int                           `json:"host_id" bson:"host_id"`
+	Timestamp  time.Time                     `json:"timestamp" bson:"timestamp"`
+	Collectors map[string]CollectorRunStatus `json:"collectors" bson:"collectors"`
+	Failed     []string                      `json:"failed,omitempty" bson:"failed,omitempty"` // Коллекторы с ошибкой или таймаутом
+}
+
+// CollectorReported сообщает, что секция коллектора содержит данные его последнего запуска. Секция коллектора,
+// завершившегося с ошибкой или по таймауту, отключенного или еще не завершившего первый запуск, пустая.
+// Агенты без collector_status присылают все секции
+func (m Metrics) CollectorReported(name string) bool {
+	if m.CollectorStatus == nil {
+		return true
+	}
+	st, ok := m.CollectorStatus[name]
+	return ok && st.Status == CollectorOK
+}
@@ -2,16 +2,41 @@ package models
 
 import "time"
 
-// Container представляет контейнер, который нужно мониторить на хосте
+// Container представляет контейнер, который нужно мониторить на хосте.
+// ContainerName служит отображаемым именем (псевдонимом) и, если условия отбора не заданы,
+// точным именем контейнера.
 type Container struct {
 	ID            int    `json:"id" db:"id"`
 	HostID        int    `json:"host_id" db:"host_id"`
 	ContainerName string `json:"container_name" binding:"required" db:"container_name"`
+	ContainerMatchSpec
 }
 
 // ContainerInput представляет данные для добавления контейнера
 type ContainerInput struct {
 	ContainerName string `json:"container_name" binding:"required"`
+	ContainerMatchSpec
+}
+
+// ContainerMatchSpec задает условия отбора контейнеров на агенте,
+// чтобы логический сервис отслеживался независимо от имен реплик.
+// Все заполненные условия должны выполняться одновременно.
+type ContainerMatchSpec struct {
+	Name      string `json:"name,omitempty" db:"match_name"`       // Точное имя контейнера
+	NameRegex string `json:"name_regex,omitempty" db:"name_regex"` // Регулярное выражение для имени
+	Image     string `json:"image,omitempty" db:"image_pattern"`   // Шаблон образа (nginx:*, registry/billing/*)
+	Label     string `json:"label,omitempty" db:"label_selector"`  // Метки через запятую: key=value или key
+}
+
+// IsEmpty сообщает, что условия отбора не заданы
+func (s ContainerMatchSpec) IsEmpty() bool {
+	return s == ContainerMatchSpec{}
+}
+
+// ContainerMatcher - правило отбора в формате агента
+type ContainerMatcher struct {
+	Alias string `json:"alias"`
+	ContainerMatchSpec
 }
 
 // ContainerMetrics представляет метрики контейнеров
@@ -23,10 +48,31 @@ type ContainerMetrics struct {
 
 // ContainerInfo представляет информацию о контейнере
 type ContainerInfo struct {
-	Name          string  `json:"name" bson:"name"`
-	ID            string  `json:"id" bson:"id"`
-	Image         string  `json:"image" bson:"image"`
-	Status        string  `json:"status" bson:"status"`
-	CPUPercent    float64 `json:"cpu_percent" bson:"cpu_percent"`
-	MemoryPercent float64 `json:"mem_percent" bson:"mem_percent"`
+	Name                  string            `json:"name" bson:"name"`
+	Alias                 string            `json:"alias,omitempty" bson:"alias,omitempty"`
+	ID                    string            `json:"id" bson:"id"`
+	Image                 string            `json:"image" bson:"image"`
+	Status                string            `json:"status" bson:"status"`
+	Health                string            `json:"health,omitempty" bson:"health,omitempty"`
+	CPUPercent            float64           `json:"cpu_percent" bson:"cpu_percent"`
+	MemoryPercent         float64           `json:"mem_percent" bson:"mem_percent"`
+	MemoryUsageBytes      uint64            `json:"memory_usage_bytes" bson:"memory_usage_bytes"`
+	MemoryLimitBytes      uint64            `json:"memory_limit_bytes" bson:"memory_limit_bytes"`
+	MemoryCacheBytes      uint64            `json:"memory_cache_bytes" bson:"memory_cache_bytes"`
+	MemoryRSSBytes        uint64            `json:"memory_rss_bytes" bson:"memory_rss_bytes"`
+	NetRxBytes            uint64            `json:"net_rx_bytes" bson:"net_rx_bytes"`
+	NetTxBytes            uint64            `json:"net_tx_bytes" bson:"net_tx_bytes"`
+	NetRxBytesPerSec      float64           `json:"net_rx_bytes_per_sec" bson:"net_rx_bytes_per_sec"`
+	NetTxBytesPerSec      float64           `json:"net_tx_bytes_per_sec" bson:"net_tx_bytes_per_sec"`
+	BlockReadBytes        uint64            `json:"block_read_bytes" bson:"block_read_bytes"`
+	BlockWriteBytes       uint64            `json:"block_write_bytes" bson:"block_write_bytes"`
+	BlockReadBytesPerSec  float64           `json:"block_read_bytes_per_sec" bson:"block_read_bytes_per_sec"`
+	BlockWriteBytesPerSec float64           `json:"block_write_bytes_per_sec" bson:"block_write_bytes_per_sec"`
+	PIDs                  uint64            `json:"pids" bson:"pids"`
+	RestartCount          int               `json:"restart_count" bson:"restart_count"`
+	ExitCode              int               `json:"exit_code" bson:"exit_code"`
+	OOMKilled             bool              `json:"oom_killed" bson:"oom_killed"`
+	StartedAt             time.Time         `json:"started_at" bson:"started_at"`
+	UptimeSeconds         float64           `json:"uptime_seconds" bson:"uptime_seconds"`
+	Labels                map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
 }
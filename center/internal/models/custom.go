@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CustomMetrics представляет показатели внешних плагинов агента.
+// Состав показателей задается плагинами, поэтому они хранятся без фиксированной схемы.
+type CustomMetrics struct {
+	HostID    int            `json:"host_id" bson:"host_id"`
+	Timestamp time.Time      `json:"timestamp" bson:"timestamp"`
+	Metrics   []CustomMetric `json:"metrics" bson:"metrics"`
+}
+
+// CustomMetric представляет один показатель плагина
+type CustomMetric struct {
+	Name      string            `json:"name" bson:"name"`
+	Plugin    string            `json:"plugin" bson:"plugin"`
+	Value     float64           `json:"value" bson:"value"`
+	Labels    map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	Status    int               `json:"status" bson:"status"`
+	Message   string            `json:"message,omitempty" bson:"message,omitempty"`
+	Timestamp time.Time         `json:"timestamp" bson:"timestamp"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Event представляет событие, зафиксированное агентом (перезапуск процесса, событие контейнера и т.п.)
+type Event struct {
+	HostID     int               `json:"host_id" bson:"host_id"`
+	EventID    string            `json:"id" bson:"event_id"` // Идентификатор, присвоенный агентом
+	Timestamp  time.Time         `json:"timestamp" bson:"timestamp"`
+	Source     string            `json:"source" bson:"source"`
+	Type       string            `json:"type" bson:"type"`
+	Object     string            `json:"object" bson:"object"`
+	Message    string            `json:"message,omitempty" bson:"message,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty" bson:"attributes,omitempty"`
+}
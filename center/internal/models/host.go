@@ -37,6 +37,28 @@ type SystemDetails struct {
 	CPU  CPUInfo  `json:"cpu" bson:"cpu"`
 	RAM  RAMInfo  `json:"ram" bson:"ram"`
 	Disk DiskInfo `json:"disk" bson:"disk"`
+	PSI  *PSIInfo `json:"psi,omitempty" bson:"psi,omitempty"`
+}
+
+// PSIInfo представляет pressure stall information по CPU, памяти и вводу-выводу
+type PSIInfo struct {
+	CPU    PSIResource `json:"cpu" bson:"cpu"`
+	Memory PSIResource `json:"memory" bson:"memory"`
+	IO     PSIResource `json:"io" bson:"io"`
+}
+
+// PSIResource представляет строки some и full файла pressure
+type PSIResource struct {
+	Some PSIStats `json:"some" bson:"some"`
+	Full PSIStats `json:"full" bson:"full"`
+}
+
+// PSIStats представляет скользящие средние (в процентах) и суммарное время простоя (в микросекундах)
+type PSIStats struct {
+	Avg10  float64 `json:"avg10" bson:"avg10"`
+	Avg60  float64 `json:"avg60" bson:"avg60"`
+	Avg300 float64 `json:"avg300" bson:"avg300"`
+	Total  uint64  `json:"total" bson:"total"`
 }
 
 type CPUInfo struct {
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// HostInventory представляет сведения о хосте, присланные агентом: ОС, ядро, оборудование и адреса.
+// Хранится одним документом на хост и обновляется при каждом новом сборе агентом.
+type HostInventory struct {
+	HostID             int       `json:"host_id" bson:"host_id"`
+	Hostname           string    `json:"hostname" bson:"hostname"`
+	OS                 string    `json:"os" bson:"os"`
+	Platform           string    `json:"platform" bson:"platform"`
+	PlatformFamily     string    `json:"platform_family" bson:"platform_family"`
+	PlatformVersion    string    `json:"platform_version" bson:"platform_version"`
+	KernelVersion      string    `json:"kernel_version" bson:"kernel_version"`
+	Arch               string    `json:"arch" bson:"arch"`
+	CPUModel           string    `json:"cpu_model" bson:"cpu_model"`
+	CPUCores           int       `json:"cpu_cores" bson:"cpu_cores"`
+	CPUThreads         int       `json:"cpu_threads" bson:"cpu_threads"`
+	MemoryTotal        uint64    `json:"memory_total" bson:"memory_total"`
+	BootTime           time.Time `json:"boot_time" bson:"boot_time"`
+	UptimeSeconds      uint64    `json:"uptime_seconds" bson:"uptime_seconds"`
+	Virtualization     string    `json:"virtualization,omitempty" bson:"virtualization,omitempty"`
+	VirtualizationRole string    `json:"virtualization_role,omitempty" bson:"virtualization_role,omitempty"`
+	MachineID          string    `json:"machine_id,omitempty" bson:"machine_id,omitempty"`
+	IPAddresses        []string  `json:"ip_addresses" bson:"ip_addresses"`
+	CollectedAt        time.Time `json:"collected_at" bson:"collected_at"`
+	UpdatedAt          time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// InventoryChange представляет изменение одного поля сведений о хосте (например, версии ядра)
+type InventoryChange struct {
+	HostID    int       `json:"host_id" bson:"host_id"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	Field     string    `json:"field" bson:"field"`
+	OldValue  string    `json:"old_value" bson:"old_value"`
+	NewValue  string    `json:"new_value" bson:"new_value"`
+}
+
+// HostDetails представляет хост вместе с последними сведениями о нем
+type HostDetails struct {
+	Host
+	Inventory *HostInventory `json:"inventory,omitempty"`
+}
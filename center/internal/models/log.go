@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// LogMetrics представляет результаты чтения журналов хоста за интервал сбора агента
+type LogMetrics struct {
+	HostID    int           `json:"host_id" bson:"host_id"`
+	Timestamp time.Time     `json:"timestamp" bson:"timestamp"`
+	Logs      []LogFileInfo `json:"logs" bson:"logs"`
+}
+
+// LogFileInfo представляет число совпадений шаблонов в одном журнале: Counts за последнее чтение агента,
+// Windows за скользящие окна 1m, 5m, 15m и 1h
+type LogFileInfo struct {
+	Name      string               `json:"name" bson:"name"`
+	Path      string               `json:"path" bson:"path"`
+	Lines     int                  `json:"lines" bson:"lines"`
+	BytesRead int64                `json:"bytes_read" bson:"bytes_read"`
+	Offset    int64                `json:"offset" bson:"offset"`
+	Rotated   bool                 `json:"rotated,omitempty" bson:"rotated,omitempty"`
+	Counts    map[string]int       `json:"counts" bson:"counts"`
+	Windows   map[string]LogWindow `json:"windows,omitempty" bson:"windows,omitempty"`
+	Samples   map[string][]string  `json:"samples,omitempty" bson:"samples,omitempty"`
+	Error     string               `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// LogWindow представляет прочитанные строки и совпадения шаблонов журнала за скользящее окно
+type LogWindow struct {
+	Lines  int            `json:"lines" bson:"lines"`
+	Counts map[string]int `json:"counts" bson:"counts"`
+}
@@ -3,12 +3,27 @@ package models
 import "time"
 
 type Metrics struct {
-	HostID         int             `json:"host_id"`
-	Timestamp      time.Time       `json:"timestamp"`
-	SystemMetrics  SystemDetails   `json:"system,omitempty"`
-	ProcessesInfo  []ProcessInfo   `json:"processes,omitempty"`
-	PortsInfo      []PortInfo      `json:"ports,omitempty"`
-	ContainersInfo []ContainerInfo `json:"containers,omitempty"`
+	HostID         int                `json:"host_id"`
+	Timestamp      time.Time          `json:"timestamp"`
+	SystemMetrics  SystemDetails      `json:"system,omitempty"`
+	ProcessesInfo  []ProcessInfo      `json:"processes,omitempty"`
+	ProcessGroups  []ProcessGroupInfo `json:"process_groups,omitempty"`
+	PortsInfo      []PortInfo         `json:"ports,omitempty"`
+	ContainersInfo []ContainerInfo    `json:"containers,omitempty"`
+	CgroupsInfo    []CgroupInfo       `json:"cgroups,omitempty"`
+	SystemdUnits   []SystemdUnitInfo  `json:"systemd_units,omitempty"`
+	Logs           []LogFileInfo      `json:"logs,omitempty"`
+	Custom         []CustomMetric     `json:"custom,omitempty"`
+	Probes         []ProbeResult      `json:"probes,omitempty"`
+	Sensors        []SensorReading    `json:"sensors,omitempty"`
+	Auth           *AuthInfo          `json:"auth,omitempty"`
+	Inventory      *HostInventory     `json:"inventory,omitempty"`
+	Packages       *PackageInventory  `json:"packages,omitempty"`
+	Events         []Event            `json:"events,omitempty"`
+
+	CollectorStatus map[string]CollectorRunStatus `json:"collector_status,omitempty"`
+	// ConfigHash - хеш конфигурации, полученной агентом через API; пустой у агентов, не сохраняющих конфигурацию
+	ConfigHash string `json:"config_hash,omitempty"`
 }
 
 // HostMetricsResponse представляет все метрики хоста за период времени
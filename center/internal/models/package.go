@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Package представляет установленный пакет
+type Package struct {
+	Name    string `json:"name" bson:"name"`
+	Version string `json:"version" bson:"version"`
+	Arch    string `json:"arch,omitempty" bson:"arch,omitempty"`
+}
+
+// PackageChange представляет изменение пакета: установку (OldVersion пуст), удаление (NewVersion пуст) или смену версии
+type PackageChange struct {
+	Name       string `json:"name"`
+	Arch       string `json:"arch,omitempty"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+}
+
+// PackageInventory представляет отчет агента о пакетах: полный список (Full)
+// или изменения, переводящие список с хешем BaseHash в список с хешем Hash
+type PackageInventory struct {
+	Manager     string          `json:"manager"`
+	Hash        string          `json:"hash"`
+	BaseHash    string          `json:"base_hash,omitempty"`
+	Full        bool            `json:"full,omitempty"`
+	Count       int             `json:"count"`
+	Packages    []Package       `json:"packages,omitempty"`
+	Changes     []PackageChange `json:"changes,omitempty"`
+	CollectedAt time.Time       `json:"collected_at"`
+}
+
+// HostPackages представляет хранимый список пакетов хоста (один документ на хост)
+type HostPackages struct {
+	HostID    int       `json:"host_id" bson:"host_id"`
+	Manager   string    `json:"manager" bson:"manager"`
+	Hash      string    `json:"hash" bson:"hash"`
+	Packages  []Package `json:"packages" bson:"packages"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// PackageHost представляет хост, на котором установлен искомый пакет
+type PackageHost struct {
+	HostID   int    `json:"host_id"`
+	Hostname string `json:"hostname"`
+	Manager  string `json:"manager"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Arch     string `json:"arch,omitempty"`
+}
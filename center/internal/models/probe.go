@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Probe представляет проверку доступности локального сервиса на хосте.
+// Type - http, tcp или unix; Target - URL, host:port или путь к сокету.
+type Probe struct {
+	ID             int    `json:"id" db:"id"`
+	HostID         int    `json:"host_id" db:"host_id"`
+	Name           string `json:"name" binding:"required" db:"name"`
+	Type           string `json:"type" binding:"required" db:"type"`
+	Target         string `json:"target" binding:"required" db:"target"`
+	ExpectedStatus int    `json:"expected_status" db:"expected_status"`
+	BodyRegex      string `json:"body_regex" db:"body_regex"`
+	TimeoutSeconds int    `json:"timeout_seconds" db:"timeout_seconds"`
+	TLSSkipVerify  bool   `json:"tls_skip_verify" db:"tls_skip_verify"`
+}
+
+// ProbeInput представляет данные для добавления проверки
+type ProbeInput struct {
+	Name           string `json:"name" binding:"required"`
+	Type           string `json:"type" binding:"required"`
+	Target         string `json:"target" binding:"required"`
+	ExpectedStatus int    `json:"expected_status"`
+	BodyRegex      string `json:"body_regex"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	TLSSkipVerify  bool   `json:"tls_skip_verify"`
+}
+
+// AgentProbe представляет проверку в формате конфигурации агента
+type AgentProbe struct {
+	Name           string        `json:"name"`
+	Type           string        `json:"type"`
+	Target         string        `json:"target"`
+	ExpectedStatus int           `json:"expected_status,omitempty"`
+	BodyRegex      string        `json:"body_regex,omitempty"`
+	Timeout        time.Duration `json:"timeout,omitempty"`
+	TLSSkipVerify  bool          `json:"tls_skip_verify,omitempty"`
+}
+
+// ProbeMetrics представляет результаты проверок доступности хоста
+type ProbeMetrics struct {
+	HostID    int           `json:"host_id" bson:"host_id"`
+	Timestamp time.Time     `json:"timestamp" bson:"timestamp"`
+	Probes    []ProbeResult `json:"probes" bson:"probes"`
+}
+
+// ProbeResult представляет результат одной проверки
+type ProbeResult struct {
+	Name         string     `json:"name" bson:"name"`
+	Type         string     `json:"type" bson:"type"`
+	Target       string     `json:"target" bson:"target"`
+	Up           bool       `json:"up" bson:"up"`
+	LatencyMs    float64    `json:"latency_ms" bson:"latency_ms"`
+	StatusCode   int        `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	CertExpiry   *time.Time `json:"cert_expiry,omitempty" bson:"cert_expiry,omitempty"`
+	CertDaysLeft float64    `json:"cert_days_left,omitempty" bson:"cert_days_left,omitempty"`
+	Error        string     `json:"error,omitempty" bson:"error,omitempty"`
+}
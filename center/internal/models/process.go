@@ -2,30 +2,88 @@ package models
 
 import "time"
 
-// Process представляет процесс, который нужно мониторить на хосте
+// Process представляет процесс, который нужно мониторить на хосте.
+// ProcessName служит отображаемым именем (псевдонимом) и, если условия отбора не заданы,
+// точным именем процесса.
 type Process struct {
 	ID          int    `json:"id" db:"id"`
 	HostID      int    `json:"host_id" db:"host_id"`
 	ProcessName string `json:"process_name" binding:"required" db:"process_name"`
+	ProcessMatchSpec
 }
 
 // ProcessInput представляет данные для добавления процесса
 type ProcessInput struct {
 	ProcessName string `json:"process_name" binding:"required"`
+	ProcessMatchSpec
+}
+
+// ProcessMatchSpec задает условия отбора процессов на агенте.
+// Все заполненные условия должны выполняться одновременно.
+type ProcessMatchSpec struct {
+	Name         string `json:"name,omitempty" db:"match_name"`             // Точное имя процесса
+	NameRegex    string `json:"name_regex,omitempty" db:"name_regex"`       // Регулярное выражение для имени
+	Cmdline      string `json:"cmdline,omitempty" db:"cmdline"`             // Подстрока командной строки
+	CmdlineRegex string `json:"cmdline_regex,omitempty" db:"cmdline_regex"` // Регулярное выражение для командной строки
+	User         string `json:"user,omitempty" db:"username"`               // Владелец процесса
+	Exe          string `json:"exe,omitempty" db:"exe_path"`                // Путь к исполняемому файлу
+	Cgroup       string `json:"cgroup,omitempty" db:"cgroup"`               // Подстрока пути cgroup
+	SystemdUnit  string `json:"systemd_unit,omitempty" db:"systemd_unit"`   // Имя systemd-юнита
+}
+
+// IsEmpty сообщает, что условия отбора не заданы
+func (s ProcessMatchSpec) IsEmpty() bool {
+	return s == ProcessMatchSpec{}
+}
+
+// ProcessMatcher - правило отбора в формате агента
+type ProcessMatcher struct {
+	Alias string `json:"alias"`
+	ProcessMatchSpec
 }
 
 // ProcessMetrics представляет метрики процессов
 type ProcessMetrics struct {
-	HostID    int           `json:"host_id" bson:"host_id"`
-	Timestamp time.Time     `json:"timestamp" bson:"timestamp"`
-	Processes []ProcessInfo `json:"processes" bson:"processes"`
+	HostID    int                `json:"host_id" bson:"host_id"`
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+	Processes []ProcessInfo      `json:"processes" bson:"processes"`
+	Groups    []ProcessGroupInfo `json:"groups,omitempty" bson:"groups,omitempty"`
 }
 
 // ProcessInfo представляет информацию о процессе
 type ProcessInfo struct {
-	Name string `json:"name" bson:"name"`
-	PID  int    `json:"pid" bson:"pid"`
-	//Status     string  `json:"status" bson:"status"`
-	CPUPercent float64 `json:"cpu_percent" bson:"cpu_percent"`
-	MemoryMB   float64 `json:"memory_mb" bson:"memory_mb"`
+	Name             string    `json:"name" bson:"name"`
+	Alias            string    `json:"alias,omitempty" bson:"alias,omitempty"`
+	PID              int       `json:"pid" bson:"pid"`
+	PPID             int       `json:"ppid" bson:"ppid"`
+	Username         string    `json:"username" bson:"username"`
+	State            string    `json:"state" bson:"state"`
+	CPUPercent       float64   `json:"cpu_percent" bson:"cpu_percent"`
+	MemPercent       float64   `json:"mem_percent" bson:"mem_percent"`
+	MemoryMB         float64   `json:"memory_mb" bson:"memory_mb"`
+	RSSBytes         uint64    `json:"rss_bytes" bson:"rss_bytes"`
+	VMSBytes         uint64    `json:"vms_bytes" bson:"vms_bytes"`
+	NumThreads       int       `json:"num_threads" bson:"num_threads"`
+	NumFDs           int       `json:"num_fds" bson:"num_fds"`
+	FDLimit          uint64    `json:"fd_limit" bson:"fd_limit"`
+	ReadBytesPerSec  float64   `json:"read_bytes_per_sec" bson:"read_bytes_per_sec"`
+	WriteBytesPerSec float64   `json:"write_bytes_per_sec" bson:"write_bytes_per_sec"`
+	StartTime        time.Time `json:"start_time" bson:"start_time"`
+	UptimeSeconds    float64   `json:"uptime_seconds" bson:"uptime_seconds"`
+}
+
+// ProcessGroupInfo представляет суммарные показатели всех экземпляров процесса с одним именем
+type ProcessGroupInfo struct {
+	Name             string  `json:"name" bson:"name"`
+	Count            int     `json:"count" bson:"count"`
+	Restarts10m      int     `json:"restarts_10m" bson:"restarts_10m"`
+	CPUPercent       float64 `json:"cpu_percent" bson:"cpu_percent"`
+	MemPercent       float64 `json:"mem_percent" bson:"mem_percent"`
+	MemoryMB         float64 `json:"memory_mb" bson:"memory_mb"`
+	RSSBytes         uint64  `json:"rss_bytes" bson:"rss_bytes"`
+	VMSBytes         uint64  `json:"vms_bytes" bson:"vms_bytes"`
+	NumThreads       int     `json:"num_threads" bson:"num_threads"`
+	NumFDs           int     `json:"num_fds" bson:"num_fds"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec" bson:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec" bson:"write_bytes_per_sec"`
 }
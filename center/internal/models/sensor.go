@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SensorMetrics представляет показания аппаратных датчиков хоста
+type SensorMetrics struct {
+	HostID    int             `json:"host_id" bson:"host_id"`
+	Timestamp time.Time       `json:"timestamp" bson:"timestamp"`
+	Sensors   []SensorReading `json:"sensors" bson:"sensors"`
+}
+
+// SensorReading представляет показание одного датчика (sensor.<chip>.<label>)
+type SensorReading struct {
+	Chip  string  `json:"chip" bson:"chip"`
+	Label string  `json:"label" bson:"label"`
+	Type  string  `json:"type" bson:"type"` // temperature, fan или voltage
+	Value float64 `json:"value" bson:"value"`
+	Max   float64 `json:"max,omitempty" bson:"max,omitempty"`
+	Crit  float64 `json:"crit,omitempty" bson:"crit,omitempty"`
+}
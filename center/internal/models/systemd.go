@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// SystemdUnit представляет systemd-юнит, который нужно мониторить на хосте.
+// UnitName - имя юнита (nginx.service) или шаблон (php*-fpm.service).
+type SystemdUnit struct {
+	ID       int    `json:"id" db:"id"`
+	HostID   int    `json:"host_id" db:"host_id"`
+	UnitName string `json:"unit_name" binding:"required" db:"unit_name"`
+}
+
+// SystemdUnitInput представляет данные для добавления systemd-юнита
+type SystemdUnitInput struct {
+	UnitName string `json:"unit_name" binding:"required"`
+}
+
+// SystemdUnitMetrics представляет состояние systemd-юнитов хоста
+type SystemdUnitMetrics struct {
+	HostID    int               `json:"host_id" bson:"host_id"`
+	Timestamp time.Time         `json:"timestamp" bson:"timestamp"`
+	Units     []SystemdUnitInfo `json:"units" bson:"units"`
+}
+
+// SystemdUnitInfo представляет состояние одного systemd-юнита
+type SystemdUnitInfo struct {
+	Name            string    `json:"name" bson:"name"`
+	Description     string    `json:"description,omitempty" bson:"description,omitempty"`
+	LoadState       string    `json:"load_state" bson:"load_state"`
+	ActiveState     string    `json:"active_state" bson:"active_state"`
+	SubState        string    `json:"sub_state" bson:"sub_state"`
+	Result          string    `json:"result,omitempty" bson:"result,omitempty"`
+	NRestarts       uint64    `json:"n_restarts" bson:"n_restarts"`
+	MainPID         int       `json:"main_pid" bson:"main_pid"`
+	MemoryBytes     uint64    `json:"memory_bytes" bson:"memory_bytes"`
+	CPUUsageSeconds float64   `json:"cpu_usage_seconds" bson:"cpu_usage_seconds"`
+	CPUPercent      float64   `json:"cpu_percent" bson:"cpu_percent"`
+	TasksCurrent    uint64    `json:"tasks_current" bson:"tasks_current"`
+	ActiveSince     time.Time `json:"active_since,omitempty" bson:"active_since,omitempty"`
+	UptimeSeconds   float64   `json:"uptime_seconds" bson:"uptime_seconds"`
+}
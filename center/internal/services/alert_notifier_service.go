@@ -6,10 +6,12 @@ import (
 	"center/internal/models"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/smtp"
+	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -130,32 +132,73 @@ func (s *AlertNotifierService) CheckHostAlerts(ctx context.Context, host *models
 	}
 }
 
+// metricCollectors - коллекторы агента, заполняющие секции метрик по типу метрики. Типы по событиям
+// (fim, kernel, auth) считают события за окно и не зависят от последнего запуска коллектора
+var metricCollectors = map[string]string{
+	"system":    "system",
+	"process":   "process",
+	"container": "container",
+	"systemd":   "systemd",
+	"probe":     "probes",
+	"custom":    "plugins",
+	"sensor":    "sensors",
+	"log":       "logs",
+	"cgroup":    "cgroups",
+	"network":   "network",
+}
+
 func (s *AlertNotifierService) evaluateRule(metrics *models.Metrics, rule models.AlertRule) (bool, string) {
-	// Парсим имя метрики: тип.имя.поле
+	// Парсим имя метрики: тип.имя.поле. Имя объекта может содержать точки
+	// (system.slice/nginx.service), поэтому тип - первая часть, поле - последняя, имя - все между ними
 	parts := strings.Split(rule.MetricName, ".")
 	l := len(parts)
-	if l < 2 || l > 3 {
+	if l < 2 {
 		return false, "invalid metric name"
 	}
 
 	metricType := parts[0]
-	var objectName, fieldName string
-	if l == 2 {
-		fieldName = parts[1]
-	} else {
-		objectName = parts[1]
-		fieldName = parts[2]
+	fieldName := parts[l-1]
+	objectName := strings.Join(parts[1:l-1], ".")
+
+	// Пустая секция коллектора без данных не проверяется, иначе правило сравнивалось бы с нулями
+	collector := metricCollectors[metricType]
+	if metricType == "system" && strings.HasPrefix(objectName, "psi.") {
+		collector = "psi"
+	}
+	if collector != "" && !metrics.CollectorReported(collector) {
+		return false, "no data from collector " + collector
 	}
 
 	switch metricType {
 	case "system":
+		if strings.HasPrefix(objectName, "psi.") {
+			return s.evaluatePSIMetric(metrics.SystemMetrics.PSI, rule, strings.TrimPrefix(objectName, "psi."), fieldName)
+		}
 		return s.evaluateSystemMetric(metrics.SystemMetrics, rule, fieldName)
 	case "process":
-		return s.evaluateProcessMetric(metrics.ProcessesInfo, rule, objectName, fieldName)
+		return s.evaluateProcessMetric(metrics.ProcessesInfo, metrics.ProcessGroups, rule, objectName, fieldName)
 	case "container":
 		return s.evaluateContainerMetric(metrics.ContainersInfo, rule, objectName, fieldName)
+	case "systemd":
+		return s.evaluateSystemdMetric(metrics.SystemdUnits, rule, objectName, fieldName)
+	case "probe":
+		return s.evaluateProbeMetric(metrics.Probes, rule, objectName, fieldName)
+	case "custom":
+		return s.evaluateCustomMetric(metrics.Custom, rule, objectName, fieldName)
+	case "sensor":
+		return s.evaluateSensorMetric(metrics.Sensors, rule, objectName, fieldName)
+	case "log":
+		return s.evaluateLogMetric(metrics.Logs, rule, objectName, fieldName)
+	case "cgroup":
+		return s.evaluateCgroupMetric(metrics.CgroupsInfo, rule, objectName, fieldName)
 	case "network":
 		return s.evaluateNetworkMetric(metrics.PortsInfo, rule, objectName, fieldName)
+	case "fim":
+		return s.evaluateFIMMetric(metrics.Events, metrics.Timestamp, rule, objectName, fieldName)
+	case "kernel":
+		return s.evaluateEventCount(metrics.Events, metrics.Timestamp, "kernel", kernelEventCounters, rule, objectName, fieldName)
+	case "auth":
+		return s.evaluateEventCount(metrics.Events, metrics.Timestamp, "auth", authEventCounters, rule, objectName, fieldName)
 	default:
 		return false, "unknown metric type"
 	}
@@ -182,58 +225,649 @@ func (s *AlertNotifierService) evaluateSystemMetric(system models.SystemDetails,
 	return s.compare(value, rule), current
 }
 
-func (s *AlertNotifierService) evaluateProcessMetric(processes []models.ProcessInfo, rule models.AlertRule, processName, fieldName string) (bool, string) {
+func (s *AlertNotifierService) evaluateProcessMetric(processes []models.ProcessInfo, groups []models.ProcessGroupInfo, rule models.AlertRule, processName, fieldName string) (bool, string) {
+	// Суммарные показатели считаем по группе, чтобы учитывать все воркеры сервиса
+	for _, group := range groups {
+		if group.Name == processName {
+			if value, current, ok := processGroupField(group, fieldName); ok {
+				return s.compare(value, rule), current
+			}
+			break
+		}
+	}
+
+	// Показатели отдельных экземпляров: алерт срабатывает, если условие выполнено хотя бы для одного
+	found := false
+	current := ""
 	for _, proc := range processes {
-		if proc.Name == processName {
-			var value float64
-			var current string
+		if proc.Alias != processName && (proc.Alias != "" || proc.Name != processName) {
+			continue
+		}
+		found = true
 
-			switch fieldName {
-			case "cpu_percent":
-				value = proc.CPUPercent
-				current = fmt.Sprintf("%.2f%%", value)
-			case "memory_mb":
-				value = proc.MemoryMB
-				current = fmt.Sprintf("%.2fMB", value)
-			default:
-				return false, "unknown process metric"
-			}
+		value, procCurrent, ok := processField(proc, fieldName)
+		if !ok {
+			return false, "unknown process metric"
+		}
+		current = procCurrent
+		if s.compare(value, rule) {
+			return true, fmt.Sprintf("%s (pid %d)", procCurrent, proc.PID)
+		}
+	}
 
-			return s.compare(value, rule), current
+	if !found {
+		return false, "process not found"
+	}
+	return false, current
+}
+
+// processGroupField возвращает значение суммарного показателя группы процессов
+func processGroupField(group models.ProcessGroupInfo, fieldName string) (float64, string, bool) {
+	switch fieldName {
+	case "count":
+		return float64(group.Count), strconv.Itoa(group.Count), true
+	case "restarts_10m":
+		return float64(group.Restarts10m), strconv.Itoa(group.Restarts10m), true
+	case "cpu_percent":
+		return group.CPUPercent, fmt.Sprintf("%.2f%%", group.CPUPercent), true
+	case "mem_percent", "memory_percent":
+		return group.MemPercent, fmt.Sprintf("%.2f%%", group.MemPercent), true
+	case "memory_mb":
+		return group.MemoryMB, fmt.Sprintf("%.2fMB", group.MemoryMB), true
+	case "rss_bytes":
+		return float64(group.RSSBytes), fmt.Sprintf("%dB", group.RSSBytes), true
+	case "vms_bytes":
+		return float64(group.VMSBytes), fmt.Sprintf("%dB", group.VMSBytes), true
+	case "num_threads":
+		return float64(group.NumThreads), strconv.Itoa(group.NumThreads), true
+	case "num_fds":
+		return float64(group.NumFDs), strconv.Itoa(group.NumFDs), true
+	case "read_bytes_per_sec":
+		return group.ReadBytesPerSec, fmt.Sprintf("%.0fB/s", group.ReadBytesPerSec), true
+	case "write_bytes_per_sec":
+		return group.WriteBytesPerSec, fmt.Sprintf("%.0fB/s", group.WriteBytesPerSec), true
+	default:
+		return 0, "", false
+	}
+}
+
+// processField возвращает значение показателя отдельного процесса
+func processField(proc models.ProcessInfo, fieldName string) (float64, string, bool) {
+	switch fieldName {
+	case "cpu_percent":
+		return proc.CPUPercent, fmt.Sprintf("%.2f%%", proc.CPUPercent), true
+	case "mem_percent", "memory_percent":
+		return proc.MemPercent, fmt.Sprintf("%.2f%%", proc.MemPercent), true
+	case "memory_mb":
+		return proc.MemoryMB, fmt.Sprintf("%.2fMB", proc.MemoryMB), true
+	case "rss_bytes":
+		return float64(proc.RSSBytes), fmt.Sprintf("%dB", proc.RSSBytes), true
+	case "vms_bytes":
+		return float64(proc.VMSBytes), fmt.Sprintf("%dB", proc.VMSBytes), true
+	case "num_threads":
+		return float64(proc.NumThreads), strconv.Itoa(proc.NumThreads), true
+	case "num_fds":
+		return float64(proc.NumFDs), strconv.Itoa(proc.NumFDs), true
+	case "fd_usage_percent":
+		if proc.FDLimit == 0 {
+			return 0, "unlimited", true
 		}
+		value := float64(proc.NumFDs) / float64(proc.FDLimit) * 100
+		return value, fmt.Sprintf("%.2f%% (%d/%d)", value, proc.NumFDs, proc.FDLimit), true
+	case "read_bytes_per_sec":
+		return proc.ReadBytesPerSec, fmt.Sprintf("%.0fB/s", proc.ReadBytesPerSec), true
+	case "write_bytes_per_sec":
+		return proc.WriteBytesPerSec, fmt.Sprintf("%.0fB/s", proc.WriteBytesPerSec), true
+	case "uptime_seconds":
+		return proc.UptimeSeconds, fmt.Sprintf("%.0fs", proc.UptimeSeconds), true
+	case "ppid":
+		return float64(proc.PPID), strconv.Itoa(proc.PPID), true
+	case "state":
+		// Преобразуем состояние в числовое значение: 1 = процесс жив, 0 = zombie/stopped/dead
+		switch proc.State {
+		case "running", "sleeping", "disk-sleep", "idle":
+			return 1, proc.State, true
+		default:
+			return 0, proc.State, true
+		}
+	default:
+		return 0, "", false
 	}
-	return false, "process not found"
 }
 
+// evaluateContainerMetric проверяет показатель контейнера по имени или псевдониму правила отбора.
+// Под псевдоним может попадать несколько реплик: count - число работающих реплик,
+// для остальных показателей правило срабатывает, если условие выполняется хотя бы для одной реплики.
 func (s *AlertNotifierService) evaluateContainerMetric(containers []models.ContainerInfo, rule models.AlertRule, containerName, fieldName string) (bool, string) {
+	var matched []models.ContainerInfo
 	for _, cont := range containers {
-		if cont.Name == containerName {
-			var value float64
-			var current string
+		if cont.Alias == containerName || (cont.Alias == "" && cont.Name == containerName) {
+			matched = append(matched, cont)
+		}
+	}
 
-			switch fieldName {
-			case "cpu_percent":
-				value = cont.CPUPercent
-				current = fmt.Sprintf("%.2f%%", value)
-			case "memory_percent":
-				value = cont.MemoryPercent
-				current = fmt.Sprintf("%.2f%%", value)
-			case "status":
-				// Преобразуем статус в числовое значение
-				if cont.Status == "running" {
-					value = 1
-				} else {
-					value = 0
-				}
-				current = cont.Status
-			default:
-				return false, "unknown container metric"
+	if fieldName == "count" {
+		running := 0
+		for _, cont := range matched {
+			if cont.Status == "running" {
+				running++
 			}
+		}
+		return s.compare(float64(running), rule), strconv.Itoa(running)
+	}
 
-			return s.compare(value, rule), current
+	if len(matched) == 0 {
+		return false, "container not found"
+	}
+
+	var current string
+	for _, cont := range matched {
+		value, valueStr, ok := containerField(cont, fieldName)
+		if !ok {
+			return false, "unknown container metric"
+		}
+		if s.compare(value, rule) {
+			return true, fmt.Sprintf("%s (%s)", valueStr, cont.Name)
+		}
+		current = valueStr
+	}
+	return false, current
+}
+
+// containerField возвращает значение показателя контейнера
+func containerField(cont models.ContainerInfo, fieldName string) (float64, string, bool) {
+	switch fieldName {
+	case "cpu_percent":
+		return cont.CPUPercent, fmt.Sprintf("%.2f%%", cont.CPUPercent), true
+	case "mem_percent", "memory_percent":
+		return cont.MemoryPercent, fmt.Sprintf("%.2f%%", cont.MemoryPercent), true
+	case "status":
+		// Преобразуем статус в числовое значение
+		if cont.Status == "running" {
+			return 1, cont.Status, true
+		}
+		return 0, cont.Status, true
+	case "health":
+		// 1 = healthy, 0 = unhealthy/starting; контейнер без healthcheck считается здоровым
+		switch cont.Health {
+		case "healthy", "":
+			return 1, cont.Health, true
+		default:
+			return 0, cont.Health, true
+		}
+	case "memory_usage_bytes":
+		return float64(cont.MemoryUsageBytes), fmt.Sprintf("%dB", cont.MemoryUsageBytes), true
+	case "memory_limit_bytes":
+		return float64(cont.MemoryLimitBytes), fmt.Sprintf("%dB", cont.MemoryLimitBytes), true
+	case "memory_cache_bytes":
+		return float64(cont.MemoryCacheBytes), fmt.Sprintf("%dB", cont.MemoryCacheBytes), true
+	case "memory_rss_bytes":
+		return float64(cont.MemoryRSSBytes), fmt.Sprintf("%dB", cont.MemoryRSSBytes), true
+	case "net_rx_bytes":
+		return float64(cont.NetRxBytes), fmt.Sprintf("%dB", cont.NetRxBytes), true
+	case "net_tx_bytes":
+		return float64(cont.NetTxBytes), fmt.Sprintf("%dB", cont.NetTxBytes), true
+	case "net_rx_bytes_per_sec":
+		return cont.NetRxBytesPerSec, fmt.Sprintf("%.0fB/s", cont.NetRxBytesPerSec), true
+	case "net_tx_bytes_per_sec":
+		return cont.NetTxBytesPerSec, fmt.Sprintf("%.0fB/s", cont.NetTxBytesPerSec), true
+	case "block_read_bytes":
+		return float64(cont.BlockReadBytes), fmt.Sprintf("%dB", cont.BlockReadBytes), true
+	case "block_write_bytes":
+		return float64(cont.BlockWriteBytes), fmt.Sprintf("%dB", cont.BlockWriteBytes), true
+	case "block_read_bytes_per_sec":
+		return cont.BlockReadBytesPerSec, fmt.Sprintf("%.0fB/s", cont.BlockReadBytesPerSec), true
+	case "block_write_bytes_per_sec":
+		return cont.BlockWriteBytesPerSec, fmt.Sprintf("%.0fB/s", cont.BlockWriteBytesPerSec), true
+	case "pids":
+		return float64(cont.PIDs), strconv.FormatUint(cont.PIDs, 10), true
+	case "restart_count":
+		return float64(cont.RestartCount), strconv.Itoa(cont.RestartCount), true
+	case "exit_code":
+		return float64(cont.ExitCode), strconv.Itoa(cont.ExitCode), true
+	case "oom_killed":
+		if cont.OOMKilled {
+			return 1, "true", true
+		}
+		return 0, "false", true
+	case "uptime_seconds":
+		return cont.UptimeSeconds, fmt.Sprintf("%.0fs", cont.UptimeSeconds), true
+	default:
+		return 0, "", false
+	}
+}
+
+// evaluateSystemdMetric проверяет показатель systemd-юнита по его полному имени (systemd.nginx.service.active).
+// Юнит без суффикса типа ищется как .service
+func (s *AlertNotifierService) evaluateSystemdMetric(units []models.SystemdUnitInfo, rule models.AlertRule, unitName, fieldName string) (bool, string) {
+	for _, unit := range units {
+		if unit.Name != unitName && unit.Name != unitName+".service" {
+			continue
+		}
+		value, current, ok := systemdUnitField(unit, fieldName)
+		if !ok {
+			return false, "unknown systemd metric"
+		}
+		return s.compare(value, rule), current
+	}
+	// Неизвестный агенту юнит считаем неактивным, чтобы правило active == 0 сработало
+	if fieldName == "active" {
+		return s.compare(0, rule), "not found"
+	}
+	return false, "unit not found"
+}
+
+// systemdUnitField возвращает значение показателя systemd-юнита
+func systemdUnitField(unit models.SystemdUnitInfo, fieldName string) (float64, string, bool) {
+	switch fieldName {
+	case "active":
+		// 1 = active, 0 = inactive/failed/activating/...
+		if unit.ActiveState == "active" {
+			return 1, unit.ActiveState + "/" + unit.SubState, true
+		}
+		return 0, unit.ActiveState + "/" + unit.SubState, true
+	case "failed":
+		if unit.ActiveState == "failed" {
+			return 1, unit.ActiveState + "/" + unit.Result, true
 		}
+		return 0, unit.ActiveState, true
+	case "n_restarts", "restarts":
+		return float64(unit.NRestarts), strconv.FormatUint(unit.NRestarts, 10), true
+	case "main_pid":
+		return float64(unit.MainPID), strconv.Itoa(unit.MainPID), true
+	case "memory_bytes":
+		return float64(unit.MemoryBytes), fmt.Sprintf("%dB", unit.MemoryBytes), true
+	case "cpu_percent":
+		return unit.CPUPercent, fmt.Sprintf("%.2f%%", unit.CPUPercent), true
+	case "tasks_current":
+		return float64(unit.TasksCurrent), strconv.FormatUint(unit.TasksCurrent, 10), true
+	case "uptime_seconds":
+		return unit.UptimeSeconds, fmt.Sprintf("%.0fs", unit.UptimeSeconds), true
+	default:
+		return 0, "", false
+	}
+}
+
+// evaluateProbeMetric проверяет результат проверки доступности (probe.<имя>.up)
+func (s *AlertNotifierService) evaluateProbeMetric(probes []models.ProbeResult, rule models.AlertRule, name, fieldName string) (bool, string) {
+	for _, p := range probes {
+		if p.Name != name {
+			continue
+		}
+		var value float64
+		var current string
+		switch fieldName {
+		case "up":
+			// 1 = сервис доступен, 0 = недоступен
+			current = "up"
+			if p.Up {
+				value = 1
+			} else {
+				current = "down: " + p.Error
+			}
+		case "latency_ms":
+			value = p.LatencyMs
+			current = fmt.Sprintf("%.1f ms", value)
+		case "status_code":
+			value = float64(p.StatusCode)
+			current = strconv.Itoa(p.StatusCode)
+		case "cert_days_left":
+			if p.CertExpiry == nil {
+				return false, "no certificate"
+			}
+			value = p.CertDaysLeft
+			current = fmt.Sprintf("%.1f days", value)
+		default:
+			return false, "unknown probe metric"
+		}
+		return s.compare(value, rule), current
+	}
+	// Проверку, результата которой нет, считаем неуспешной, чтобы правило up == 0 сработало
+	if fieldName == "up" {
+		return s.compare(0, rule), "not found"
+	}
+	return false, "probe not found"
+}
+
+// evaluateCustomMetric проверяет показатель плагина (custom.<имя>.value или custom.<имя>.status).
+// Показатели с одним именем и разными метками проверяются все: правило срабатывает, если условие выполняется хотя бы для одного
+func (s *AlertNotifierService) evaluateCustomMetric(metrics []models.CustomMetric, rule models.AlertRule, name, fieldName string) (bool, string) {
+	found := false
+	var current string
+	for _, m := range metrics {
+		if m.Name != name {
+			continue
+		}
+		found = true
+
+		var value float64
+		switch fieldName {
+		case "value":
+			value = m.Value
+			current = strconv.FormatFloat(m.Value, 'f', -1, 64)
+		case "status":
+			value = float64(m.Status)
+			current = strconv.Itoa(m.Status)
+		default:
+			return false, "unknown custom metric field"
+		}
+		if m.Message != "" {
+			current += " (" + m.Message + ")"
+		}
+		if s.compare(value, rule) {
+			return true, current
+		}
+	}
+	if !found {
+		return false, "custom metric not found"
+	}
+	return false, current
+}
+
+// evaluateSensorMetric проверяет показание датчика (sensor.<chip>.<label>, например sensor.coretemp.package_id_0)
+func (s *AlertNotifierService) evaluateSensorMetric(sensors []models.SensorReading, rule models.AlertRule, chip, label string) (bool, string) {
+	for _, sensor := range sensors {
+		if sensor.Chip != chip || sensor.Label != label {
+			continue
+		}
+		var current string
+		switch sensor.Type {
+		case "temperature":
+			current = fmt.Sprintf("%.1f°C", sensor.Value)
+		case "fan":
+			current = fmt.Sprintf("%.0f RPM", sensor.Value)
+		case "voltage":
+			current = fmt.Sprintf("%.3f V", sensor.Value)
+		default:
+			current = strconv.FormatFloat(sensor.Value, 'f', -1, 64)
+		}
+		return s.compare(sensor.Value, rule), current
+	}
+	return false, "sensor not found"
+}
+
+// logWindows - окна, за которые агент считает совпадения шаблонов журналов
+var logWindows = map[time.Duration]string{
+	time.Minute:      "1m",
+	5 * time.Minute:  "5m",
+	15 * time.Minute: "15m",
+	time.Hour:        "1h",
+}
+
+// evaluateLogMetric проверяет число совпадений шаблона журнала за окно, заданное суффиксом поля так же,
+// как в evaluateFIMMetric: log.<журнал>.<шаблон>_5m. Агент считает окна 1m, 5m, 15m и 1h; без суффикса окно - час.
+// Кроме имен шаблонов доступно поле lines - число прочитанных строк
+func (s *AlertNotifierService) evaluateLogMetric(logs []models.LogFileInfo, rule models.AlertRule, logName, fieldName string) (bool, string) {
+	pattern, period, err := parseEventWindow(fieldName)
+	if err != nil {
+		return false, err.Error()
+	}
+	label, ok := logWindows[period]
+	if !ok {
+		return false, "log window must be 1m, 5m, 15m or 1h"
+	}
+
+	for _, l := range logs {
+		if l.Name != logName {
+			continue
+		}
+		window, ok := l.Windows[label]
+		if !ok {
+			return false, "log window not reported by agent"
+		}
+		count, ok := window.Counts[pattern]
+		if !ok {
+			if pattern != "lines" {
+				return false, "unknown log pattern"
+			}
+			count = window.Lines
+		}
+		current := strconv.Itoa(count)
+		if samples := l.Samples[pattern]; len(samples) > 0 {
+			current = fmt.Sprintf("%d (%s)", count, samples[0])
+		}
+		return s.compare(float64(count), rule), current
+	}
+	return false, "log not found"
+}
+
+// evaluateFIMMetric проверяет число изменений контролируемых файлов за окно, заданное суффиксом поля так же,
+// как в evaluateEventCount: fim.changes_5m по всем файлам или fim.<путь или шаблон>.<changes|added|removed|modified>_<окно>.
+// Без суффикса окно - час, в течение которого агент повторяет события
+func (s *AlertNotifierService) evaluateFIMMetric(events []models.Event, now time.Time, rule models.AlertRule, object, fieldName string) (bool, string) {
+	counter, window, err := parseEventWindow(fieldName)
+	if err != nil {
+		return false, err.Error()
+	}
+	if counter != "changes" && counter != "added" && counter != "removed" && counter != "modified" {
+		return false, "unknown fim field"
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	count := 0
+	var last string
+	for _, e := range events {
+		if e.Source != "fim" || (counter != "changes" && e.Type != counter) || e.Timestamp.Before(now.Add(-window)) {
+			continue
+		}
+		if object != "" && e.Object != object {
+			if ok, _ := path.Match(object, e.Object); !ok {
+				continue
+			}
+		}
+		count++
+		last = e.Type + " " + e.Object
+	}
+
+	current := strconv.Itoa(count)
+	if last != "" {
+		current = fmt.Sprintf("%d (%s)", count, last)
+	}
+	return s.compare(float64(count), rule), current
+}
+
+// kernelEventCounters сопоставляет счетчики правил kernel.* типам событий журнала ядра
+var kernelEventCounters = map[string]string{
+	"oom_kills":  "oom_kill",
+	"segfaults":  "segfault",
+	"hung_tasks": "hung_task",
+	"fs_errors":  "fs_error",
+	"io_errors":  "io_error",
+}
+
+// authEventCounters сопоставляет счетчики правил auth.* типам событий входа. Объект ssh_failed -
+// адрес источника (auth.1.2.3.4.ssh_failed_5m), объект ssh_accepted и login - пользователь
+var authEventCounters = map[string]string{
+	"ssh_failed":   "ssh_failed",
+	"ssh_accepted": "ssh_accepted",
+	"logins":       "login",
+}
+
+// evaluateEventCount считает события источника за окно, заданное суффиксом поля: kernel.oom_kills_5m,
+// auth.ssh_failed_5m. Агент повторяет события в течение часа, поэтому окно не больше часа;
+// без суффикса считаются все повторяемые события. Объект, если задан, сравнивается с объектом события.
+// Агент сводит повторяющиеся события в одно с атрибутом count или suppressed - они учитываются в сумме
+func (s *AlertNotifierService) evaluateEventCount(events []models.Event, now time.Time, source string, counters map[string]string, rule models.AlertRule, object, fieldName string) (bool, string) {
+	counter, window, err := parseEventWindow(fieldName)
+	if err != nil {
+		return false, err.Error()
+	}
+	eventType, ok := counters[counter]
+	if !ok {
+		return false, "unknown " + source + " counter"
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	count := 0
+	var last string
+	for _, e := range events {
+		if e.Source != source || e.Type != eventType || e.Timestamp.Before(now.Add(-window)) {
+			continue
+		}
+		if object != "" && e.Object != object {
+			continue
+		}
+		count += eventWeight(e)
+		last = e.Object
+	}
+
+	current := strconv.Itoa(count)
+	if last != "" {
+		current = fmt.Sprintf("%d (last: %s)", count, last)
+	}
+	return s.compare(float64(count), rule), current
+}
+
+// parseEventWindow отделяет от поля суффикс окна: oom_kills_5m - счетчик oom_kills за 5 минут.
+// Без суффикса окно - час, в течение которого агент повторяет события
+func parseEventWindow(fieldName string) (string, time.Duration, error) {
+	counter, window := fieldName, time.Hour
+	if i := strings.LastIndex(fieldName, "_"); i > 0 {
+		if d, err := time.ParseDuration(fieldName[i+1:]); err == nil {
+			counter, window = fieldName[:i], d
+		}
+	}
+	if window <= 0 || window > time.Hour {
+		return "", 0, errors.New("event window must be between 0 and 1h")
+	}
+	return counter, window, nil
+}
+
+// eventWeight - число случаев, которые представляет событие
+func eventWeight(e models.Event) int {
+	if n, err := strconv.Atoi(e.Attributes["count"]); err == nil && n > 0 {
+		return n
+	}
+	n, _ := strconv.Atoi(e.Attributes["suppressed"])
+	return 1 + n
+}
+
+// evaluatePSIMetric проверяет показатель PSI: ресурс cpu, memory или io,
+// поле - <some|full>_<avg10|avg60|avg300|total>, например system.psi.memory.full_avg60
+func (s *AlertNotifierService) evaluatePSIMetric(psi *models.PSIInfo, rule models.AlertRule, resource, fieldName string) (bool, string) {
+	if psi == nil {
+		return false, "psi not available"
+	}
+
+	var res models.PSIResource
+	switch resource {
+	case "cpu":
+		res = psi.CPU
+	case "memory":
+		res = psi.Memory
+	case "io":
+		res = psi.IO
+	default:
+		return false, "unknown psi resource"
+	}
+
+	line, avg, ok := strings.Cut(fieldName, "_")
+	if !ok {
+		return false, "unknown psi metric"
+	}
+	var stats models.PSIStats
+	switch line {
+	case "some":
+		stats = res.Some
+	case "full":
+		stats = res.Full
+	default:
+		return false, "unknown psi metric"
+	}
+
+	var value float64
+	var current string
+	switch avg {
+	case "avg10":
+		value = stats.Avg10
+	case "avg60":
+		value = stats.Avg60
+	case "avg300":
+		value = stats.Avg300
+	case "total":
+		value = float64(stats.Total)
+		current = fmt.Sprintf("%dus", stats.Total)
+	default:
+		return false, "unknown psi metric"
+	}
+	if current == "" {
+		current = fmt.Sprintf("%.2f%%", value)
+	}
+
+	return s.compare(value, rule), current
+}
+
+// evaluateCgroupMetric проверяет показатель cgroup по имени (псевдониму) или пути.
+// PSI cgroup адресуется как cgroup.<имя>.psi.<ресурс>.<поле>
+func (s *AlertNotifierService) evaluateCgroupMetric(cgroups []models.CgroupInfo, rule models.AlertRule, cgroupName, fieldName string) (bool, string) {
+	psiResource := ""
+	if i := strings.LastIndex(cgroupName, ".psi."); i >= 0 {
+		psiResource = cgroupName[i+len(".psi."):]
+		cgroupName = cgroupName[:i]
+	}
+
+	for _, cg := range cgroups {
+		if cg.Name != cgroupName && cg.Path != cgroupName {
+			continue
+		}
+		if psiResource != "" {
+			return s.evaluatePSIMetric(cg.PSI, rule, psiResource, fieldName)
+		}
+		value, current, ok := cgroupField(cg, fieldName)
+		if !ok {
+			return false, "unknown cgroup metric"
+		}
+		return s.compare(value, rule), current
+	}
+	return false, "cgroup not found"
+}
+
+// cgroupField возвращает значение показателя cgroup
+func cgroupField(cg models.CgroupInfo, fieldName string) (float64, string, bool) {
+	switch fieldName {
+	case "cpu_percent":
+		return cg.CPUPercent, fmt.Sprintf("%.2f%%", cg.CPUPercent), true
+	case "cpu_usage_seconds":
+		return cg.CPUUsageSeconds, fmt.Sprintf("%.0fs", cg.CPUUsageSeconds), true
+	case "nr_throttled":
+		return float64(cg.NrThrottled), strconv.FormatUint(cg.NrThrottled, 10), true
+	case "throttled_usec":
+		return float64(cg.ThrottledUsec), strconv.FormatUint(cg.ThrottledUsec, 10), true
+	case "throttled_percent":
+		return cg.ThrottledPercent, fmt.Sprintf("%.2f%%", cg.ThrottledPercent), true
+	case "throttled_seconds_per_sec":
+		return cg.ThrottledSecondsPerSec, fmt.Sprintf("%.3fs/s", cg.ThrottledSecondsPerSec), true
+	case "memory_current":
+		return float64(cg.MemoryCurrent), fmt.Sprintf("%dB", cg.MemoryCurrent), true
+	case "memory_max":
+		return float64(cg.MemoryMax), fmt.Sprintf("%dB", cg.MemoryMax), true
+	case "memory_usage_percent":
+		return cg.MemoryUsagePercent, fmt.Sprintf("%.2f%%", cg.MemoryUsagePercent), true
+	case "oom":
+		return float64(cg.OOM), strconv.FormatUint(cg.OOM, 10), true
+	case "oom_kill":
+		return float64(cg.OOMKill), strconv.FormatUint(cg.OOMKill, 10), true
+	case "oom_kill_delta":
+		return float64(cg.OOMKillDelta), strconv.FormatUint(cg.OOMKillDelta, 10), true
+	case "io_read_bytes_per_sec":
+		return cg.IOReadBytesPerSec, fmt.Sprintf("%.0fB/s", cg.IOReadBytesPerSec), true
+	case "io_write_bytes_per_sec":
+		return cg.IOWriteBytesPerSec, fmt.Sprintf("%.0fB/s", cg.IOWriteBytesPerSec), true
+	case "pids_current":
+		return float64(cg.PIDsCurrent), strconv.FormatUint(cg.PIDsCurrent, 10), true
+	case "pids_usage_percent":
+		if cg.PIDsMax == 0 {
+			return 0, "unlimited", true
+		}
+		value := float64(cg.PIDsCurrent) / float64(cg.PIDsMax) * 100
+		return value, fmt.Sprintf("%.2f%% (%d/%d)", value, cg.PIDsCurrent, cg.PIDsMax), true
+	default:
+		return 0, "", false
 	}
-	return false, "container not found"
 }
 
 func (s *AlertNotifierService) evaluateNetworkMetric(ports []models.PortInfo, rule models.AlertRule, portStr, fieldName string) (bool, string) {
@@ -322,7 +956,7 @@ func SendEmailAlert(cfg config.EmailConfig, subject, message string) error {
 	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
 	//log.Println("              auth:", auth, ", addr:", addr, ", body:", body, "to:", to)
 	if err := smtp.SendMail(addr, auth, from, cfg.To, []byte(body)); err != nil {
-		log.Printf("failed to send email: %w", err)
+		log.Printf("failed to send email: %v", err)
 		return err
 	}
 	return nil
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"center/internal/models"
+	"testing"
+	"time"
+)
+
+func TestEvaluateFIMMetric(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []models.Event{
+		{EventID: "1", Source: "fim", Type: "modified", Object: "/etc/sudoers", Timestamp: now.Add(-50 * time.Minute)},
+		{EventID: "2", Source: "fim", Type: "added", Object: "/etc/sudoers.d/deploy", Timestamp: now.Add(-3 * time.Minute)},
+		{EventID: "3", Source: "fim", Type: "modified", Object: "/etc/ssh/sshd_config", Timestamp: now.Add(-30 * time.Second)},
+		{EventID: "4", Source: "kernel", Type: "oom_kill", Object: "java", Timestamp: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		metric    string
+		triggered bool // правило "> 0" срабатывает
+		current   string
+	}{
+		{metric: "fim.changes", triggered: true, current: "3 (modified /etc/ssh/sshd_config)"},
+		{metric: "fim.changes_5m", triggered: true, current: "2 (modified /etc/ssh/sshd_config)"},
+		{metric: "fim.changes_10s", current: "0"},
+		{metric: "fim./etc/sudoers.modified", triggered: true, current: "1 (modified /etc/sudoers)"},
+		// Изменение /etc/sudoers старше окна: правило больше не срабатывает на каждом опросе
+		{metric: "fim./etc/sudoers.modified_5m", current: "0"},
+		{metric: "fim./etc/sudoers.d/*.added_5m", triggered: true, current: "1 (added /etc/sudoers.d/deploy)"},
+		{metric: "fim./etc/sudoers.d/*.removed_5m", current: "0"},
+		{metric: "fim.changes_2h", current: "event window must be between 0 and 1h"},
+		{metric: "fim.renamed_5m", current: "unknown fim field"},
+	}
+
+	s := &AlertNotifierService{}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			rule := models.AlertRule{MetricName: tt.metric, Condition: ">", ThresholdValue: 0}
+			triggered, current := s.evaluateRule(&models.Metrics{Timestamp: now, Events: events}, rule)
+			if triggered != tt.triggered || current != tt.current {
+				t.Errorf("evaluate %s = %v %q, want %v %q", tt.metric, triggered, current, tt.triggered, tt.current)
+			}
+		})
+	}
+}
+
+func TestEvaluateRuleSkipsCollectorsWithoutData(t *testing.T) {
+	metrics := &models.Metrics{
+		CollectorStatus: map[string]models.CollectorRunStatus{
+			"system":  {Status: models.CollectorError, Message: "read /proc/stat"},
+			"process": {Status: models.CollectorOK},
+			"sensors": {Status: models.CollectorTimeout},
+		},
+		ProcessesInfo: []models.ProcessInfo{{Name: "nginx", PID: 42, CPUPercent: 1}},
+	}
+
+	tests := []struct {
+		metric    string
+		triggered bool
+		current   string
+	}{
+		// Нулевые показатели коллектора с ошибкой не сравниваются с порогом
+		{metric: "system.cpu.cpu_usage_percent", current: "no data from collector system"},
+		{metric: "system.psi.cpu.some_avg10", current: "no data from collector psi"},
+		{metric: "sensor.coretemp.core_0", current: "no data from collector sensors"},
+		// Отключенный коллектор не присылает состояние
+		{metric: "container.web.cpu_percent", current: "no data from collector container"},
+		{metric: "process.nginx.cpu_percent", triggered: true, current: "1.00% (pid 42)"},
+	}
+
+	s := &AlertNotifierService{}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			rule := models.AlertRule{MetricName: tt.metric, Condition: "<", ThresholdValue: 5}
+			triggered, current := s.evaluateRule(metrics, rule)
+			if triggered != tt.triggered || current != tt.current {
+				t.Errorf("evaluate %s = %v %q, want %v %q", tt.metric, triggered, current, tt.triggered, tt.current)
+			}
+		})
+	}
+
+	// Агенты без collector_status присылают все секции
+	rule := models.AlertRule{MetricName: "system.cpu.cpu_usage_percent", Condition: "<", ThresholdValue: 5}
+	if triggered, _ := s.evaluateRule(&models.Metrics{}, rule); !triggered {
+		t.Errorf("rule not evaluated for an agent without collector status")
+	}
+}
+
+func TestEvaluateLogMetric(t *testing.T) {
+	logs := []models.LogFileInfo{{
+		Name:   "nginx",
+		Counts: map[string]int{"error": 1},
+		Windows: map[string]models.LogWindow{
+			"1m":  {Lines: 40, Counts: map[string]int{"error": 1}},
+			"5m":  {Lines: 200, Counts: map[string]int{"error": 12}},
+			"15m": {Lines: 600, Counts: map[string]int{"error": 12}},
+			"1h":  {Lines: 2400, Counts: map[string]int{"error": 30}},
+		},
+		Samples: map[string][]string{"error": {"[error] upstream timed out"}},
+	}}
+
+	tests := []struct {
+		metric    string
+		triggered bool
+		current   string
+	}{
+		{metric: "log.nginx.error_1m", current: "1 ([error] upstream timed out)"},
+		{metric: "log.nginx.error_5m", triggered: true, current: "12 ([error] upstream timed out)"},
+		{metric: "log.nginx.error_300s", triggered: true, current: "12 ([error] upstream timed out)"},
+		{metric: "log.nginx.error", triggered: true, current: "30 ([error] upstream timed out)"},
+		{metric: "log.nginx.lines_15m", triggered: true, current: "600"},
+		{metric: "log.nginx.error_10m", current: "log window must be 1m, 5m, 15m or 1h"},
+		{metric: "log.nginx.warn_5m", current: "unknown log pattern"},
+		{metric: "log.billing.error_5m", current: "log not found"},
+	}
+
+	s := &AlertNotifierService{}
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			rule := models.AlertRule{MetricName: tt.metric, Condition: ">", ThresholdValue: 10}
+			triggered, current := s.evaluateRule(&models.Metrics{Logs: logs}, rule)
+			if triggered != tt.triggered || current != tt.current {
+				t.Errorf("evaluate %s = %v %q, want %v %q", tt.metric, triggered, current, tt.triggered, tt.current)
+			}
+		})
+	}
+}
@@ -6,33 +6,91 @@ import (
 	pg_repo "center/internal/database/postgres/repositories"
 	"center/internal/models"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 // HostService реализует бизнес-логику работы с хостами
 type HostService struct {
-	HostRepo      pg_repo.PostgresHostRepository
-	ProcessRepo   pg_repo.PostgresProcessRepository
-	ContainerRepo pg_repo.PostgresContainerRepository
-	AlertRepo     pg_repo.PostgresAlertRepository
-	MetricRepo    repositories.MongoMetricRepository
+	HostRepo            pg_repo.PostgresHostRepository
+	ProcessRepo         pg_repo.PostgresProcessRepository
+	ContainerRepo       pg_repo.PostgresContainerRepository
+	SystemdRepo         pg_repo.PostgresSystemdUnitRepository
+	ProbeRepo           pg_repo.PostgresProbeRepository
+	AlertRepo           pg_repo.PostgresAlertRepository
+	MetricRepo          repositories.MongoMetricRepository
+	CollectorConfigRepo pg_repo.PostgresCollectorConfigRepository
+
+	maintenanceWindows []maintenanceWindow
+	// Включение коллекторов агента из секции metrics; nil - настройки агента не меняются
+	collectorSettings []models.AgentCollectorSettings
+
+	// Хеши конфигурации агентов после последней отправки, по ID хоста
+	agentConfigMu     sync.Mutex
+	agentConfigHashes map[int]string
+	// Отложенные повторные отправки конфигурации после ошибки, по ID хоста
+	agentConfigRetries map[int]agentConfigRetry
 }
 
 func NewHostService(
 	hostRepo pg_repo.PostgresHostRepository,
 	processRepo pg_repo.PostgresProcessRepository,
 	containerRepo pg_repo.PostgresContainerRepository,
+	systemdRepo pg_repo.PostgresSystemdUnitRepository,
+	probeRepo pg_repo.PostgresProbeRepository,
 	alertRepo pg_repo.PostgresAlertRepository,
 	metricRepo repositories.MongoMetricRepository,
+	collectorConfigRepo pg_repo.PostgresCollectorConfigRepository,
 ) *HostService {
 	return &HostService{
-		HostRepo:      hostRepo,
-		ProcessRepo:   processRepo,
-		ContainerRepo: containerRepo,
-		AlertRepo:     alertRepo,
-		MetricRepo:    metricRepo,
+		HostRepo:            hostRepo,
+		ProcessRepo:         processRepo,
+		ContainerRepo:       containerRepo,
+		SystemdRepo:         systemdRepo,
+		ProbeRepo:           probeRepo,
+		AlertRepo:           alertRepo,
+		MetricRepo:          metricRepo,
+		CollectorConfigRepo: collectorConfigRepo,
+		agentConfigHashes:   make(map[int]string),
+		agentConfigRetries:  make(map[int]agentConfigRetry),
+	}
+}
+
+// SetMetricsConfig задает включение коллекторов агентов по секции metrics конфигурации
+func (s *HostService) SetMetricsConfig(cfg config.MetricsConfig) {
+	enabled := func(v bool) *bool { return &v }
+	s.collectorSettings = []models.AgentCollectorSettings{
+		{
+			Name:    "system",
+			Enabled: enabled(cfg.System.Enabled),
+			Options: map[string]bool{
+				"cpu":   cfg.System.CollectCPU,
+				"ram":   cfg.System.CollectRAM,
+				"disks": cfg.System.CollectDisks,
+			},
+		},
+		{Name: "process", Enabled: enabled(cfg.Process.Enabled)},
+		{
+			Name:    "network",
+			Enabled: enabled(cfg.Network.Enabled),
+			Options: map[string]bool{
+				"tcp": cfg.Network.MonitorTCP,
+				"udp": cfg.Network.MonitorUDP,
+			},
+		},
+		{Name: "container", Enabled: enabled(cfg.Container.Enabled)},
 	}
 }
 
@@ -139,40 +197,368 @@ func (s *HostService) electMasterHost(ctx context.Context) error {
 	return s.SetMasterHost(ctx, masterHost.ID)
 }
 
+// ValidationError - ошибка во входных данных, а не в работе БД или агента; обработчики отвечают на нее 400
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// AgentError - агент недоступен или не смог выполнить запрос; обработчики отвечают на нее 502
+type AgentError struct {
+	Err error
+}
+
+func (e *AgentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AgentError) Unwrap() error {
+	return e.Err
+}
+
 // Process Operations
-func (s *HostService) AddProcess(ctx context.Context, hostID int, processName string) (int, error) {
-	exists, err := s.ProcessRepo.Exists(ctx, hostID, processName)
+func (s *HostService) AddProcess(ctx context.Context, hostID int, input models.ProcessInput) (int, error) {
+	if err := validateProcessMatchSpec(input.ProcessMatchSpec); err != nil {
+		return 0, &ValidationError{Err: err}
+	}
+
+	exists, err := s.ProcessRepo.Exists(ctx, hostID, input.ProcessName)
 	if err != nil {
 		return 0, err
 	}
 	if exists {
-		return 0, errors.New("process already monitored")
+		return 0, &ValidationError{Err: errors.New("process already monitored")}
 	}
 
 	process := &models.Process{
-		HostID:      hostID,
-		ProcessName: processName,
+		HostID:           hostID,
+		ProcessName:      input.ProcessName,
+		ProcessMatchSpec: input.ProcessMatchSpec,
 	}
 	return s.ProcessRepo.Create(ctx, process)
 }
 
+// validateProcessMatchSpec проверяет регулярные выражения до сохранения в БД
+func validateProcessMatchSpec(spec models.ProcessMatchSpec) error {
+	if spec.NameRegex != "" {
+		if _, err := regexp.Compile(spec.NameRegex); err != nil {
+			return fmt.Errorf("invalid name_regex: %w", err)
+		}
+	}
+	if spec.CmdlineRegex != "" {
+		if _, err := regexp.Compile(spec.CmdlineRegex); err != nil {
+			return fmt.Errorf("invalid cmdline_regex: %w", err)
+		}
+	}
+	return nil
+}
+
 // Container Operations
-func (s *HostService) AddContainer(ctx context.Context, hostID int, containerName string) (int, error) {
-	exists, err := s.ContainerRepo.Exists(ctx, hostID, containerName)
+func (s *HostService) AddContainer(ctx context.Context, hostID int, input models.ContainerInput) (int, error) {
+	if err := validateContainerMatchSpec(input.ContainerMatchSpec); err != nil {
+		return 0, &ValidationError{Err: err}
+	}
+
+	exists, err := s.ContainerRepo.Exists(ctx, hostID, input.ContainerName)
 	if err != nil {
 		return 0, err
 	}
 	if exists {
-		return 0, errors.New("container already monitored")
+		return 0, &ValidationError{Err: errors.New("container already monitored")}
 	}
 
 	container := &models.Container{
-		HostID:        hostID,
-		ContainerName: containerName,
+		HostID:             hostID,
+		ContainerName:      input.ContainerName,
+		ContainerMatchSpec: input.ContainerMatchSpec,
 	}
 	return s.ContainerRepo.Create(ctx, container)
 }
 
+// validateContainerMatchSpec проверяет регулярное выражение, шаблон образа и селектор меток
+func validateContainerMatchSpec(spec models.ContainerMatchSpec) error {
+	if spec.NameRegex != "" {
+		if _, err := regexp.Compile(spec.NameRegex); err != nil {
+			return fmt.Errorf("invalid name_regex: %w", err)
+		}
+	}
+	if spec.Image != "" {
+		if _, err := path.Match(spec.Image, ""); err != nil {
+			return fmt.Errorf("invalid image pattern: %w", err)
+		}
+	}
+	for _, part := range strings.Split(spec.Label, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if key, _, _ := strings.Cut(part, "="); strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid label selector %q", part)
+		}
+	}
+	return nil
+}
+
+// Systemd Unit Operations
+func (s *HostService) AddSystemdUnit(ctx context.Context, hostID int, input models.SystemdUnitInput) (int, error) {
+	if err := validateSystemdUnitName(input.UnitName); err != nil {
+		return 0, err
+	}
+
+	exists, err := s.SystemdRepo.Exists(ctx, hostID, input.UnitName)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, errors.New("systemd unit already monitored")
+	}
+
+	unit := &models.SystemdUnit{
+		HostID:   hostID,
+		UnitName: input.UnitName,
+	}
+	return s.SystemdRepo.Create(ctx, unit)
+}
+
+// Collector Config Operations
+
+// collectorNameRe - имя коллектора агента (system, process, container и т.д.)
+var collectorNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// collectorListFields - поля настроек коллекторов, которые центр задает из своих таблиц
+// (/hosts/{id}/processes, /containers, /systemd). Они не принимаются в типизированных настройках
+// и не сохраняются в них, иначе сохраненный снимок списка перезаписывал бы актуальный при отправке на агент
+var collectorListFields = map[string]string{
+	"process":   "matchers",
+	"container": "matchers",
+	"systemd":   "units",
+}
+
+// stripCollectorListFields убирает из настроек коллектора поле, которое центр задает из своих таблиц
+func stripCollectorListFields(collector string, config json.RawMessage) (json.RawMessage, error) {
+	field, ok := collectorListFields[collector]
+	if !ok {
+		return config, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(config, &obj); err != nil {
+		return nil, err
+	}
+	if _, ok := obj[field]; !ok {
+		return config, nil
+	}
+	delete(obj, field)
+	return json.Marshal(obj)
+}
+
+// SetCollectorConfig отправляет настройки коллектора на агент и сохраняет итоговые настройки из его ответа.
+// Агент проверяет настройки по схеме и объединяет их с текущими, поэтому в БД попадают полные настройки
+// с учетом прежних изменений, а неверные не попадают вовсе
+func (s *HostService) SetCollectorConfig(ctx context.Context, host models.Host, collector string, config json.RawMessage) (*models.CollectorConfig, error) {
+	if !collectorNameRe.MatchString(collector) {
+		return nil, &ValidationError{Err: fmt.Errorf("invalid collector name %q", collector)}
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(config, &obj); err != nil || obj == nil {
+		return nil, &ValidationError{Err: errors.New("collector config must be a JSON object")}
+	}
+	if field, ok := collectorListFields[collector]; ok {
+		if _, ok := obj[field]; ok {
+			return nil, &ValidationError{Err: fmt.Errorf("%s of collector %s are set from the host lists, not from collector config", field, collector)}
+		}
+	}
+
+	var resp struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/"+collector, config, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Config) == 0 || string(resp.Config) == "null" {
+		return nil, &AgentError{Err: errors.New("agent response has no collector config")}
+	}
+	merged, err := stripCollectorListFields(collector, resp.Config)
+	if err != nil {
+		return nil, &AgentError{Err: fmt.Errorf("invalid agent response: %w", err)}
+	}
+
+	cfg := &models.CollectorConfig{
+		HostID:    host.ID,
+		Collector: collector,
+		Config:    merged,
+	}
+	if err := s.CollectorConfigRepo.Upsert(ctx, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validateSystemdUnitName проверяет имя или шаблон юнита так же, как это делает агент
+func validateSystemdUnitName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, "/ \t\n") {
+		return fmt.Errorf("invalid unit name %q", name)
+	}
+	if _, err := path.Match(name, ""); err != nil {
+		return fmt.Errorf("invalid unit pattern: %w", err)
+	}
+	return nil
+}
+
+// Probe Operations
+func (s *HostService) AddProbe(ctx context.Context, hostID int, input models.ProbeInput) (int, error) {
+	if err := validateProbe(input); err != nil {
+		return 0, err
+	}
+
+	exists, err := s.ProbeRepo.Exists(ctx, hostID, input.Name)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, errors.New("probe already exists")
+	}
+
+	probe := &models.Probe{
+		HostID:         hostID,
+		Name:           input.Name,
+		Type:           input.Type,
+		Target:         input.Target,
+		ExpectedStatus: input.ExpectedStatus,
+		BodyRegex:      input.BodyRegex,
+		TimeoutSeconds: input.TimeoutSeconds,
+		TLSSkipVerify:  input.TLSSkipVerify,
+	}
+	return s.ProbeRepo.Create(ctx, probe)
+}
+
+// validateProbe проверяет описание проверки так же, как это делает агент
+func validateProbe(input models.ProbeInput) error {
+	if input.Name == "" || strings.ContainsAny(input.Name, " \t\n") {
+		return fmt.Errorf("invalid probe name %q", input.Name)
+	}
+	switch input.Type {
+	case "http":
+		u, err := url.Parse(input.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q", input.Target)
+		}
+	case "tcp":
+		if _, _, err := net.SplitHostPort(input.Target); err != nil {
+			return fmt.Errorf("invalid address %q: %w", input.Target, err)
+		}
+	case "unix":
+		if !path.IsAbs(input.Target) {
+			return errors.New("socket path must be absolute")
+		}
+	default:
+		return fmt.Errorf("unknown probe type %q", input.Type)
+	}
+	if input.Type != "http" && (input.ExpectedStatus != 0 || input.BodyRegex != "") {
+		return errors.New("expected_status and body_regex apply only to http probes")
+	}
+	if input.BodyRegex != "" {
+		if _, err := regexp.Compile(input.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body_regex: %w", err)
+		}
+	}
+	if input.TimeoutSeconds < 0 {
+		return errors.New("timeout_seconds must not be negative")
+	}
+	return nil
+}
+
+// Inventory Operations
+
+// GetHostDetails возвращает хост вместе с последними сведениями о нем
+func (s *HostService) GetHostDetails(ctx context.Context, id int) (*models.HostDetails, error) {
+	host, err := s.HostRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if host == nil {
+		return nil, errors.New("host not found")
+	}
+
+	inventory, err := s.MetricRepo.GetHostInventory(ctx, id)
+	if err != nil {
+		log.Printf("Failed to load inventory for host %d: %v", id, err)
+	}
+
+	return &models.HostDetails{Host: *host, Inventory: inventory}, nil
+}
+
+// UpdateHostInventory сохраняет сведения о хосте и записывает в историю изменившиеся поля.
+// Агент обновляет сведения раз в час, поэтому документ перезаписывается только при новом сборе
+func (s *HostService) UpdateHostInventory(ctx context.Context, hostID int, inventory *models.HostInventory) error {
+	previous, err := s.MetricRepo.GetHostInventory(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	if previous != nil && previous.CollectedAt.Equal(inventory.CollectedAt) {
+		return nil
+	}
+
+	now := time.Now()
+	inventory.HostID = hostID
+	inventory.UpdatedAt = now
+
+	if previous != nil {
+		changes := inventoryChanges(previous, inventory)
+		for i := range changes {
+			changes[i].HostID = hostID
+			changes[i].Timestamp = now
+		}
+		if err := s.MetricRepo.SaveInventoryChanges(ctx, changes); err != nil {
+			return err
+		}
+	}
+
+	return s.MetricRepo.SaveHostInventory(ctx, inventory)
+}
+
+// inventoryChanges сравнивает сведения о хосте по полям, которые не меняются без причины.
+// Время работы меняется постоянно и в историю не попадает; смена времени загрузки означает перезагрузку
+func inventoryChanges(old, new *models.HostInventory) []models.InventoryChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"hostname", old.Hostname, new.Hostname},
+		{"os", old.OS, new.OS},
+		{"platform", old.Platform, new.Platform},
+		{"platform_version", old.PlatformVersion, new.PlatformVersion},
+		{"kernel_version", old.KernelVersion, new.KernelVersion},
+		{"arch", old.Arch, new.Arch},
+		{"cpu_model", old.CPUModel, new.CPUModel},
+		{"cpu_cores", strconv.Itoa(old.CPUCores), strconv.Itoa(new.CPUCores)},
+		{"cpu_threads", strconv.Itoa(old.CPUThreads), strconv.Itoa(new.CPUThreads)},
+		{"memory_total", strconv.FormatUint(old.MemoryTotal, 10), strconv.FormatUint(new.MemoryTotal, 10)},
+		{"virtualization", old.Virtualization, new.Virtualization},
+		{"machine_id", old.MachineID, new.MachineID},
+		{"boot_time", old.BootTime.UTC().Format(time.RFC3339), new.BootTime.UTC().Format(time.RFC3339)},
+		{"ip_addresses", strings.Join(old.IPAddresses, ","), strings.Join(new.IPAddresses, ",")},
+	}
+
+	var changes []models.InventoryChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, models.InventoryChange{
+				Field:    f.name,
+				OldValue: f.old,
+				NewValue: f.new,
+			})
+		}
+	}
+	return changes
+}
+
 // Alert Operations
 func (s *HostService) CreateAlertRule(ctx context.Context, hostID int, alertInput models.AlertInput) (int, error) {
 	rule := &models.AlertRule{
@@ -202,10 +588,63 @@ func (s *HostService) SaveContainerMetrics(ctx context.Context, metrics *models.
 	return s.MetricRepo.SaveContainerMetrics(ctx, metrics)
 }
 
+func (s *HostService) SaveCgroupMetrics(ctx context.Context, metrics *models.CgroupMetrics) error {
+	return s.MetricRepo.SaveCgroupMetrics(ctx, metrics)
+}
+
+func (s *HostService) SaveSystemdUnitMetrics(ctx context.Context, metrics *models.SystemdUnitMetrics) error {
+	return s.MetricRepo.SaveSystemdUnitMetrics(ctx, metrics)
+}
+
+func (s *HostService) SaveLogMetrics(ctx context.Context, metrics *models.LogMetrics) error {
+	return s.MetricRepo.SaveLogMetrics(ctx, metrics)
+}
+
+func (s *HostService) SaveProbeMetrics(ctx context.Context, metrics *models.ProbeMetrics) error {
+	return s.MetricRepo.SaveProbeMetrics(ctx, metrics)
+}
+
+func (s *HostService) SaveCustomMetrics(ctx context.Context, metrics *models.CustomMetrics) error {
+	return s.MetricRepo.SaveCustomMetrics(ctx, metrics)
+}
+
+// SaveCollectorStatus сохраняет состояние коллекторов агента. О коллекторах, завершившихся
+// ошибкой в этом запуске, пишется в журнал; повторы из прошлых запусков не дублируются
+func (s *HostService) SaveCollectorStatus(ctx context.Context, hostID int, metrics models.Metrics) error {
+	status := models.CollectorStatusMetrics{
+		HostID:     hostID,
+		Timestamp:  metrics.Timestamp,
+		Collectors: metrics.CollectorStatus,
+	}
+	for name, st := range metrics.CollectorStatus {
+		if st.Status == models.CollectorOK {
+			continue
+		}
+		status.Failed = append(status.Failed, name)
+		if st.Timestamp.Equal(metrics.Timestamp) {
+			log.Printf("Host %d: collector %s %s: %s", hostID, name, st.Status, st.Message)
+		}
+	}
+	sort.Strings(status.Failed)
+	return s.MetricRepo.SaveCollectorStatus(ctx, &status)
+}
+
+func (s *HostService) SaveSensorMetrics(ctx context.Context, metrics *models.SensorMetrics) error {
+	return s.MetricRepo.SaveSensorMetrics(ctx, metrics)
+}
+
+func (s *HostService) SaveAuthMetrics(ctx context.Context, metrics *models.AuthMetrics) error {
+	return s.MetricRepo.SaveAuthMetrics(ctx, metrics)
+}
+
 func (s *HostService) SaveNetworkMetrics(ctx context.Context, metrics *models.NetworkMetrics) error {
 	return s.MetricRepo.SaveNetworkMetrics(ctx, metrics)
 }
 
+func (s *HostService) SaveEvents(ctx context.Context, events []models.Event) error {
+	return s.MetricRepo.SaveEvents(ctx, events)
+}
+
 func (s *HostService) LoadInitialData(ctx context.Context, cfg *config.AppConfig) error {
 	//Проверка, есть ли уже данные
 	count, err := s.HostRepo.GetHostCount()
@@ -232,17 +671,69 @@ func (s *HostService) LoadInitialData(ctx context.Context, cfg *config.AppConfig
 
 		// Добавление процессов
 		for _, process := range hostCfg.Processes {
-			if _, err := s.AddProcess(ctx, hostID, process); err != nil {
+			if _, err := s.AddProcess(ctx, hostID, models.ProcessInput{ProcessName: process}); err != nil {
 				log.Printf("Failed to add process %s to host %s: %v", process, hostCfg.Hostname, err)
 			}
 		}
+		for _, matcher := range hostCfg.ProcessMatchers {
+			if _, err := s.AddProcess(ctx, hostID, models.ProcessInput{
+				ProcessName: matcher.Alias,
+				ProcessMatchSpec: models.ProcessMatchSpec{
+					Name:         matcher.Name,
+					NameRegex:    matcher.NameRegex,
+					Cmdline:      matcher.Cmdline,
+					CmdlineRegex: matcher.CmdlineRegex,
+					User:         matcher.User,
+					Exe:          matcher.Exe,
+					Cgroup:       matcher.Cgroup,
+					SystemdUnit:  matcher.SystemdUnit,
+				},
+			}); err != nil {
+				log.Printf("Failed to add process %s to host %s: %v", matcher.Alias, hostCfg.Hostname, err)
+			}
+		}
 
 		// Добавление контейнеров
 		for _, container := range hostCfg.Containers {
-			if _, err := s.AddContainer(ctx, hostID, container); err != nil {
+			if _, err := s.AddContainer(ctx, hostID, models.ContainerInput{ContainerName: container}); err != nil {
 				log.Printf("Failed to add container %s to host %s: %v", container, hostCfg.Hostname, err)
 			}
 		}
+		for _, matcher := range hostCfg.ContainerMatchers {
+			if _, err := s.AddContainer(ctx, hostID, models.ContainerInput{
+				ContainerName: matcher.Alias,
+				ContainerMatchSpec: models.ContainerMatchSpec{
+					Name:      matcher.Name,
+					NameRegex: matcher.NameRegex,
+					Image:     matcher.Image,
+					Label:     matcher.Label,
+				},
+			}); err != nil {
+				log.Printf("Failed to add container %s to host %s: %v", matcher.Alias, hostCfg.Hostname, err)
+			}
+		}
+
+		// Добавление systemd-юнитов
+		for _, unit := range hostCfg.SystemdUnits {
+			if _, err := s.AddSystemdUnit(ctx, hostID, models.SystemdUnitInput{UnitName: unit}); err != nil {
+				log.Printf("Failed to add systemd unit %s to host %s: %v", unit, hostCfg.Hostname, err)
+			}
+		}
+
+		// Добавление проверок доступности
+		for _, probe := range hostCfg.Probes {
+			if _, err := s.AddProbe(ctx, hostID, models.ProbeInput{
+				Name:           probe.Name,
+				Type:           probe.Type,
+				Target:         probe.Target,
+				ExpectedStatus: probe.ExpectedStatus,
+				BodyRegex:      probe.BodyRegex,
+				TimeoutSeconds: probe.TimeoutSeconds,
+				TLSSkipVerify:  probe.TLSSkipVerify,
+			}); err != nil {
+				log.Printf("Failed to add probe %s to host %s: %v", probe.Name, hostCfg.Hostname, err)
+			}
+		}
 
 		// Добавление правил оповещений
 		for _, alert := range hostCfg.Alerts {
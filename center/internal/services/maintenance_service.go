@@ -62,7 +62,17 @@ func (s *MaintenanceService) cleanupOldMetrics(ctx context.Context) {
 		"system_metrics",
 		"process_metrics",
 		"container_metrics",
+		"cgroup_metrics",
+		"systemd_metrics",
+		"log_metrics",
+		"custom_metrics",
+		"sensor_metrics",
+		"collector_status",
+		"auth_metrics",
+		"probe_metrics",
 		"network_metrics",
+		"events",
+		"security_events",
 	}
 
 	for _, collection := range collections {
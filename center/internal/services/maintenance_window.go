@@ -0,0 +1,130 @@
+package services
+
+import (
+	"center/internal/config"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maintenanceWindow - окно обслуживания с разобранными днями и временем
+type maintenanceWindow struct {
+	hosts map[string]bool       // имена хостов; пусто - все хосты
+	days  map[time.Weekday]bool // дни недели; пусто - каждый день
+	start int                   // начало в минутах от полуночи
+	end   int                   // окончание в минутах от полуночи; меньше start - окно через полночь
+	loc   *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// compileMaintenanceWindows проверяет и разбирает окна обслуживания из конфигурации
+func compileMaintenanceWindows(cfgs []config.MaintenanceWindowConfig) ([]maintenanceWindow, error) {
+	windows := make([]maintenanceWindow, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		w := maintenanceWindow{loc: time.Local}
+
+		if cfg.Timezone != "" {
+			loc, err := time.LoadLocation(cfg.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("maintenance window %d: %w", i, err)
+			}
+			w.loc = loc
+		}
+
+		var err error
+		if w.start, err = parseClock(cfg.Start); err != nil {
+			return nil, fmt.Errorf("maintenance window %d: start: %w", i, err)
+		}
+		if w.end, err = parseClock(cfg.End); err != nil {
+			return nil, fmt.Errorf("maintenance window %d: end: %w", i, err)
+		}
+
+		if len(cfg.Days) > 0 {
+			w.days = make(map[time.Weekday]bool, len(cfg.Days))
+			for _, d := range cfg.Days {
+				day, ok := weekdays[strings.ToLower(d)]
+				if !ok {
+					return nil, fmt.Errorf("maintenance window %d: unknown day %q", i, d)
+				}
+				w.days[day] = true
+			}
+		}
+
+		if len(cfg.Hosts) > 0 {
+			w.hosts = make(map[string]bool, len(cfg.Hosts))
+			for _, h := range cfg.Hosts {
+				w.hosts[h] = true
+			}
+		}
+
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// parseClock разбирает время в формате ЧЧ:ММ
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains проверяет, попадает ли момент t в окно для указанного хоста.
+// Для окна через полночь день недели относится к дню начала окна
+func (w maintenanceWindow) contains(hostname string, t time.Time) bool {
+	if w.hosts != nil && !w.hosts[hostname] {
+		return false
+	}
+
+	local := t.In(w.loc)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	switch {
+	case w.start == w.end:
+		// Окно на весь день
+	case w.start < w.end:
+		if minute < w.start || minute >= w.end {
+			return false
+		}
+	default:
+		if minute < w.start && minute >= w.end {
+			return false
+		}
+		if minute < w.end {
+			day = (day + 6) % 7
+		}
+	}
+	return w.days == nil || w.days[day]
+}
+
+// InMaintenanceWindow проверяет, находится ли хост в окне обслуживания в момент t
+func (s *HostService) InMaintenanceWindow(hostname string, t time.Time) bool {
+	for _, w := range s.maintenanceWindows {
+		if w.contains(hostname, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMaintenanceWindows задает окна обслуживания, в которые изменения хостов считаются плановыми
+func (s *HostService) SetMaintenanceWindows(cfgs []config.MaintenanceWindowConfig) error {
+	windows, err := compileMaintenanceWindows(cfgs)
+	if err != nil {
+		return err
+	}
+	s.maintenanceWindows = windows
+	return nil
+}
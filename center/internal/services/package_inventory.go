@@ -0,0 +1,295 @@
+package services
+
+import (
+	"center/internal/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Операции сравнения версий в запросе пакетов
+var packageVersionOps = map[string]func(int) bool{
+	"lt": func(c int) bool { return c < 0 },
+	"le": func(c int) bool { return c <= 0 },
+	"eq": func(c int) bool { return c == 0 },
+	"ne": func(c int) bool { return c != 0 },
+	"ge": func(c int) bool { return c >= 0 },
+	"gt": func(c int) bool { return c > 0 },
+}
+
+// UpdateHostPackages применяет отчет агента о пакетах к сохраненному списку хоста.
+// Если изменения нельзя применить (ЦМ пропустил предыдущий отчет), полный список запрашивается у агента.
+// Возвращает события об изменениях пакетов вне окон обслуживания
+func (s *HostService) UpdateHostPackages(ctx context.Context, hostID int, report *models.PackageInventory) ([]models.Event, error) {
+	stored, err := s.MetricRepo.GetHostPackages(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.Hash == report.Hash {
+		return nil, nil
+	}
+
+	packages := reportPackages(stored, report)
+	var host *models.Host
+	if packages == nil {
+		host, err = s.HostRepo.GetByID(ctx, hostID)
+		if err != nil {
+			return nil, err
+		}
+		if host == nil {
+			return nil, errors.New("host not found")
+		}
+		if report, err = s.fetchHostPackages(ctx, *host); err != nil {
+			return nil, err
+		}
+		packages = report.Packages
+	}
+
+	now := time.Now()
+	if err := s.MetricRepo.SaveHostPackages(ctx, &models.HostPackages{
+		HostID:    hostID,
+		Manager:   report.Manager,
+		Hash:      report.Hash,
+		Packages:  packages,
+		UpdatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Первый список пакетов хоста изменением не считается
+	if stored == nil {
+		return nil, nil
+	}
+	changes := diffPackages(stored.Packages, packages)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if host == nil {
+		if host, err = s.HostRepo.GetByID(ctx, hostID); err != nil || host == nil {
+			return nil, err
+		}
+	}
+	if s.InMaintenanceWindow(host.Hostname, now) {
+		return nil, nil
+	}
+	return packageChangeEvents(report.Hash, now, changes), nil
+}
+
+// reportPackages возвращает отсортированный список пакетов по отчету агента или nil, если отчет
+// нельзя применить к сохраненному списку stored или хеш результата не совпадает с хешем агента
+func reportPackages(stored *models.HostPackages, report *models.PackageInventory) []models.Package {
+	var packages []models.Package
+	switch {
+	case report.Full:
+		packages = report.Packages
+	case stored != nil && report.BaseHash == stored.Hash && len(report.Changes) > 0:
+		packages = applyPackageChanges(stored.Packages, report.Changes)
+	}
+	if packages == nil {
+		return nil
+	}
+	sortPackages(packages)
+	if packageListHash(packages) != report.Hash {
+		return nil
+	}
+	return packages
+}
+
+// fetchHostPackages запрашивает у агента полный список пакетов
+func (s *HostService) fetchHostPackages(ctx context.Context, host models.Host) (*models.PackageInventory, error) {
+	var full models.PackageInventory
+	if err := s.fetchFromAgent(ctx, host, "/packages", &full); err != nil {
+		return nil, fmt.Errorf("failed to fetch package list: %w", err)
+	}
+	sortPackages(full.Packages)
+	return &full, nil
+}
+
+// packageChangeEvents формирует события об изменениях пакетов.
+// Идентификатор события включает хеш нового списка, поэтому повторная обработка не создает дублей
+func packageChangeEvents(hash string, at time.Time, changes []models.PackageChange) []models.Event {
+	events := make([]models.Event, 0, len(changes))
+	for _, c := range changes {
+		event := models.Event{
+			EventID:    fmt.Sprintf("pkg-%s-%s-%s-%s-%s", hash[:min(len(hash), 16)], c.Name, c.Arch, c.OldVersion, c.NewVersion),
+			Timestamp:  at,
+			Source:     "packages",
+			Object:     c.Name,
+			Attributes: map[string]string{},
+		}
+		switch {
+		case c.OldVersion == "":
+			event.Type = "installed"
+			event.Message = fmt.Sprintf("Package %s %s installed", c.Name, c.NewVersion)
+		case c.NewVersion == "":
+			event.Type = "removed"
+			event.Message = fmt.Sprintf("Package %s %s removed", c.Name, c.OldVersion)
+		default:
+			event.Type = "updated"
+			event.Message = fmt.Sprintf("Package %s updated from %s to %s", c.Name, c.OldVersion, c.NewVersion)
+		}
+		if c.Arch != "" {
+			event.Attributes["arch"] = c.Arch
+		}
+		if c.OldVersion != "" {
+			event.Attributes["old_version"] = c.OldVersion
+		}
+		if c.NewVersion != "" {
+			event.Attributes["new_version"] = c.NewVersion
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// FindHostsWithPackage возвращает хосты, на которых установлен пакет name. Если задана версия,
+// остаются только установленные версии, удовлетворяющие условию op (lt, le, eq, ne, ge, gt)
+// по правилам менеджера пакетов хоста
+func (s *HostService) FindHostsWithPackage(ctx context.Context, name, op, version string) ([]models.PackageHost, error) {
+	var match func(int) bool
+	if version != "" {
+		if op == "" {
+			op = "eq"
+		}
+		var ok bool
+		if match, ok = packageVersionOps[op]; !ok {
+			return nil, fmt.Errorf("unknown version operator %q", op)
+		}
+	}
+
+	lists, err := s.MetricRepo.FindHostPackagesByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.PackageHost{}
+	for _, list := range lists {
+		var hostname string
+		if host, err := s.HostRepo.GetByID(ctx, list.HostID); err == nil && host != nil {
+			hostname = host.Hostname
+		}
+		for _, p := range list.Packages {
+			if p.Name != name {
+				continue
+			}
+			if match != nil && !match(comparePackageVersions(list.Manager, p.Version, version)) {
+				continue
+			}
+			result = append(result, models.PackageHost{
+				HostID:   list.HostID,
+				Hostname: hostname,
+				Manager:  list.Manager,
+				Name:     p.Name,
+				Version:  p.Version,
+				Arch:     p.Arch,
+			})
+		}
+	}
+	return result, nil
+}
+
+// Сортировка, хеш и сравнение списков пакетов совпадают с агентом
+
+func sortPackages(packages []models.Package) {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		if packages[i].Arch != packages[j].Arch {
+			return packages[i].Arch < packages[j].Arch
+		}
+		return packages[i].Version < packages[j].Version
+	})
+}
+
+func packageListHash(packages []models.Package) string {
+	h := sha256.New()
+	for _, p := range packages {
+		fmt.Fprintf(h, "%s\t%s\t%s\n", p.Name, p.Arch, p.Version)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func packageKey(name, arch, version string) string {
+	return name + "\x00" + arch + "\x00" + version
+}
+
+// applyPackageChanges применяет изменения к копии списка пакетов
+func applyPackageChanges(packages []models.Package, changes []models.PackageChange) []models.Package {
+	removed := make(map[string]bool)
+	for _, c := range changes {
+		if c.OldVersion != "" {
+			removed[packageKey(c.Name, c.Arch, c.OldVersion)] = true
+		}
+	}
+
+	result := make([]models.Package, 0, len(packages)+len(changes))
+	for _, p := range packages {
+		if !removed[packageKey(p.Name, p.Arch, p.Version)] {
+			result = append(result, p)
+		}
+	}
+	for _, c := range changes {
+		if c.NewVersion != "" {
+			result = append(result, models.Package{Name: c.Name, Version: c.NewVersion, Arch: c.Arch})
+		}
+	}
+	return result
+}
+
+// diffPackages сравнивает списки по имени, архитектуре и версии; пара удаление + установка
+// одного имени и архитектуры считается сменой версии
+func diffPackages(old, new []models.Package) []models.PackageChange {
+	oldSet := make(map[string]bool, len(old))
+	for _, p := range old {
+		oldSet[packageKey(p.Name, p.Arch, p.Version)] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, p := range new {
+		newSet[packageKey(p.Name, p.Arch, p.Version)] = true
+	}
+
+	type nameArch struct{ name, arch string }
+	removed := make(map[nameArch][]string)
+	added := make(map[nameArch][]string)
+	var order []nameArch
+	for _, p := range old {
+		if !newSet[packageKey(p.Name, p.Arch, p.Version)] {
+			na := nameArch{p.Name, p.Arch}
+			if removed[na] == nil && added[na] == nil {
+				order = append(order, na)
+			}
+			removed[na] = append(removed[na], p.Version)
+		}
+	}
+	for _, p := range new {
+		if !oldSet[packageKey(p.Name, p.Arch, p.Version)] {
+			na := nameArch{p.Name, p.Arch}
+			if removed[na] == nil && added[na] == nil {
+				order = append(order, na)
+			}
+			added[na] = append(added[na], p.Version)
+		}
+	}
+
+	var changes []models.PackageChange
+	for _, na := range order {
+		r, a := removed[na], added[na]
+		if len(r) == 1 && len(a) == 1 {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, OldVersion: r[0], NewVersion: a[0]})
+			continue
+		}
+		for _, v := range r {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, OldVersion: v})
+		}
+		for _, v := range a {
+			changes = append(changes, models.PackageChange{Name: na.name, Arch: na.arch, NewVersion: v})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"center/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestApplyPackageChanges(t *testing.T) {
+	packages := []models.Package{
+		{Name: "bash", Version: "5.1-6", Arch: "amd64"},
+		{Name: "kernel", Version: "5.14.0-362", Arch: "x86_64"},
+		{Name: "kernel", Version: "5.14.0-427", Arch: "x86_64"},
+		{Name: "openssl", Version: "3.0.2-0ubuntu1.10", Arch: "amd64"},
+	}
+	changes := []models.PackageChange{
+		{Name: "openssl", Arch: "amd64", OldVersion: "3.0.2-0ubuntu1.10", NewVersion: "3.0.2-0ubuntu1.12"},
+		{Name: "kernel", Arch: "x86_64", OldVersion: "5.14.0-362"},
+		{Name: "curl", Arch: "amd64", NewVersion: "7.81.0-1"},
+		// Удаление отсутствующей версии ничего не меняет
+		{Name: "bash", Arch: "i386", OldVersion: "5.1-6"},
+	}
+	got := applyPackageChanges(packages, changes)
+	sortPackages(got)
+	want := []models.Package{
+		{Name: "bash", Version: "5.1-6", Arch: "amd64"},
+		{Name: "curl", Version: "7.81.0-1", Arch: "amd64"},
+		{Name: "kernel", Version: "5.14.0-427", Arch: "x86_64"},
+		{Name: "openssl", Version: "3.0.2-0ubuntu1.12", Arch: "amd64"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("packages:\n got %+v\nwant %+v", got, want)
+	}
+	if len(packages) != 4 || packages[3].Version != "3.0.2-0ubuntu1.10" {
+		t.Errorf("stored list modified: %+v", packages)
+	}
+
+	// Изменения, полученные сравнением списков, переводят старый список в новый
+	diff := applyPackageChanges(packages, diffPackages(packages, want))
+	sortPackages(diff)
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff applied = %+v, want %+v", diff, want)
+	}
+}
+
+func TestReportPackages(t *testing.T) {
+	old := []models.Package{{Name: "bash", Version: "5.1-6"}, {Name: "curl", Version: "7.81.0-1"}}
+	updated := []models.Package{{Name: "bash", Version: "5.2-1"}, {Name: "curl", Version: "7.81.0-1"}}
+	stored := &models.HostPackages{Hash: packageListHash(old), Packages: old}
+	changes := []models.PackageChange{{Name: "bash", OldVersion: "5.1-6", NewVersion: "5.2-1"}}
+
+	tests := []struct {
+		name   string
+		stored *models.HostPackages
+		report models.PackageInventory
+		want   []models.Package // nil - нужен полный список от агента
+	}{
+		{
+			name:   "full list",
+			report: models.PackageInventory{Full: true, Hash: packageListHash(updated), Packages: []models.Package{updated[1], updated[0]}},
+			want:   updated,
+		},
+		{
+			name:   "changes",
+			stored: stored,
+			report: models.PackageInventory{Hash: packageListHash(updated), BaseHash: stored.Hash, Changes: changes},
+			want:   updated,
+		},
+		{
+			// ЦМ пропустил отчет, в котором список сменился на base_hash
+			name:   "changes to another base",
+			stored: stored,
+			report: models.PackageInventory{Hash: packageListHash(updated), BaseHash: "other", Changes: changes},
+		},
+		{
+			name:   "changes without stored list",
+			report: models.PackageInventory{Hash: packageListHash(updated), BaseHash: stored.Hash, Changes: changes},
+		},
+		{
+			name:   "hash mismatch after changes",
+			stored: stored,
+			report: models.PackageInventory{Hash: "mismatch", BaseHash: stored.Hash, Changes: changes},
+		},
+		{
+			name:   "hash mismatch of full list",
+			report: models.PackageInventory{Full: true, Hash: "mismatch", Packages: updated},
+		},
+		{
+			name:   "hash only",
+			stored: stored,
+			report: models.PackageInventory{Hash: packageListHash(updated)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := tt.report
+			if got := reportPackages(tt.stored, &report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("packages = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchHostPackages(t *testing.T) {
+	full := models.PackageInventory{
+		Manager:  "dpkg",
+		Full:     true,
+		Packages: []models.Package{{Name: "curl", Version: "7.81.0-1"}, {Name: "bash", Version: "5.2-1"}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/packages" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(full)
+	}))
+	defer srv.Close()
+
+	s := &HostService{}
+	// Хеш отчета не совпал с результатом, поэтому список запрашивается у агента
+	report := &models.PackageInventory{Hash: "mismatch", BaseHash: "base", Changes: []models.PackageChange{{Name: "bash", NewVersion: "5.2-1"}}}
+	if reportPackages(&models.HostPackages{Hash: "base"}, report) != nil {
+		t.Fatalf("report with mismatched hash applied")
+	}
+	got, err := s.fetchHostPackages(context.Background(), testAgentHost(t, srv.Listener.Addr().String()))
+	if err != nil {
+		t.Fatalf("fetchHostPackages: %v", err)
+	}
+	want := []models.Package{{Name: "bash", Version: "5.2-1"}, {Name: "curl", Version: "7.81.0-1"}}
+	if got.Manager != "dpkg" || !reflect.DeepEqual(got.Packages, want) {
+		t.Errorf("packages = %+v", got)
+	}
+
+	srv.Close()
+	if _, err := s.fetchHostPackages(context.Background(), testAgentHost(t, srv.Listener.Addr().String())); err == nil {
+		t.Errorf("fetchHostPackages succeeded with the agent down")
+	}
+}
@@ -5,12 +5,19 @@ import (
 	"center/internal/models"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 )
 
+// securityEventSources - источники событий агента, которые считаются событиями безопасности
+var securityEventSources = map[string]bool{
+	"fim":  true,
+	"auth": true,
+}
+
 // PollerService отвечает за периодический опрос агентов
 type PollerService struct {
 	hostService  *HostService
@@ -113,6 +120,15 @@ func (s *PollerService) pollHost(ctx context.Context, host models.Host) {
 	// Сохраняем метрики
 	s.hostService.ProcessHostMetrics(ctx, host.ID, metrics)
 
+	// Конфигурация отправляется при запуске ЦМ и при изменениях; агент, перезапущенный без
+	// сохраненной конфигурации или недоступный в тот момент, получает ее заново
+	if s.hostService.AgentConfigStale(host.ID, metrics.ConfigHash) {
+		log.Printf("[%s] Agent configuration is stale, sending it again", host.Hostname)
+		if err := s.hostService.SendConfigurationToAgent(ctx, host); err != nil {
+			log.Printf("[%s] Failed to send configuration: %v", host.Hostname, err)
+		}
+	}
+
 	log.Printf("[%s] Metrics collected in %v", host.Hostname, duration)
 	s.alertService.recordCheckResult(true)
 	// Вызов проверки алертов после успешного получения метрик
@@ -131,16 +147,25 @@ func (s *PollerService) updateHostStatus(ctx context.Context, hostID int, status
 
 // ProcessHostMetrics обрабатывает и сохраняет метрики хоста
 func (s *HostService) ProcessHostMetrics(ctx context.Context, hostID int, metrics models.Metrics) {
-	// Сохраняем системные метрики
-	systemMetrics := models.SystemMetrics{
-		HostID:    hostID,
-		Timestamp: metrics.Timestamp,
-		System:    metrics.SystemMetrics,
+	// Сохраняем состояние коллекторов; ошибки видны по хосту в /metrics/:host_id/collectors
+	if len(metrics.CollectorStatus) > 0 {
+		if err := s.SaveCollectorStatus(ctx, hostID, metrics); err != nil {
+			log.Printf("Error saving collector status: %v", err)
+		}
 	}
 
-	err := s.SaveSystemMetrics(ctx, &systemMetrics)
-	if err != nil {
-		log.Printf("Error saving system metrics: %v", err)
+	// Сохраняем системные метрики; нулевая секция коллектора без данных не сохраняется
+	if metrics.CollectorReported("system") {
+		systemMetrics := models.SystemMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			System:    metrics.SystemMetrics,
+		}
+
+		err := s.SaveSystemMetrics(ctx, &systemMetrics)
+		if err != nil {
+			log.Printf("Error saving system metrics: %v", err)
+		}
 	}
 
 	// Сохраняем метрики процессов
@@ -149,6 +174,7 @@ func (s *HostService) ProcessHostMetrics(ctx context.Context, hostID int, metric
 			HostID:    hostID,
 			Timestamp: metrics.Timestamp,
 			Processes: metrics.ProcessesInfo,
+			Groups:    metrics.ProcessGroups,
 		}
 		if err := s.SaveProcessMetrics(ctx, &processMetrics); err != nil {
 			log.Printf("Error saving process metrics: %v", err)
@@ -178,14 +204,217 @@ func (s *HostService) ProcessHostMetrics(ctx context.Context, hostID int, metric
 			log.Printf("Error saving container metrics: %v", err)
 		}
 	}
+
+	// Сохраняем метрики cgroup
+	if len(metrics.CgroupsInfo) > 0 {
+		cgroupMetrics := models.CgroupMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Cgroups:   metrics.CgroupsInfo,
+		}
+		if err := s.SaveCgroupMetrics(ctx, &cgroupMetrics); err != nil {
+			log.Printf("Error saving cgroup metrics: %v", err)
+		}
+	}
+
+	// Сохраняем состояние systemd-юнитов
+	if len(metrics.SystemdUnits) > 0 {
+		unitMetrics := models.SystemdUnitMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Units:     metrics.SystemdUnits,
+		}
+		if err := s.SaveSystemdUnitMetrics(ctx, &unitMetrics); err != nil {
+			log.Printf("Error saving systemd unit metrics: %v", err)
+		}
+	}
+
+	// Сохраняем счетчики журналов
+	if len(metrics.Logs) > 0 {
+		logMetrics := models.LogMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Logs:      metrics.Logs,
+		}
+		if err := s.SaveLogMetrics(ctx, &logMetrics); err != nil {
+			log.Printf("Error saving log metrics: %v", err)
+		}
+	}
+
+	// Сохраняем результаты проверок доступности
+	if len(metrics.Probes) > 0 {
+		probeMetrics := models.ProbeMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Probes:    metrics.Probes,
+		}
+		if err := s.SaveProbeMetrics(ctx, &probeMetrics); err != nil {
+			log.Printf("Error saving probe metrics: %v", err)
+		}
+	}
+
+	// Сохраняем показатели плагинов
+	if len(metrics.Custom) > 0 {
+		customMetrics := models.CustomMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Metrics:   metrics.Custom,
+		}
+		if err := s.SaveCustomMetrics(ctx, &customMetrics); err != nil {
+			log.Printf("Error saving custom metrics: %v", err)
+		}
+	}
+
+	// Сохраняем показания датчиков
+	if len(metrics.Sensors) > 0 {
+		sensorMetrics := models.SensorMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Sensors:   metrics.Sensors,
+		}
+		if err := s.SaveSensorMetrics(ctx, &sensorMetrics); err != nil {
+			log.Printf("Error saving sensor metrics: %v", err)
+		}
+	}
+
+	// Сохраняем сеансы пользователей и входы по SSH
+	if metrics.Auth != nil {
+		authMetrics := models.AuthMetrics{
+			HostID:    hostID,
+			Timestamp: metrics.Timestamp,
+			Auth:      *metrics.Auth,
+		}
+		if err := s.SaveAuthMetrics(ctx, &authMetrics); err != nil {
+			log.Printf("Error saving auth metrics: %v", err)
+		}
+	}
+
+	// Сохраняем сведения о хосте
+	if metrics.Inventory != nil {
+		if err := s.UpdateHostInventory(ctx, hostID, metrics.Inventory); err != nil {
+			log.Printf("Error saving host inventory: %v", err)
+		}
+	}
+
+	// Обновляем список пакетов; события об изменениях попадают в metrics.Events и сохраняются ниже
+	if metrics.Packages != nil {
+		events, err := s.UpdateHostPackages(ctx, hostID, metrics.Packages)
+		if err != nil {
+			log.Printf("Error updating host packages: %v", err)
+		}
+		metrics.Events = append(metrics.Events, events...)
+	}
+
+	// Сохраняем события
+	if len(metrics.Events) > 0 {
+		for i := range metrics.Events {
+			metrics.Events[i].HostID = hostID
+		}
+		if err := s.SaveEvents(ctx, metrics.Events); err != nil {
+			log.Printf("Error saving events: %v", err)
+		}
+
+		// События безопасности дополнительно сохраняются отдельно
+		var securityEvents []models.Event
+		for _, e := range metrics.Events {
+			if securityEventSources[e.Source] {
+				securityEvents = append(securityEvents, e)
+			}
+		}
+		if len(securityEvents) > 0 {
+			if err := s.MetricRepo.SaveSecurityEvents(ctx, securityEvents); err != nil {
+				log.Printf("Error saving security events: %v", err)
+			}
+		}
+	}
+}
+
+// Пауза перед повторной отправкой конфигурации при опросе после ошибки; удваивается до agentConfigRetryMax
+const (
+	agentConfigRetryMin = 30 * time.Second
+	agentConfigRetryMax = 30 * time.Minute
+)
+
+// agentConfigRetry - время следующей попытки отправить конфигурацию и текущая пауза
+type agentConfigRetry struct {
+	next  time.Time
+	delay time.Duration
 }
 
 // SendConfigurationToAgent отправляет конфигурацию на агент
 func (s *HostService) SendConfigurationToAgent(ctx context.Context, host models.Host) error {
+	if err := s.sendConfigurationSections(ctx, host); err != nil {
+		// Хеш не запоминается: агент получил не всю конфигурацию. Повтор откладывается,
+		// чтобы постоянная ошибка не повторялась при каждом опросе
+		s.delayAgentConfigRetry(host.ID, time.Now())
+		return err
+	}
+	s.rememberAgentConfigHash(ctx, host)
+	return nil
+}
+
+// sendConfigurationSections отправляет на агент все секции конфигурации, останавливаясь на первой ошибке
+func (s *HostService) sendConfigurationSections(ctx context.Context, host models.Host) error {
 	if err := s.SendProcessConfigurationToAgent(ctx, host); err != nil {
 		return err
 	}
-	return s.SendContainerConfigurationToAgent(ctx, host)
+	if err := s.SendContainerConfigurationToAgent(ctx, host); err != nil {
+		return err
+	}
+	if err := s.SendSystemdUnitConfigurationToAgent(ctx, host); err != nil {
+		return err
+	}
+	if err := s.SendProbeConfigurationToAgent(ctx, host); err != nil {
+		return err
+	}
+	if err := s.SendCollectorConfigurationToAgent(ctx, host); err != nil {
+		return err
+	}
+	return s.SendCollectorConfigsToAgent(ctx, host)
+}
+
+// rememberAgentConfigHash запоминает хеш конфигурации агента после успешной отправки
+func (s *HostService) rememberAgentConfigHash(ctx context.Context, host models.Host) {
+	var version models.AgentConfigVersion
+	if err := s.fetchFromAgent(ctx, host, "/config/version", &version); err != nil {
+		// Агент недоступен или не сохраняет конфигурацию: хеш сверяется при следующем опросе
+		return
+	}
+
+	s.agentConfigMu.Lock()
+	defer s.agentConfigMu.Unlock()
+	s.agentConfigHashes[host.ID] = version.Hash
+	delete(s.agentConfigRetries, host.ID)
+}
+
+// delayAgentConfigRetry откладывает следующую отправку конфигурации хосту, удваивая паузу после каждой ошибки
+func (s *HostService) delayAgentConfigRetry(hostID int, now time.Time) {
+	s.agentConfigMu.Lock()
+	defer s.agentConfigMu.Unlock()
+
+	delay := agentConfigRetryMin
+	if retry, ok := s.agentConfigRetries[hostID]; ok {
+		delay = min(retry.delay*2, agentConfigRetryMax)
+	}
+	s.agentConfigRetries[hostID] = agentConfigRetry{next: now.Add(delay), delay: delay}
+}
+
+// AgentConfigStale сообщает, что конфигурацию агента пора отправить заново: она отличается от отправленной
+// (агент потерял сохраненную конфигурацию, ее изменили вручную или ЦМ еще не смог ее отправить),
+// а пауза после предыдущей ошибки отправки истекла
+func (s *HostService) AgentConfigStale(hostID int, hash string) bool {
+	if hash == "" {
+		// Агент не сообщает хеш конфигурации
+		return false
+	}
+
+	s.agentConfigMu.Lock()
+	defer s.agentConfigMu.Unlock()
+	if known, ok := s.agentConfigHashes[hostID]; ok && known == hash {
+		return false
+	}
+	retry, ok := s.agentConfigRetries[hostID]
+	return !ok || !time.Now().Before(retry.next)
 }
 
 // SendProcessConfigurationToAgent отправляет конфигурацию process на агент
@@ -196,13 +425,22 @@ func (s *HostService) SendProcessConfigurationToAgent(ctx context.Context, host
 		return err
 	}
 
+	// processes оставлен для агентов, не поддерживающих правила отбора
 	processNames := make([]string, 0, len(processes))
+	matchers := make([]models.ProcessMatcher, 0, len(processes))
 	for _, p := range processes {
 		processNames = append(processNames, p.ProcessName)
+
+		matcher := models.ProcessMatcher{Alias: p.ProcessName, ProcessMatchSpec: p.ProcessMatchSpec}
+		if matcher.IsEmpty() {
+			matcher.Name = p.ProcessName
+		}
+		matchers = append(matchers, matcher)
 	}
 
 	return s.sendToAgent(ctx, host, "/config/processes", map[string]interface{}{
 		"processes": processNames,
+		"matchers":  matchers,
 	})
 }
 
@@ -214,25 +452,141 @@ func (s *HostService) SendContainerConfigurationToAgent(ctx context.Context, hos
 		return err
 	}
 
+	// containers оставлен для агентов, не поддерживающих правила отбора
 	containerNames := make([]string, 0, len(containers))
+	matchers := make([]models.ContainerMatcher, 0, len(containers))
 	for _, c := range containers {
 		containerNames = append(containerNames, c.ContainerName)
+
+		matcher := models.ContainerMatcher{Alias: c.ContainerName, ContainerMatchSpec: c.ContainerMatchSpec}
+		if matcher.IsEmpty() {
+			matcher.Name = c.ContainerName
+		}
+		matchers = append(matchers, matcher)
 	}
 
 	return s.sendToAgent(ctx, host, "/config/containers", map[string]interface{}{
 		"containers": containerNames,
+		"matchers":   matchers,
+	})
+}
+
+// SendSystemdUnitConfigurationToAgent отправляет список systemd-юнитов на агент
+func (s *HostService) SendSystemdUnitConfigurationToAgent(ctx context.Context, host models.Host) error {
+	units, err := s.SystemdRepo.GetByHostID(ctx, host.ID)
+	if err != nil {
+		return err
+	}
+
+	unitNames := make([]string, 0, len(units))
+	for _, u := range units {
+		unitNames = append(unitNames, u.UnitName)
+	}
+
+	return s.sendToAgent(ctx, host, "/config/units", map[string]interface{}{
+		"units": unitNames,
+	})
+}
+
+// SendProbeConfigurationToAgent отправляет список проверок доступности на агент
+func (s *HostService) SendProbeConfigurationToAgent(ctx context.Context, host models.Host) error {
+	probes, err := s.ProbeRepo.GetByHostID(ctx, host.ID)
+	if err != nil {
+		return err
+	}
+
+	agentProbes := make([]models.AgentProbe, 0, len(probes))
+	for _, p := range probes {
+		agentProbes = append(agentProbes, models.AgentProbe{
+			Name:           p.Name,
+			Type:           p.Type,
+			Target:         p.Target,
+			ExpectedStatus: p.ExpectedStatus,
+			BodyRegex:      p.BodyRegex,
+			Timeout:        time.Duration(p.TimeoutSeconds) * time.Second,
+			TLSSkipVerify:  p.TLSSkipVerify,
+		})
+	}
+
+	return s.sendToAgent(ctx, host, "/config/probes", map[string]interface{}{
+		"probes": agentProbes,
+	})
+}
+
+// SendCollectorConfigurationToAgent отправляет на агент включение коллекторов и их частей
+func (s *HostService) SendCollectorConfigurationToAgent(ctx context.Context, host models.Host) error {
+	if s.collectorSettings == nil {
+		return nil
+	}
+	return s.sendToAgent(ctx, host, "/config/collectors", map[string]interface{}{
+		"collectors": s.collectorSettings,
 	})
 }
 
+// SendCollectorConfigsToAgent отправляет на агент сохраненные настройки коллекторов.
+// Ошибка одного коллектора не мешает отправке остальных
+func (s *HostService) SendCollectorConfigsToAgent(ctx context.Context, host models.Host) error {
+	configs, err := s.CollectorConfigRepo.GetByHostID(ctx, host.ID)
+	if err != nil {
+		return err
+	}
+	return s.sendCollectorConfigs(ctx, host, configs)
+}
+
+// sendCollectorConfigs отправляет настройки коллекторов на агент. Списки процессов, контейнеров и юнитов
+// уже отправлены из таблиц хоста, поэтому из настроек, сохраненных до их исключения, они убираются
+func (s *HostService) sendCollectorConfigs(ctx context.Context, host models.Host, configs []models.CollectorConfig) error {
+	var errs []error
+	for _, c := range configs {
+		config, err := stripCollectorListFields(c.Collector, c.Config)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("collector %s: %w", c.Collector, err))
+			continue
+		}
+		if err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/"+c.Collector, config, nil); err != nil {
+			errs = append(errs, fmt.Errorf("collector %s: %w", c.Collector, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetchFromAgent запрашивает данные у агента
+func (s *HostService) fetchFromAgent(ctx context.Context, host models.Host, endpoint string, out interface{}) error {
+	url := fmt.Sprintf("http://%s:%d%s", host.IPAddress, host.AgentPort, endpoint)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agent returned status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 // sendToAgent отправляет данные на агент
 func (s *HostService) sendToAgent(ctx context.Context, host models.Host, endpoint string, data interface{}) error {
+	return s.requestAgent(ctx, host, http.MethodPost, endpoint, data, nil)
+}
+
+// requestAgent отправляет данные на агент указанным методом и, если out не nil, разбирает ответ в out.
+// Отказ агента в ответ на неверные данные возвращается как ValidationError, сбой связи или агента - как AgentError;
+// текст ошибки агента включается в ошибку
+func (s *HostService) requestAgent(ctx context.Context, host models.Host, method, endpoint string, data, out interface{}) error {
 	url := fmt.Sprintf("http://%s:%d%s", host.IPAddress, host.AgentPort, endpoint)
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
@@ -240,13 +594,29 @@ func (s *HostService) sendToAgent(ctx context.Context, host models.Host, endpoin
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return &AgentError{Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("agent returned status %d", resp.StatusCode)
+		var body struct {
+			Message string `json:"message"`
+		}
+		err := fmt.Errorf("agent returned status %d", resp.StatusCode)
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Message != "" {
+			err = fmt.Errorf("agent returned status %d: %s", resp.StatusCode, body.Message)
+		}
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return &ValidationError{Err: err}
+		}
+		return &AgentError{Err: err}
 	}
 
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return &AgentError{Err: fmt.Errorf("invalid agent response: %w", err)}
+	}
 	return nil
 }
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"center/internal/database/mongodb/repositories"
+	pg_repo "center/internal/database/postgres/repositories"
+	"center/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testAgentHost - хост, указывающий на тестовый сервер агента
+func testAgentHost(t *testing.T, addr string) models.Host {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.Host{ID: 1, IPAddress: host, AgentPort: p}
+}
+
+func TestRequestAgent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/config/collectors/system":
+			// Агент возвращает настройки, объединенные с прежними
+			w.Write([]byte(`{"status":"success","config":{"mountpoints":["/"],"cpu":true}}`))
+		case "/config/collectors/bad":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","message":"cpu: expected boolean"}`))
+		case "/config/collectors/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"status":"error","message":"internal"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s := &HostService{}
+	ctx := context.Background()
+	host := testAgentHost(t, srv.Listener.Addr().String())
+
+	var resp struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/system", map[string]bool{"cpu": true}, &resp); err != nil {
+		t.Fatalf("requestAgent: %v", err)
+	}
+	if string(resp.Config) != `{"mountpoints":["/"],"cpu":true}` {
+		t.Errorf("config = %s", resp.Config)
+	}
+
+	var validationErr *ValidationError
+	err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/bad", nil, nil)
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "cpu: expected boolean") {
+		t.Errorf("rejected config: err = %v, want ValidationError with agent message", err)
+	}
+
+	var agentErr *AgentError
+	if err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/broken", nil, nil); !errors.As(err, &agentErr) {
+		t.Errorf("agent failure: err = %v, want AgentError", err)
+	}
+
+	srv.Close()
+	if err := s.requestAgent(ctx, host, http.MethodPut, "/config/collectors/system", nil, nil); !errors.As(err, &agentErr) {
+		t.Errorf("agent down: err = %v, want AgentError", err)
+	}
+}
+
+func TestAgentConfigStaleRetry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hash":"abc"}`))
+	}))
+	defer srv.Close()
+
+	s := NewHostService(pg_repo.PostgresHostRepository{}, pg_repo.PostgresProcessRepository{},
+		pg_repo.PostgresContainerRepository{}, pg_repo.PostgresSystemdUnitRepository{},
+		pg_repo.PostgresProbeRepository{}, pg_repo.PostgresAlertRepository{},
+		repositories.MongoMetricRepository{}, pg_repo.PostgresCollectorConfigRepository{})
+	host := testAgentHost(t, srv.Listener.Addr().String())
+
+	if s.AgentConfigStale(host.ID, "") {
+		t.Errorf("agent without config hash reported stale")
+	}
+	if !s.AgentConfigStale(host.ID, "abc") {
+		t.Errorf("config that was never sent is not stale")
+	}
+
+	// После ошибки отправки повтор откладывается, пауза удваивается до предела
+	now := time.Now()
+	s.delayAgentConfigRetry(host.ID, now)
+	if s.AgentConfigStale(host.ID, "abc") {
+		t.Errorf("config resent before the retry delay")
+	}
+	for i := 0; i < 10; i++ {
+		s.delayAgentConfigRetry(host.ID, now)
+	}
+	if retry := s.agentConfigRetries[host.ID]; retry.delay != agentConfigRetryMax || !retry.next.Equal(now.Add(agentConfigRetryMax)) {
+		t.Errorf("retry = %+v, want delay %v", retry, agentConfigRetryMax)
+	}
+	s.agentConfigRetries[host.ID] = agentConfigRetry{next: now.Add(-time.Second), delay: agentConfigRetryMax}
+	if !s.AgentConfigStale(host.ID, "abc") {
+		t.Errorf("config not resent after the retry delay")
+	}
+
+	// Успешная отправка запоминает хеш агента и сбрасывает паузу
+	s.rememberAgentConfigHash(context.Background(), host)
+	if s.AgentConfigStale(host.ID, "abc") {
+		t.Errorf("config reported stale after a successful send")
+	}
+	if _, ok := s.agentConfigRetries[host.ID]; ok {
+		t.Errorf("retry delay kept after a successful send")
+	}
+	if !s.AgentConfigStale(host.ID, "other") {
+		t.Errorf("changed agent config is not stale")
+	}
+}
+
+// fakeProcessAgent - агент, хранящий правила отбора процессов, как коллектор process
+type fakeProcessAgent struct {
+	mu       sync.Mutex
+	matchers []models.ProcessMatcher
+}
+
+func (a *fakeProcessAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var body struct {
+		Matchers *[]models.ProcessMatcher `json:"matchers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch r.URL.Path {
+	case "/config/processes", "/config/collectors/process":
+		// Не указанные поля не меняются
+		if body.Matchers != nil {
+			a.matchers = *body.Matchers
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"config": map[string]interface{}{"matchers": a.matchers},
+	})
+}
+
+func TestCollectorConfigDoesNotOverrideProcessList(t *testing.T) {
+	agent := &fakeProcessAgent{}
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	s := &HostService{}
+	ctx := context.Background()
+	host := testAgentHost(t, srv.Listener.Addr().String())
+
+	// Правила отбора задаются только списком процессов хоста
+	var validationErr *ValidationError
+	_, err := s.SetCollectorConfig(ctx, host, "process", json.RawMessage(`{"matchers": [{"name": "cron"}]}`))
+	if !errors.As(err, &validationErr) {
+		t.Errorf("SetCollectorConfig with matchers: err = %v, want ValidationError", err)
+	}
+
+	// Итоговые настройки агента сохраняются без списка
+	stored, err := stripCollectorListFields("process", json.RawMessage(`{"matchers": [{"name": "cron"}]}`))
+	if err != nil || string(stored) != `{}` {
+		t.Errorf("stored config = %s, %v", stored, err)
+	}
+
+	// Процесс добавлен после сохранения настроек: снимок, сохраненный до исправления, не возвращает старый список
+	if err := s.sendToAgent(ctx, host, "/config/processes", map[string]interface{}{
+		"matchers": []models.ProcessMatcher{{Alias: "nginx", ProcessMatchSpec: models.ProcessMatchSpec{Name: "nginx"}}},
+	}); err != nil {
+		t.Fatalf("send processes: %v", err)
+	}
+	configs := []models.CollectorConfig{{HostID: host.ID, Collector: "process", Config: json.RawMessage(`{"matchers": [{"name": "cron"}]}`)}}
+	if err := s.sendCollectorConfigs(ctx, host, configs); err != nil {
+		t.Fatalf("sendCollectorConfigs: %v", err)
+	}
+	if len(agent.matchers) != 1 || agent.matchers[0].Alias != "nginx" {
+		t.Errorf("agent matchers = %+v, want the host process list", agent.matchers)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"center/internal/services"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollectorConfigHandler struct {
+	service *services.HostService
+}
+
+func NewCollectorConfigHandler(service *services.HostService) *CollectorConfigHandler {
+	return &CollectorConfigHandler{service: service}
+}
+
+// GetConfigsByHostID
+// @Summary Получить настройки коллекторов хоста
+// @Description Возвращает сохраненные типизированные настройки коллекторов агента
+// @Tags Collectors
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Success 200 {array} models.CollectorConfig
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/collectors [get]
+func (h *CollectorConfigHandler) GetConfigsByHostID(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	configs, err := h.service.CollectorConfigRepo.GetByHostID(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, configs)
+}
+
+// SetConfig
+// @Summary Изменить настройки коллектора
+// @Description Отправляет настройки коллектора на агент и сохраняет итоговые настройки, если агент принял их по схеме коллектора.
+// @Description Не указанные поля сохраняют прежние значения
+// @Description Правила отбора process и container и юниты systemd задаются списками хоста (/hosts/{id}/processes, /containers, /systemd) и здесь не принимаются
+// @Description Схема и текущие настройки доступны на агенте: GET /config/collectors/{name}
+// @Tags Collectors
+// @Accept json
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Param name path string true "Имя коллектора (system, process, container, network, systemd, cgroups)"
+// @Param config body object true "Настройки коллектора"
+// @Success 200 {object} models.CollectorConfig
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string "Агент недоступен"
+// @Router /hosts/{id}/collectors/{name} [put]
+func (h *CollectorConfigHandler) SetConfig(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	host, err := h.service.GetHost(ctx, hostID)
+	if err != nil || host == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Host not found"})
+		return
+	}
+
+	cfg, err := h.service.SetCollectorConfig(ctx, *host, c.Param("name"), body)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// DeleteConfig
+// @Summary Удалить настройки коллектора
+// @Description Удаляет сохраненные настройки, и ЦМ перестает отправлять их на агент. Сброса на агенте нет:
+// @Description агент хранит полученные настройки в runtime_config.json и продолжает использовать их и после перезапуска.
+// @Description Другие значения задаются через PUT, возврат к config.yml описан в readme агента
+// @Tags Collectors
+// @Param id path int true "ID хоста"
+// @Param name path string true "Имя коллектора"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/collectors/{name} [delete]
+func (h *CollectorConfigHandler) DeleteConfig(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.CollectorConfigRepo.Delete(ctx, hostID, c.Param("name")); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Collector config not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -46,7 +46,7 @@ func (h *ContainerHandler) GetContainersByHostID(c *gin.Context) {
 
 // CreateContainer
 // @Summary Добавить контейнер для мониторинга
-// @Description Добавляет новый контейнер для мониторинга на указанном хосте
+// @Description Добавляет новый контейнер для мониторинга на указанном хосте. Помимо точного имени контейнеры можно отбирать по меткам (com.docker.compose.project=billing), шаблону образа и regex имени
 // @Tags Containers
 // @Accept json
 // @Produce json
@@ -70,10 +70,10 @@ func (h *ContainerHandler) CreateContainer(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	id, err := h.service.AddContainer(ctx, hostID, containerInput.ContainerName)
+	id, err := h.service.AddContainer(ctx, hostID, containerInput)
 	//log.Println("-----------------------------", err)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -44,11 +44,11 @@ func (h *HostHandler) GetHosts(c *gin.Context) {
 
 // GetHostByID возвращает информацию о конкретном хосте
 // @Summary Получить хост по ID
-// @Description Возвращает информацию о хосте по его ID
+// @Description Возвращает информацию о хосте по его ID вместе со сведениями об ОС, ядре и оборудовании
 // @Tags Hosts
 // @Produce json
 // @Param id path int true "ID хоста"
-// @Success 200 {object} models.Host
+// @Success 200 {object} models.HostDetails
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /hosts/{id} [get]
@@ -60,7 +60,7 @@ func (h *HostHandler) GetHostByID(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	host, err := h.service.GetHost(ctx, id)
+	host, err := h.service.GetHostDetails(ctx, id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Host not found"})
 		return
@@ -68,6 +68,32 @@ func (h *HostHandler) GetHostByID(c *gin.Context) {
 	c.JSON(http.StatusOK, host)
 }
 
+// GetInventoryHistory возвращает историю изменений сведений о хосте
+// @Summary Получить историю изменений сведений о хосте
+// @Description Возвращает изменения ОС, ядра, оборудования, адресов и времени загрузки хоста, новые первыми
+// @Tags Hosts
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Success 200 {array} models.InventoryChange
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/inventory/history [get]
+func (h *HostHandler) GetInventoryHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	changes, err := h.service.MetricRepo.GetInventoryHistory(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
 // CreateHost создает новый хост
 // @Summary Создать новый хост
 // @Description Добавляет новый хост в систему
@@ -190,6 +190,238 @@ func (h *MetricHandler) GetContainerMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetSystemdUnitMetrics
+// @Summary Получить состояние systemd-юнитов
+// @Description Возвращает состояние отслеживаемых systemd-юнитов для указанного хоста
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.SystemdUnitMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/units [get]
+func (h *MetricHandler) GetSystemdUnitMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetSystemdUnitMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetLogMetrics
+// @Summary Получить счетчики журналов
+// @Description Возвращает число строк журналов, совпавших с шаблонами, за каждый интервал сбора агента
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.LogMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/logs [get]
+func (h *MetricHandler) GetLogMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetLogMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetCustomMetrics
+// @Summary Получить показатели плагинов
+// @Description Возвращает показатели внешних плагинов агента (раздел custom) для указанного хоста
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.CustomMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/custom [get]
+func (h *MetricHandler) GetCustomMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetCustomMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetSensorMetrics
+// @Summary Получить показания датчиков
+// @Description Возвращает температуры, обороты вентиляторов и напряжения с аппаратных датчиков хоста
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.SensorMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/sensors [get]
+func (h *MetricHandler) GetSensorMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetSensorMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetCollectorStatus
+// @Summary Получить состояние коллекторов агента
+// @Description Возвращает результаты запусков коллекторов агента: ok, timeout или error с сообщением
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.CollectorStatusMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/collectors [get]
+func (h *MetricHandler) GetCollectorStatus(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetCollectorStatusInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetAuthMetrics
+// @Summary Получить сеансы и входы пользователей
+// @Description Возвращает сеансы пользователей и число успешных и неудачных входов по SSH за каждый интервал сбора
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.AuthMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/auth [get]
+func (h *MetricHandler) GetAuthMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetAuthMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetProbeMetrics
+// @Summary Получить результаты проверок доступности
+// @Description Возвращает доступность, время ответа, HTTP-код и срок действия сертификата локальных сервисов хоста
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.ProbeMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/probes [get]
+func (h *MetricHandler) GetProbeMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetProbeMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// GetCgroupMetrics
+// @Summary Получить метрики cgroup
+// @Description Возвращает метрики cgroup v2 (CPU, троттлинг, память, IO, pids) для указанного хоста
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Success 200 {array} models.CgroupMetrics
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/cgroups [get]
+func (h *MetricHandler) GetCgroupMetrics(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	metrics, err := h.service.MetricRepo.GetCgroupMetricsInRange(ctx, hostID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
 // GetNetworkMetrics
 // @Summary Получить сетевые метрики
 // @Description Возвращает сетевые метрики для указанного хоста
@@ -225,6 +457,66 @@ func (h *MetricHandler) GetNetworkMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetEvents
+// @Summary Получить события хоста
+// @Description Возвращает события хоста (перезапуски процессов и т.п.), при необходимости отфильтрованные по источнику
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Param source query string false "Источник событий (process, container, ...)"
+// @Success 200 {array} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/events [get]
+func (h *MetricHandler) GetEvents(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-5*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	events, err := h.service.MetricRepo.GetEventsInRange(ctx, hostID, c.Query("source"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// GetSecurityEvents
+// @Summary Получить события безопасности хоста
+// @Description Возвращает события безопасности хоста (изменения контролируемых файлов и т.п.), при необходимости отфильтрованные по источнику
+// @Tags Metrics
+// @Produce json
+// @Param host_id path int true "ID хоста"
+// @Param source query string false "Источник событий (fim, ...)"
+// @Success 200 {array} models.Event
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /metrics/{host_id}/security [get]
+func (h *MetricHandler) GetSecurityEvents(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("host_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	from, to := time.Now().Add(time.Duration(-14*24)*time.Hour), time.Now()
+
+	ctx := c.Request.Context()
+	events, err := h.service.MetricRepo.GetSecurityEventsInRange(ctx, hostID, c.Query("source"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
 // GetMetrics возвращает агрегированные метрики по всем хостам
 // @Summary Получить все метрики
 // @Description Возвращает агрегированные метрики по всем хостам
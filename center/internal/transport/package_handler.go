@@ -0,0 +1,76 @@
+package api
+
+import (
+	"center/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PackageHandler struct {
+	service *services.HostService
+}
+
+func NewPackageHandler(service *services.HostService) *PackageHandler {
+	return &PackageHandler{service: service}
+}
+
+// FindPackageHosts
+// @Summary Найти хосты с установленным пакетом
+// @Description Возвращает хосты, на которых установлен пакет. С параметром version остаются версии, удовлетворяющие условию op (например, openssl с op=lt и version=3.0.2). Версии сравниваются по правилам dpkg или rpm хоста
+// @Tags Packages
+// @Produce json
+// @Param name query string true "Имя пакета"
+// @Param op query string false "Условие: lt, le, eq, ne, ge, gt (по умолчанию eq)"
+// @Param version query string false "Версия для сравнения"
+// @Success 200 {array} models.PackageHost
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /packages [get]
+func (h *PackageHandler) FindPackageHosts(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Package name is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	hosts, err := h.service.FindHostsWithPackage(ctx, name, c.Query("op"), c.Query("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, hosts)
+}
+
+// GetHostPackages
+// @Summary Получить список пакетов хоста
+// @Description Возвращает последний известный список установленных пакетов хоста
+// @Tags Packages
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Success 200 {object} models.HostPackages
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/packages [get]
+func (h *PackageHandler) GetHostPackages(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	packages, err := h.service.MetricRepo.GetHostPackages(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if packages == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Package list not received yet"})
+		return
+	}
+	c.JSON(http.StatusOK, packages)
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"center/internal/models"
+	"center/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProbeHandler struct {
+	service *services.HostService
+}
+
+func NewProbeHandler(service *services.HostService) *ProbeHandler {
+	return &ProbeHandler{service: service}
+}
+
+// GetProbesByHostID
+// @Summary Получить проверки доступности для хоста
+// @Description Возвращает все проверки доступности локальных сервисов указанного хоста
+// @Tags Probes
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Success 200 {array} models.Probe
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/probes [get]
+func (h *ProbeHandler) GetProbesByHostID(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	probes, err := h.service.ProbeRepo.GetByHostID(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, probes)
+}
+
+// CreateProbe
+// @Summary Добавить проверку доступности
+// @Description Добавляет HTTP-, TCP- или Unix-проверку локального сервиса и отправляет список проверок на агент
+// @Tags Probes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Param probe body models.ProbeInput true "Описание проверки"
+// @Success 201 {object} map[string]int "ID созданной проверки"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/probes [post]
+func (h *ProbeHandler) CreateProbe(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	var input models.ProbeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id, err := h.service.AddProbe(ctx, hostID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	host, err := h.service.GetHost(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SendProbeConfigurationToAgent(ctx, *host); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// DeleteProbe
+// @Summary Удалить проверку доступности
+// @Description Удаляет проверку и отправляет обновленный список проверок на агент
+// @Tags Probes
+// @Param id path int true "ID хоста"
+// @Param probe_id path int true "ID проверки"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/probes/{probe_id} [delete]
+func (h *ProbeHandler) DeleteProbe(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	probeID, err := strconv.Atoi(c.Param("probe_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid probe ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.ProbeRepo.Delete(ctx, probeID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Агент хранит список целиком, поэтому после удаления отправляем его заново
+	if host, err := h.service.GetHost(ctx, hostID); err == nil && host != nil {
+		if err := h.service.SendProbeConfigurationToAgent(ctx, *host); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
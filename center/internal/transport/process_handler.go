@@ -3,6 +3,7 @@ package api
 import (
 	"center/internal/models"
 	"center/internal/services"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -45,7 +46,7 @@ func (h *ProcessHandler) GetProcessesByHostID(c *gin.Context) {
 
 // CreateProcess
 // @Summary Добавить процесс для мониторинга
-// @Description Добавляет новый процесс для мониторинга на указанном хосте
+// @Description Добавляет новый процесс для мониторинга на указанном хосте. process_name - отображаемое имя; условия отбора (name_regex, cmdline, cmdline_regex, user, exe, cgroup, systemd_unit) необязательны
 // @Tags Processes
 // @Accept json
 // @Produce json
@@ -69,9 +70,9 @@ func (h *ProcessHandler) CreateProcess(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	id, err := h.service.AddProcess(ctx, hostID, processInput.ProcessName)
+	id, err := h.service.AddProcess(ctx, hostID, processInput)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	host, err := h.service.GetHost(ctx, hostID)
@@ -81,6 +82,7 @@ func (h *ProcessHandler) CreateProcess(c *gin.Context) {
 	}
 	if err = h.service.SendProcessConfigurationToAgent(ctx, *host); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"id": id})
 }
@@ -116,3 +118,17 @@ func (h *ProcessHandler) DeleteProcess(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// serviceErrorStatus подбирает HTTP-код для ошибки сервиса: 400 для неверных входных данных,
+// 502 для сбоя связи с агентом, 500 для остальных
+func serviceErrorStatus(err error) int {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	var agentErr *services.AgentError
+	if errors.As(err, &agentErr) {
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
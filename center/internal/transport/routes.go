@@ -10,7 +10,11 @@ type Handler struct {
 	MetricHandler    *MetricHandler
 	ProcessHandler   *ProcessHandler
 	ContainerHandler *ContainerHandler
+	SystemdHandler   *SystemdUnitHandler
+	ProbeHandler     *ProbeHandler
+	PackageHandler   *PackageHandler
 	AlertHandler     *AlertHandler
+	CollectorHandler *CollectorConfigHandler
 }
 
 func SetupRoutes(router *gin.Engine, handler *Handler) {
@@ -22,6 +26,8 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 		{
 			hosts.GET("", handler.HostHandler.GetHosts)
 			hosts.GET("/:id", handler.HostHandler.GetHostByID)
+			hosts.GET("/:id/inventory/history", handler.HostHandler.GetInventoryHistory)
+			hosts.GET("/:id/packages", handler.PackageHandler.GetHostPackages)
 			hosts.POST("", handler.HostHandler.CreateHost)
 			hosts.PUT("/:id", handler.HostHandler.UpdateHost)
 			hosts.DELETE("/:id", handler.HostHandler.DeleteHost)
@@ -37,6 +43,17 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			hosts.GET("/:id/containers", handler.ContainerHandler.GetContainersByHostID)
 			hosts.POST("/:id/containers", handler.ContainerHandler.CreateContainer)
 			hosts.DELETE("/:id/containers/:container_id", handler.ContainerHandler.DeleteContainer)
+			hosts.GET("/:id/units", handler.SystemdHandler.GetUnitsByHostID)
+			hosts.POST("/:id/units", handler.SystemdHandler.CreateUnit)
+			hosts.DELETE("/:id/units/:unit_id", handler.SystemdHandler.DeleteUnit)
+			hosts.GET("/:id/probes", handler.ProbeHandler.GetProbesByHostID)
+			hosts.POST("/:id/probes", handler.ProbeHandler.CreateProbe)
+			hosts.DELETE("/:id/probes/:probe_id", handler.ProbeHandler.DeleteProbe)
+
+			// Настройки коллекторов агента
+			hosts.GET("/:id/collectors", handler.CollectorHandler.GetConfigsByHostID)
+			hosts.PUT("/:id/collectors/:name", handler.CollectorHandler.SetConfig)
+			hosts.DELETE("/:id/collectors/:name", handler.CollectorHandler.DeleteConfig)
 
 			// Правила оповещений хоста
 			hosts.GET("/:id/alerts", handler.AlertHandler.GetAlertsByHostID)
@@ -54,9 +71,22 @@ func SetupRoutes(router *gin.Engine, handler *Handler) {
 			metrics.GET("/:host_id/system", handler.MetricHandler.GetSystemMetrics)
 			metrics.GET("/:host_id/processes", handler.MetricHandler.GetProcessMetrics)
 			metrics.GET("/:host_id/containers", handler.MetricHandler.GetContainerMetrics)
+			metrics.GET("/:host_id/cgroups", handler.MetricHandler.GetCgroupMetrics)
+			metrics.GET("/:host_id/units", handler.MetricHandler.GetSystemdUnitMetrics)
+			metrics.GET("/:host_id/logs", handler.MetricHandler.GetLogMetrics)
+			metrics.GET("/:host_id/custom", handler.MetricHandler.GetCustomMetrics)
+			metrics.GET("/:host_id/sensors", handler.MetricHandler.GetSensorMetrics)
+			metrics.GET("/:host_id/collectors", handler.MetricHandler.GetCollectorStatus)
+			metrics.GET("/:host_id/auth", handler.MetricHandler.GetAuthMetrics)
+			metrics.GET("/:host_id/probes", handler.MetricHandler.GetProbeMetrics)
 			metrics.GET("/:host_id/network", handler.MetricHandler.GetNetworkMetrics)
+			metrics.GET("/:host_id/events", handler.MetricHandler.GetEvents)
+			metrics.GET("/:host_id/security", handler.MetricHandler.GetSecurityEvents)
 		}
 
+		// Поиск хостов по установленным пакетам
+		api.GET("/packages", handler.PackageHandler.FindPackageHosts)
+
 		// Проверка состояния системы
 		api.GET("/health", handler.MetricHandler.GetHealth)
 	}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"center/internal/models"
+	"center/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SystemdUnitHandler struct {
+	service *services.HostService
+}
+
+func NewSystemdUnitHandler(service *services.HostService) *SystemdUnitHandler {
+	return &SystemdUnitHandler{service: service}
+}
+
+// GetUnitsByHostID
+// @Summary Получить systemd-юниты для хоста
+// @Description Возвращает все отслеживаемые systemd-юниты указанного хоста
+// @Tags Systemd
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Success 200 {array} models.SystemdUnit
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/units [get]
+func (h *SystemdUnitHandler) GetUnitsByHostID(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	units, err := h.service.SystemdRepo.GetByHostID(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, units)
+}
+
+// CreateUnit
+// @Summary Добавить systemd-юнит для мониторинга
+// @Description Добавляет юнит (nginx.service) или шаблон юнитов (php*-fpm.service) и отправляет список на агент
+// @Tags Systemd
+// @Accept json
+// @Produce json
+// @Param id path int true "ID хоста"
+// @Param unit body models.SystemdUnitInput true "Имя или шаблон юнита"
+// @Success 201 {object} map[string]int "ID созданной записи"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/units [post]
+func (h *SystemdUnitHandler) CreateUnit(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	var input models.SystemdUnitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id, err := h.service.AddSystemdUnit(ctx, hostID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	host, err := h.service.GetHost(ctx, hostID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SendSystemdUnitConfigurationToAgent(ctx, *host); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// DeleteUnit
+// @Summary Удалить systemd-юнит из мониторинга
+// @Description Удаляет юнит из списка мониторинга и отправляет обновленный список на агент
+// @Tags Systemd
+// @Param id path int true "ID хоста"
+// @Param unit_id path int true "ID записи юнита"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /hosts/{id}/units/{unit_id} [delete]
+func (h *SystemdUnitHandler) DeleteUnit(c *gin.Context) {
+	hostID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid host ID"})
+		return
+	}
+
+	unitID, err := strconv.Atoi(c.Param("unit_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unit ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.SystemdRepo.Delete(ctx, unitID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Агент хранит список целиком, поэтому после удаления отправляем его заново
+	if host, err := h.service.GetHost(ctx, hostID); err == nil && host != nil {
+		if err := h.service.SendSystemdUnitConfigurationToAgent(ctx, *host); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}